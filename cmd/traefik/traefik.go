@@ -23,6 +23,7 @@ import (
 	"github.com/traefik/traefik/v2/cmd"
 	"github.com/traefik/traefik/v2/cmd/healthcheck"
 	cmdVersion "github.com/traefik/traefik/v2/cmd/version"
+	"github.com/traefik/traefik/v2/pkg/api"
 	tcli "github.com/traefik/traefik/v2/pkg/cli"
 	"github.com/traefik/traefik/v2/pkg/collector"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
@@ -34,11 +35,13 @@ import (
 	"github.com/traefik/traefik/v2/pkg/pilot"
 	"github.com/traefik/traefik/v2/pkg/provider/acme"
 	"github.com/traefik/traefik/v2/pkg/provider/aggregator"
+	"github.com/traefik/traefik/v2/pkg/provider/ca"
 	"github.com/traefik/traefik/v2/pkg/provider/traefik"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/server"
 	"github.com/traefik/traefik/v2/pkg/server/middleware"
 	"github.com/traefik/traefik/v2/pkg/server/service"
+	"github.com/traefik/traefik/v2/pkg/spiffe"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
 	"github.com/traefik/traefik/v2/pkg/types"
 	"github.com/traefik/traefik/v2/pkg/version"
@@ -163,6 +166,9 @@ func runCmd(staticConfiguration *static.Configuration) error {
 	return nil
 }
 
+// spiffeReadyTimeout is the maximum duration to wait on start for the SVID delivered by the SPIFFE Workload API.
+const spiffeReadyTimeout = 30 * time.Second
+
 func setupServer(staticConfiguration *static.Configuration) (*server.Server, error) {
 	providerAggregator := aggregator.NewProviderAggregator(*staticConfiguration.Providers)
 
@@ -178,28 +184,22 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 	// ACME
 
 	tlsManager := traefiktls.NewManager()
-	httpChallengeProvider := acme.NewChallengeHTTP()
+
+	acmeKVStores := initACMEKVStores(staticConfiguration)
+	challengeStore := acme.NewLocalChallengeStore()
+
+	httpChallengeProvider := acme.NewChallengeHTTP(challengeStore)
 
 	// we need to wait at least 2 times the ProvidersThrottleDuration to be sure to handle the challenge.
-	tlsChallengeProvider := acme.NewChallengeTLSALPN(time.Duration(staticConfiguration.Providers.ProvidersThrottleDuration) * 2)
+	tlsChallengeProvider := acme.NewChallengeTLSALPN(time.Duration(staticConfiguration.Providers.ProvidersThrottleDuration)*2, challengeStore)
 	err = providerAggregator.AddProvider(tlsChallengeProvider)
 	if err != nil {
 		return nil, err
 	}
 
-	acmeProviders := initACMEProvider(staticConfiguration, &providerAggregator, tlsManager, httpChallengeProvider, tlsChallengeProvider)
-
-	// Entrypoints
-
-	serverEntryPointsTCP, err := server.NewTCPEntryPoints(staticConfiguration.EntryPoints)
-	if err != nil {
-		return nil, err
-	}
+	acmeProviders := initACMEProvider(staticConfiguration, &providerAggregator, tlsManager, acmeKVStores, httpChallengeProvider, tlsChallengeProvider)
 
-	serverEntryPointsUDP, err := server.NewUDPEntryPoints(staticConfiguration.EntryPoints)
-	if err != nil {
-		return nil, err
-	}
+	caProviders := initCAProviders(staticConfiguration, &providerAggregator)
 
 	// Pilot
 
@@ -247,18 +247,54 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		metricRegistries = append(metricRegistries, pilotRegistry)
 	}
 	metricsRegistry := metrics.NewMultiRegistry(metricRegistries)
+	log.SetDroppedLinesCounter(metricsRegistry.LogDroppedLinesCounter())
+
+	// Entrypoints
+
+	serverEntryPointsTCP, err := server.NewTCPEntryPoints(staticConfiguration.EntryPoints, metricsRegistry)
+	if err != nil {
+		return nil, err
+	}
+
+	serverEntryPointsUDP, err := server.NewUDPEntryPoints(staticConfiguration.EntryPoints)
+	if err != nil {
+		return nil, err
+	}
+
+	// SPIFFE
+
+	var spiffeX509Source spiffe.Source
+	if staticConfiguration.Spiffe != nil {
+		source, err := spiffe.NewX509Source(staticConfiguration.Spiffe.WorkloadAPIAddr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create SPIFFE X.509 source: %w", err)
+		}
+
+		routinesPool.GoCtx(source.Watch)
+
+		log.WithoutContext().Info("Waiting on SPIFFE SVID delivery")
+		readyCtx, cancel := context.WithTimeout(ctx, spiffeReadyTimeout)
+		err = source.WaitUntilReady(readyCtx)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("unable to obtain SPIFFE SVID from the Workload API within %s, check that the SPIFFE agent is running and that its Workload API is reachable: %w", spiffeReadyTimeout, err)
+		}
+		log.WithoutContext().Info("Successfully obtained SPIFFE SVID")
+
+		spiffeX509Source = source
+	}
 
 	// Service manager factory
 
-	roundTripperManager := service.NewRoundTripperManager()
+	roundTripperManager := service.NewRoundTripperManager(spiffeX509Source)
 	acmeHTTPHandler := getHTTPChallengeHandler(acmeProviders, httpChallengeProvider)
-	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, metricsRegistry, roundTripperManager, acmeHTTPHandler)
+	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, metricsRegistry, roundTripperManager, acmeHTTPHandler, getACMEResolvers(acmeProviders), getCASigners(caProviders))
 
 	// Router factory
 
 	accessLog := setupAccessLog(staticConfiguration.AccessLog)
 	chainBuilder := middleware.NewChainBuilder(*staticConfiguration, metricsRegistry, accessLog)
-	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, chainBuilder, pluginBuilder, metricsRegistry)
+	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, chainBuilder, pluginBuilder, metricsRegistry, accessLog)
 
 	// Watcher
 
@@ -316,6 +352,12 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		watcher.AddListener(p.ListenConfiguration)
 	}
 
+	// CA
+	for _, p := range caProviders {
+		resolverNames[p.ResolverName] = struct{}{}
+		watcher.AddListener(p.ListenConfiguration)
+	}
+
 	// Certificate resolver logs
 	watcher.AddListener(func(config dynamic.Configuration) {
 		for rtName, rt := range config.HTTP.Routers {
@@ -343,6 +385,22 @@ func getHTTPChallengeHandler(acmeProviders []*acme.Provider, httpChallengeProvid
 	return acmeHTTPHandler
 }
 
+func getACMEResolvers(acmeProviders []*acme.Provider) map[string]api.ACMEResolver {
+	resolvers := make(map[string]api.ACMEResolver)
+	for _, p := range acmeProviders {
+		resolvers[p.ResolverName] = p
+	}
+	return resolvers
+}
+
+func getCASigners(caProviders []*ca.Provider) map[string]api.CASigner {
+	signers := make(map[string]api.CASigner)
+	for _, p := range caProviders {
+		signers[p.ResolverName] = p
+	}
+	return signers
+}
+
 func getDefaultsEntrypoints(staticConfiguration *static.Configuration) []string {
 	var defaultEntryPoints []string
 	for name, cfg := range staticConfiguration.EntryPoints {
@@ -376,8 +434,29 @@ func switchRouter(routerFactory *server.RouterFactory, serverEntryPointsTCP serv
 	}
 }
 
+// initACMEKVStores creates the KV stores of the ACME resolvers sharing their data between several instances.
+func initACMEKVStores(c *static.Configuration) map[string]*acme.KVStore {
+	kvStores := map[string]*acme.KVStore{}
+
+	for name, resolver := range c.CertificatesResolvers {
+		if resolver.ACME == nil || resolver.ACME.KVStorage == nil {
+			continue
+		}
+
+		kvStore, err := acme.NewKVStore(context.Background(), resolver.ACME.KVStorage)
+		if err != nil {
+			log.WithoutContext().Errorf("The ACME resolver %q is skipped from the resolvers list because: %v", name, err)
+			continue
+		}
+
+		kvStores[name] = kvStore
+	}
+
+	return kvStores
+}
+
 // initACMEProvider creates an acme provider from the ACME part of globalConfiguration.
-func initACMEProvider(c *static.Configuration, providerAggregator *aggregator.ProviderAggregator, tlsManager *traefiktls.Manager, httpChallengeProvider, tlsChallengeProvider challenge.Provider) []*acme.Provider {
+func initACMEProvider(c *static.Configuration, providerAggregator *aggregator.ProviderAggregator, tlsManager *traefiktls.Manager, kvStores map[string]*acme.KVStore, httpChallengeProvider *acme.ChallengeHTTP, tlsChallengeProvider *acme.ChallengeTLSALPN) []*acme.Provider {
 	localStores := map[string]*acme.LocalStore{}
 
 	var resolvers []*acme.Provider
@@ -386,16 +465,32 @@ func initACMEProvider(c *static.Configuration, providerAggregator *aggregator.Pr
 			continue
 		}
 
-		if localStores[resolver.ACME.Storage] == nil {
-			localStores[resolver.ACME.Storage] = acme.NewLocalStore(resolver.ACME.Storage)
+		var store acme.Store
+		var httpChallenge, tlsChallenge challenge.Provider = httpChallengeProvider, tlsChallengeProvider
+		if resolver.ACME.KVStorage != nil {
+			kvStore, ok := kvStores[name]
+			if !ok {
+				// The KV store creation error has already been logged.
+				continue
+			}
+			store = kvStore
+
+			// The challenges are shared with the other instances through the KV store of the resolver.
+			httpChallenge = httpChallengeProvider.WithStore(kvStore)
+			tlsChallenge = tlsChallengeProvider.WithStore(kvStore)
+		} else {
+			if localStores[resolver.ACME.Storage] == nil {
+				localStores[resolver.ACME.Storage] = acme.NewLocalStore(resolver.ACME.Storage)
+			}
+			store = localStores[resolver.ACME.Storage]
 		}
 
 		p := &acme.Provider{
 			Configuration:         resolver.ACME,
-			Store:                 localStores[resolver.ACME.Storage],
+			Store:                 store,
 			ResolverName:          name,
-			HTTPChallengeProvider: httpChallengeProvider,
-			TLSChallengeProvider:  tlsChallengeProvider,
+			HTTPChallengeProvider: httpChallenge,
+			TLSChallengeProvider:  tlsChallenge,
 		}
 
 		if err := providerAggregator.AddProvider(p); err != nil {
@@ -410,6 +505,41 @@ func initACMEProvider(c *static.Configuration, providerAggregator *aggregator.Pr
 		resolvers = append(resolvers, p)
 	}
 
+	sort.Slice(resolvers, func(i, j int) bool {
+		return resolvers[i].ResolverName < resolvers[j].ResolverName
+	})
+
+	for _, p := range resolvers {
+		if p.OnDemand != nil {
+			tlsManager.AddOnDemandResolver(p)
+		}
+	}
+
+	return resolvers
+}
+
+// initCAProviders creates the providers of the CA certificate resolvers.
+func initCAProviders(c *static.Configuration, providerAggregator *aggregator.ProviderAggregator) []*ca.Provider {
+	var resolvers []*ca.Provider
+	for name, resolver := range c.CertificatesResolvers {
+		if resolver.CA == nil {
+			continue
+		}
+
+		p := ca.NewProvider(name, resolver.CA)
+
+		if err := providerAggregator.AddProvider(p); err != nil {
+			log.WithoutContext().Errorf("The CA resolver %q is skipped from the resolvers list because: %v", name, err)
+			continue
+		}
+
+		resolvers = append(resolvers, p)
+	}
+
+	sort.Slice(resolvers, func(i, j int) bool {
+		return resolvers[i].ResolverName < resolvers[j].ResolverName
+	})
+
 	return resolvers
 }
 
@@ -450,6 +580,16 @@ func registerMetricClients(metricsConfig *types.Metrics) []metrics.Registry {
 			metricsConfig.InfluxDB.Address, metricsConfig.InfluxDB.PushInterval)
 	}
 
+	if metricsConfig.OpenTelemetry != nil {
+		ctx := log.With(context.Background(), log.Str(log.MetricsProviderName, "openTelemetry"))
+		openTelemetryRegistry := metrics.RegisterOpenTelemetry(ctx, metricsConfig.OpenTelemetry)
+		if openTelemetryRegistry != nil {
+			registries = append(registries, openTelemetryRegistry)
+			log.FromContext(ctx).Debugf("Configured OpenTelemetry metrics: pushing once every %s",
+				metricsConfig.OpenTelemetry.PushInterval)
+		}
+	}
+
 	return registries
 }
 
@@ -510,11 +650,28 @@ func configureLogging(staticConfiguration *static.Configuration) {
 	if staticConfiguration.Log != nil && staticConfiguration.Log.Format == "json" {
 		formatter = &logrus.JSONFormatter{}
 	} else {
-		disableColors := len(logFile) > 0
+		disableColors := len(logFile) > 0 || staticConfiguration.Log != nil && staticConfiguration.Log.Sink != nil
 		formatter = &logrus.TextFormatter{DisableColors: disableColors, FullTimestamp: true, DisableSorting: true}
 	}
 	log.SetFormatter(formatter)
 
+	if staticConfiguration.Log != nil && staticConfiguration.Log.Sink != nil {
+		sink, err := staticConfiguration.Log.Sink.CreateSink(context.Background(), "traefik")
+		if err != nil {
+			log.WithoutContext().Errorf("Error while creating log sink: %v", err)
+			return
+		}
+
+		log.SetOutput(sink)
+		logrus.RegisterExitHandler(func() {
+			log.SetOutput(os.Stdout)
+			if err := sink.Close(); err != nil {
+				log.WithoutContext().Errorf("Error while closing log sink: %v", err)
+			}
+		})
+		return
+	}
+
 	if len(logFile) > 0 {
 		dir := filepath.Dir(logFile)
 
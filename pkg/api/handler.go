@@ -51,12 +51,21 @@ type Handler struct {
 
 	// runtimeConfiguration is the data set used to create all the data representations exposed by the API.
 	runtimeConfiguration *runtime.Configuration
+
+	// acmeResolvers are the ACME certificate resolvers, by name.
+	acmeResolvers map[string]ACMEResolver
+
+	// caSigners are the CA certificate resolvers, by name.
+	caSigners map[string]CASigner
 }
 
 // NewBuilder returns a http.Handler builder based on runtime.Configuration.
-func NewBuilder(staticConfig static.Configuration) func(*runtime.Configuration) http.Handler {
+func NewBuilder(staticConfig static.Configuration, acmeResolvers map[string]ACMEResolver, caSigners map[string]CASigner) func(*runtime.Configuration) http.Handler {
 	return func(configuration *runtime.Configuration) http.Handler {
-		return New(staticConfig, configuration).createRouter()
+		handler := New(staticConfig, configuration)
+		handler.acmeResolvers = acmeResolvers
+		handler.caSigners = caSigners
+		return handler.createRouter()
 	}
 }
 
@@ -109,6 +118,18 @@ func (h Handler) createRouter() *mux.Router {
 	router.Methods(http.MethodGet).Path("/api/udp/services").HandlerFunc(h.getUDPServices)
 	router.Methods(http.MethodGet).Path("/api/udp/services/{serviceID}").HandlerFunc(h.getUDPService)
 
+	router.Methods(http.MethodGet).Path("/api/acme/{resolverID}/certificates").HandlerFunc(h.getACMECertificates)
+
+	if h.staticConfig.API.ACMEManagement {
+		router.Methods(http.MethodPost).Path("/api/acme/{resolverID}/certificates/{domain}/renew").HandlerFunc(h.renewACMECertificate)
+		router.Methods(http.MethodPost).Path("/api/acme/{resolverID}/certificates/{domain}/revoke").HandlerFunc(h.revokeACMECertificate)
+		router.Methods(http.MethodDelete).Path("/api/acme/{resolverID}/certificates/{domain}").HandlerFunc(h.deleteACMECertificate)
+	}
+
+	if h.staticConfig.API.CASigning {
+		router.Methods(http.MethodPost).Path("/api/ca/{resolverID}/sign").HandlerFunc(h.signCSR)
+	}
+
 	version.Handler{}.Append(router)
 
 	return router
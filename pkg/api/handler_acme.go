@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider/acme"
+)
+
+// ACMEResolver is an ACME certificate resolver whose certificates are managed through the API.
+type ACMEResolver interface {
+	Certificates() ([]acme.CertificateInfo, error)
+	RenewCertificate(domain string) error
+	RevokeCertificate(domain string) error
+	DeleteCertificate(domain string) error
+}
+
+func (h Handler) getACMECertificates(rw http.ResponseWriter, request *http.Request) {
+	resolver, ok := h.getACMEResolver(rw, request)
+	if !ok {
+		return
+	}
+
+	certificates, err := resolver.Certificates()
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	results := make([]acme.CertificateInfo, 0, len(certificates))
+	results = append(results, certificates...)
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Domain.Main < results[j].Domain.Main
+	})
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) renewACMECertificate(rw http.ResponseWriter, request *http.Request) {
+	resolver, ok := h.getACMEResolver(rw, request)
+	if !ok {
+		return
+	}
+
+	h.handleACMECertificateAction(rw, request, resolver.RenewCertificate, http.StatusAccepted)
+}
+
+func (h Handler) revokeACMECertificate(rw http.ResponseWriter, request *http.Request) {
+	resolver, ok := h.getACMEResolver(rw, request)
+	if !ok {
+		return
+	}
+
+	h.handleACMECertificateAction(rw, request, resolver.RevokeCertificate, http.StatusNoContent)
+}
+
+func (h Handler) deleteACMECertificate(rw http.ResponseWriter, request *http.Request) {
+	resolver, ok := h.getACMEResolver(rw, request)
+	if !ok {
+		return
+	}
+
+	h.handleACMECertificateAction(rw, request, resolver.DeleteCertificate, http.StatusNoContent)
+}
+
+func (h Handler) handleACMECertificateAction(rw http.ResponseWriter, request *http.Request, action func(domain string) error, statusCode int) {
+	domain := mux.Vars(request)["domain"]
+
+	err := action(domain)
+	if errors.Is(err, acme.ErrCertificateNotFound) {
+		writeError(rw, fmt.Sprintf("certificate not found: %s", domain), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, acme.ErrRenewalInProgress) {
+		writeError(rw, fmt.Sprintf("certificate already being renewed: %s", domain), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.WriteHeader(statusCode)
+}
+
+func (h Handler) getACMEResolver(rw http.ResponseWriter, request *http.Request) (ACMEResolver, bool) {
+	resolverID := mux.Vars(request)["resolverID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	resolver, ok := h.acmeResolvers[resolverID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("ACME resolver not found: %s", resolverID), http.StatusNotFound)
+		return nil, false
+	}
+
+	return resolver, true
+}
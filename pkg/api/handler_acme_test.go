@@ -0,0 +1,292 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/provider/acme"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+type acmeResolverMock struct {
+	certificates []acme.CertificateInfo
+	err          error
+	renewing     bool
+
+	mu      sync.Mutex
+	actions []string
+}
+
+func (r *acmeResolverMock) Certificates() ([]acme.CertificateInfo, error) {
+	return r.certificates, r.err
+}
+
+func (r *acmeResolverMock) RenewCertificate(domain string) error {
+	if r.renewing {
+		return acme.ErrRenewalInProgress
+	}
+
+	return r.action("renew", domain)
+}
+
+func (r *acmeResolverMock) RevokeCertificate(domain string) error {
+	return r.action("revoke", domain)
+}
+
+func (r *acmeResolverMock) DeleteCertificate(domain string) error {
+	return r.action("delete", domain)
+}
+
+func (r *acmeResolverMock) action(name, domain string) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	for _, cert := range r.certificates {
+		if cert.Domain.Main == domain {
+			r.mu.Lock()
+			r.actions = append(r.actions, name+" "+domain)
+			r.mu.Unlock()
+			return nil
+		}
+	}
+
+	return acme.ErrCertificateNotFound
+}
+
+func TestHandler_ACME(t *testing.T) {
+	notAfter := time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC)
+	lastAttempt := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	type expected struct {
+		statusCode int
+		nextPage   string
+		jsonFile   string
+		actions    []string
+	}
+
+	testCases := []struct {
+		desc           string
+		method         string
+		path           string
+		acmeManagement bool
+		resolver       *acmeResolverMock
+		expected       expected
+	}{
+		{
+			desc:     "all certificates, but no certificate",
+			method:   http.MethodGet,
+			path:     "/api/acme/myresolver/certificates",
+			resolver: &acmeResolverMock{},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/acme-certificates-empty.json",
+			},
+		},
+		{
+			desc:   "all certificates",
+			method: http.MethodGet,
+			path:   "/api/acme/myresolver/certificates",
+			resolver: &acmeResolverMock{
+				certificates: []acme.CertificateInfo{
+					{
+						Domain:             types.Domain{Main: "foo.com", SANs: []string{"www.foo.com"}},
+						Store:              "default",
+						Issuer:             "CN=R3,O=Let's Encrypt,C=US",
+						NotAfter:           &notAfter,
+						LastRenewalAttempt: &lastAttempt,
+						LastRenewalError:   "rate limited",
+					},
+					{
+						Domain:  types.Domain{Main: "bar.com"},
+						Store:   "default",
+						Issuer:  "CN=R3,O=Let's Encrypt,C=US",
+						Expired: true,
+					},
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/acme-certificates.json",
+			},
+		},
+		{
+			desc:     "all certificates, with an unknown resolver",
+			method:   http.MethodGet,
+			path:     "/api/acme/unknown/certificates",
+			resolver: &acmeResolverMock{},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+			},
+		},
+		{
+			desc:     "all certificates, with an error",
+			method:   http.MethodGet,
+			path:     "/api/acme/myresolver/certificates",
+			resolver: &acmeResolverMock{err: errors.New("KV store unreachable")},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			desc:           "renew a certificate",
+			method:         http.MethodPost,
+			path:           "/api/acme/myresolver/certificates/foo.com/renew",
+			acmeManagement: true,
+			resolver:       &acmeResolverMock{certificates: []acme.CertificateInfo{{Domain: types.Domain{Main: "foo.com"}}}},
+			expected: expected{
+				statusCode: http.StatusAccepted,
+				actions:    []string{"renew foo.com"},
+			},
+		},
+		{
+			desc:           "renew a certificate that does not exist",
+			method:         http.MethodPost,
+			path:           "/api/acme/myresolver/certificates/bar.com/renew",
+			acmeManagement: true,
+			resolver:       &acmeResolverMock{certificates: []acme.CertificateInfo{{Domain: types.Domain{Main: "foo.com"}}}},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+			},
+		},
+		{
+			desc:           "renew a certificate already being renewed",
+			method:         http.MethodPost,
+			path:           "/api/acme/myresolver/certificates/foo.com/renew",
+			acmeManagement: true,
+			resolver: &acmeResolverMock{
+				certificates: []acme.CertificateInfo{{Domain: types.Domain{Main: "foo.com"}}},
+				renewing:     true,
+			},
+			expected: expected{
+				statusCode: http.StatusConflict,
+			},
+		},
+		{
+			desc:     "renew a certificate, without the ACME management",
+			method:   http.MethodPost,
+			path:     "/api/acme/myresolver/certificates/foo.com/renew",
+			resolver: &acmeResolverMock{certificates: []acme.CertificateInfo{{Domain: types.Domain{Main: "foo.com"}}}},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+			},
+		},
+		{
+			desc:     "delete a certificate, without the ACME management",
+			method:   http.MethodDelete,
+			path:     "/api/acme/myresolver/certificates/foo.com",
+			resolver: &acmeResolverMock{certificates: []acme.CertificateInfo{{Domain: types.Domain{Main: "foo.com"}}}},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+			},
+		},
+		{
+			desc:           "revoke a certificate",
+			method:         http.MethodPost,
+			path:           "/api/acme/myresolver/certificates/foo.com/revoke",
+			acmeManagement: true,
+			resolver:       &acmeResolverMock{certificates: []acme.CertificateInfo{{Domain: types.Domain{Main: "foo.com"}}}},
+			expected: expected{
+				statusCode: http.StatusNoContent,
+				actions:    []string{"revoke foo.com"},
+			},
+		},
+		{
+			desc:           "revoke a certificate, with an error",
+			method:         http.MethodPost,
+			path:           "/api/acme/myresolver/certificates/foo.com/revoke",
+			acmeManagement: true,
+			resolver:       &acmeResolverMock{err: errors.New("unauthorized")},
+			expected: expected{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			desc:           "delete a wildcard certificate",
+			method:         http.MethodDelete,
+			path:           "/api/acme/myresolver/certificates/*.foo.com",
+			acmeManagement: true,
+			resolver:       &acmeResolverMock{certificates: []acme.CertificateInfo{{Domain: types.Domain{Main: "*.foo.com"}}}},
+			expected: expected{
+				statusCode: http.StatusNoContent,
+				actions:    []string{"delete *.foo.com"},
+			},
+		},
+		{
+			desc:           "delete a certificate, with an unknown resolver",
+			method:         http.MethodDelete,
+			path:           "/api/acme/unknown/certificates/foo.com",
+			acmeManagement: true,
+			resolver:       &acmeResolverMock{certificates: []acme.CertificateInfo{{Domain: types.Domain{Main: "foo.com"}}}},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := New(static.Configuration{API: &static.API{ACMEManagement: test.acmeManagement}, Global: &static.Global{}}, &runtime.Configuration{})
+			handler.acmeResolvers = map[string]ACMEResolver{"myresolver": test.resolver}
+
+			server := httptest.NewServer(handler.createRouter())
+			t.Cleanup(server.Close)
+
+			req, err := http.NewRequest(test.method, server.URL+test.path, nil)
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected.nextPage, resp.Header.Get(nextPageHeader))
+
+			require.Equal(t, test.expected.statusCode, resp.StatusCode)
+
+			assert.Equal(t, test.expected.actions, test.resolver.actions)
+
+			if test.expected.jsonFile == "" {
+				return
+			}
+
+			assert.Equal(t, resp.Header.Get("Content-Type"), "application/json")
+
+			contents, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			err = resp.Body.Close()
+			require.NoError(t, err)
+
+			if *updateExpected {
+				var results interface{}
+				err := json.Unmarshal(contents, &results)
+				require.NoError(t, err)
+
+				newJSON, err := json.MarshalIndent(results, "", "\t")
+				require.NoError(t, err)
+
+				err = os.WriteFile(test.expected.jsonFile, newJSON, 0o644)
+				require.NoError(t, err)
+			}
+
+			data, err := os.ReadFile(test.expected.jsonFile)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(data), string(contents))
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider/ca"
+)
+
+// maxCSRSize is the maximum size of a certificate signing request.
+const maxCSRSize = 64 * 1024
+
+// CASigner is a CA certificate resolver signing the certificate signing requests sent to the API.
+type CASigner interface {
+	SignCSR(csrPEM []byte) ([]byte, error)
+}
+
+func (h Handler) signCSR(rw http.ResponseWriter, request *http.Request) {
+	resolverID := mux.Vars(request)["resolverID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	signer, ok := h.caSigners[resolverID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("CA resolver not found: %s", resolverID), http.StatusNotFound)
+		return
+	}
+
+	csr, err := io.ReadAll(io.LimitReader(request.Body, maxCSRSize))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	certificate, err := signer.SignCSR(csr)
+	if err != nil {
+		switch {
+		case errors.Is(err, ca.ErrCSRSigningDisabled):
+			writeError(rw, err.Error(), http.StatusForbidden)
+		case errors.Is(err, ca.ErrInvalidCSR):
+			writeError(rw, err.Error(), http.StatusBadRequest)
+		default:
+			log.FromContext(request.Context()).Error(err)
+			writeError(rw, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/x-pem-file")
+
+	if _, err := rw.Write(certificate); err != nil {
+		log.FromContext(request.Context()).Error(err)
+	}
+}
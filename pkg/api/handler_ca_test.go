@@ -0,0 +1,108 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/provider/ca"
+)
+
+type caSignerMock struct {
+	err error
+}
+
+func (s caSignerMock) SignCSR(csrPEM []byte) ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return []byte("signed " + string(csrPEM)), nil
+}
+
+func TestHandler_CA(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		path               string
+		disabled           bool
+		signer             caSignerMock
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			desc:               "signed CSR",
+			path:               "/api/ca/internal/sign",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "signed csr",
+		},
+		{
+			desc:               "without the CA signing",
+			path:               "/api/ca/internal/sign",
+			disabled:           true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			desc:               "unknown resolver",
+			path:               "/api/ca/unknown/sign",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			desc:               "signing disabled",
+			path:               "/api/ca/internal/sign",
+			signer:             caSignerMock{err: ca.ErrCSRSigningDisabled},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			desc:               "invalid CSR",
+			path:               "/api/ca/internal/sign",
+			signer:             caSignerMock{err: fmt.Errorf("%w: domain not allowed", ca.ErrInvalidCSR)},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "signing error",
+			path:               "/api/ca/internal/sign",
+			signer:             caSignerMock{err: errors.New("boom")},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := New(static.Configuration{API: &static.API{CASigning: !test.disabled}, Global: &static.Global{}}, &runtime.Configuration{})
+			handler.caSigners = map[string]CASigner{"internal": test.signer}
+
+			server := httptest.NewServer(handler.createRouter())
+			t.Cleanup(server.Close)
+
+			resp, err := http.Post(server.URL+test.path, "application/pkcs10", strings.NewReader("csr"))
+			require.NoError(t, err)
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+
+			if test.expectedBody == "" {
+				return
+			}
+
+			assert.Equal(t, "application/x-pem-file", resp.Header.Get("Content-Type"))
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			err = resp.Body.Close()
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedBody, string(body))
+		})
+	}
+}
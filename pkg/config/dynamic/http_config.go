@@ -226,6 +226,15 @@ type ServersTransport struct {
 	ForwardingTimeouts  *ForwardingTimeouts        `description:"Timeouts for requests forwarded to the backend servers." json:"forwardingTimeouts,omitempty" toml:"forwardingTimeouts,omitempty" yaml:"forwardingTimeouts,omitempty" export:"true"`
 	DisableHTTP2        bool                       `description:"Disable HTTP/2 for connections with backend servers." json:"disableHTTP2,omitempty" toml:"disableHTTP2,omitempty" yaml:"disableHTTP2,omitempty" export:"true"`
 	PeerCertURI         string                     `description:"URI used to match against SAN URI during the peer certificate verification." json:"peerCertURI,omitempty" toml:"peerCertURI,omitempty" yaml:"peerCertURI,omitempty" export:"true"`
+	Spiffe              *Spiffe                    `description:"Enable mTLS with the X.509 SVIDs and trust bundles of the SPIFFE Workload API." json:"spiffe,omitempty" toml:"spiffe,omitempty" yaml:"spiffe,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Spiffe holds the SPIFFE verification configuration of the servers.
+type Spiffe struct {
+	IDs         []string `description:"Allowed SPIFFE IDs of the servers (takes precedence over the trust domain)." json:"ids,omitempty" toml:"ids,omitempty" yaml:"ids,omitempty" export:"true"`
+	TrustDomain string   `description:"Allowed SPIFFE trust domain of the servers." json:"trustDomain,omitempty" toml:"trustDomain,omitempty" yaml:"trustDomain,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
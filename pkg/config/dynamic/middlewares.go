@@ -32,6 +32,7 @@ type Middleware struct {
 	CircuitBreaker    *CircuitBreaker    `json:"circuitBreaker,omitempty" toml:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty" export:"true"`
 	Compress          *Compress          `json:"compress,omitempty" toml:"compress,omitempty" yaml:"compress,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	PassTLSClientCert *PassTLSClientCert `json:"passTLSClientCert,omitempty" toml:"passTLSClientCert,omitempty" yaml:"passTLSClientCert,omitempty" export:"true"`
+	ClientCertAuth    *ClientCertAuth    `json:"clientCertAuth,omitempty" toml:"clientCertAuth,omitempty" yaml:"clientCertAuth,omitempty" export:"true"`
 	Retry             *Retry             `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty" export:"true"`
 	ContentType       *ContentType       `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty" export:"true"`
 
@@ -98,6 +99,23 @@ type CircuitBreaker struct {
 
 // +k8s:deepcopy-gen=true
 
+// ClientCertAuth holds the TLS client certificate authorization configuration.
+// The certificate must match all the defined criteria, and one of the values of each criterion.
+type ClientCertAuth struct {
+	// Subjects are the allowed subject distinguished names (e.g. CN=client,O=Example).
+	Subjects []string `json:"subjects,omitempty" toml:"subjects,omitempty" yaml:"subjects,omitempty" export:"true"`
+	// Issuers are the allowed issuer distinguished names.
+	Issuers []string `json:"issuers,omitempty" toml:"issuers,omitempty" yaml:"issuers,omitempty" export:"true"`
+	// DNSNames are the patterns of the allowed DNS names of the SAN extension (e.g. *.example.com).
+	DNSNames []string `json:"dnsNames,omitempty" toml:"dnsNames,omitempty" yaml:"dnsNames,omitempty" export:"true"`
+	// URIs are the patterns of the allowed URIs of the SAN extension (e.g. spiffe://example.org/ns/*/sa/client).
+	URIs []string `json:"uris,omitempty" toml:"uris,omitempty" yaml:"uris,omitempty" export:"true"`
+	// Fingerprints are the allowed SHA-256 fingerprints of the certificate, in hexadecimal.
+	Fingerprints []string `json:"fingerprints,omitempty" toml:"fingerprints,omitempty" yaml:"fingerprints,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Compress holds the compress configuration.
 type Compress struct {
 	ExcludedContentTypes []string `json:"excludedContentTypes,omitempty" toml:"excludedContentTypes,omitempty" yaml:"excludedContentTypes,omitempty" export:"true"`
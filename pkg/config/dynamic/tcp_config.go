@@ -2,10 +2,18 @@ package dynamic
 
 import (
 	"reflect"
+	"time"
 
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/types"
 )
 
+// Default values of the dialing of the servers of a TCP service, used when not configured.
+const (
+	DefaultTCPDialTimeout = ptypes.Duration(30 * time.Second)
+	DefaultTCPDNSTTL      = ptypes.Duration(30 * time.Second)
+)
+
 // +k8s:deepcopy-gen=true
 
 // TCPConfiguration contains all the TCP configuration parameters.
@@ -74,9 +82,24 @@ type TCPServersLoadBalancer struct {
 	// connection, to close the reading capability as well, hence fully terminating the
 	// connection. It is a duration in milliseconds, defaulting to 100. A negative value
 	// means an infinite deadline (i.e. the reading capability is never closed).
-	TerminationDelay *int           `json:"terminationDelay,omitempty" toml:"terminationDelay,omitempty" yaml:"terminationDelay,omitempty" export:"true"`
-	ProxyProtocol    *ProxyProtocol `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
-	Servers          []TCPServer    `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
+	TerminationDelay *int `json:"terminationDelay,omitempty" toml:"terminationDelay,omitempty" yaml:"terminationDelay,omitempty" export:"true"`
+	// DialTimeout is the maximum duration for the connection to a server to be established,
+	// across all the addresses resolved for its host name. It defaults to 30s. A negative value means no timeout.
+	DialTimeout ptypes.Duration `json:"dialTimeout,omitempty" toml:"dialTimeout,omitempty" yaml:"dialTimeout,omitempty" export:"true"`
+	// DNSTTL is the duration during which the addresses resolved for the host name of a server are reused,
+	// before the host name is resolved again. It defaults to 30s. A negative value means the host name is resolved for each connection.
+	DNSTTL ptypes.Duration `json:"dnsTTL,omitempty" toml:"dnsTTL,omitempty" yaml:"dnsTTL,omitempty" export:"true"`
+	// IdleTimeout is the maximum duration a connection can stay without any data exchanged
+	// with the client or the server, before it is closed. Zero means no idle timeout.
+	IdleTimeout ptypes.Duration `json:"idleTimeout,omitempty" toml:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty" export:"true"`
+	// MaxLifetime is the maximum duration of a connection, after which it is closed. Zero means no maximum.
+	MaxLifetime ptypes.Duration `json:"maxLifetime,omitempty" toml:"maxLifetime,omitempty" yaml:"maxLifetime,omitempty" export:"true"`
+	// DrainTimeout is the duration during which the ongoing connections are kept,
+	// once the service is removed or changed by a new configuration, before they are closed.
+	// Zero means the ongoing connections are kept until they are closed by the client or the server.
+	DrainTimeout  ptypes.Duration `json:"drainTimeout,omitempty" toml:"drainTimeout,omitempty" yaml:"drainTimeout,omitempty" export:"true"`
+	ProxyProtocol *ProxyProtocol  `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Servers       []TCPServer     `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
 }
 
 // SetDefaults Default values for a TCPServersLoadBalancer.
@@ -114,10 +137,22 @@ type TCPServer struct {
 
 // ProxyProtocol holds the ProxyProtocol configuration.
 type ProxyProtocol struct {
-	Version int `json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty" export:"true"`
+	Version int                `json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty" export:"true"`
+	TLVs    []ProxyProtocolTLV `json:"tlvs,omitempty" toml:"tlvs,omitempty" yaml:"tlvs,omitempty" export:"true"`
 }
 
 // SetDefaults Default values for a ProxyProtocol.
 func (p *ProxyProtocol) SetDefaults() {
 	p.Version = 2
 }
+
+// +k8s:deepcopy-gen=true
+
+// ProxyProtocolTLV holds a TLV sent in the Proxy Protocol v2 header.
+// Its value is either the given Value, or taken From the client connection:
+// its server name (sni), its application protocol (alpn), or the TLV of the same type sent by the client through the Proxy Protocol (client).
+type ProxyProtocolTLV struct {
+	Type  string `json:"type,omitempty" toml:"type,omitempty" yaml:"type,omitempty" export:"true"`
+	Value string `json:"value,omitempty" toml:"value,omitempty" yaml:"value,omitempty"`
+	From  string `json:"from,omitempty" toml:"from,omitempty" yaml:"from,omitempty" export:"true"`
+}
@@ -10,8 +10,26 @@ import (
 
 // TCPMiddleware holds the TCPMiddleware configuration.
 type TCPMiddleware struct {
-	IPWhiteList *TCPIPWhiteList `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
-	RateLimit   *TCPRateLimit   `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty" export:"true"`
+	IPWhiteList    *TCPIPWhiteList    `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
+	RateLimit      *TCPRateLimit      `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty" export:"true"`
+	ClientCertAuth *TCPClientCertAuth `json:"clientCertAuth,omitempty" toml:"clientCertAuth,omitempty" yaml:"clientCertAuth,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPClientCertAuth holds the TLS client certificate authorization configuration.
+// The certificate must match all the defined criteria, and one of the values of each criterion.
+type TCPClientCertAuth struct {
+	// Subjects are the allowed subject distinguished names (e.g. CN=client,O=Example).
+	Subjects []string `json:"subjects,omitempty" toml:"subjects,omitempty" yaml:"subjects,omitempty" export:"true"`
+	// Issuers are the allowed issuer distinguished names.
+	Issuers []string `json:"issuers,omitempty" toml:"issuers,omitempty" yaml:"issuers,omitempty" export:"true"`
+	// DNSNames are the patterns of the allowed DNS names of the SAN extension (e.g. *.example.com).
+	DNSNames []string `json:"dnsNames,omitempty" toml:"dnsNames,omitempty" yaml:"dnsNames,omitempty" export:"true"`
+	// URIs are the patterns of the allowed URIs of the SAN extension (e.g. spiffe://example.org/ns/*/sa/client).
+	URIs []string `json:"uris,omitempty" toml:"uris,omitempty" yaml:"uris,omitempty" export:"true"`
+	// Fingerprints are the allowed SHA-256 fingerprints of the certificate, in hexadecimal.
+	Fingerprints []string `json:"fingerprints,omitempty" toml:"fingerprints,omitempty" yaml:"fingerprints,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
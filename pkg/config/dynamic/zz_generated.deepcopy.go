@@ -124,6 +124,47 @@ func (in *CircuitBreaker) DeepCopy() *CircuitBreaker {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertAuth) DeepCopyInto(out *ClientCertAuth) {
+	*out = *in
+	if in.Subjects != nil {
+		in, out := &in.Subjects, &out.Subjects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Issuers != nil {
+		in, out := &in.Issuers, &out.Issuers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.URIs != nil {
+		in, out := &in.URIs, &out.URIs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Fingerprints != nil {
+		in, out := &in.Fingerprints, &out.Fingerprints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertAuth.
+func (in *ClientCertAuth) DeepCopy() *ClientCertAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Compress) DeepCopyInto(out *Compress) {
 	*out = *in
@@ -703,6 +744,11 @@ func (in *Middleware) DeepCopyInto(out *Middleware) {
 		*out = new(PassTLSClientCert)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ClientCertAuth != nil {
+		in, out := &in.ClientCertAuth, &out.ClientCertAuth
+		*out = new(ClientCertAuth)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Retry != nil {
 		in, out := &in.Retry, &out.Retry
 		*out = new(Retry)
@@ -830,6 +876,11 @@ func (in *PassTLSClientCert) DeepCopy() *PassTLSClientCert {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProxyProtocol) DeepCopyInto(out *ProxyProtocol) {
 	*out = *in
+	if in.TLVs != nil {
+		in, out := &in.TLVs, &out.TLVs
+		*out = make([]ProxyProtocolTLV, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -843,6 +894,22 @@ func (in *ProxyProtocol) DeepCopy() *ProxyProtocol {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyProtocolTLV) DeepCopyInto(out *ProxyProtocolTLV) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyProtocolTLV.
+func (in *ProxyProtocolTLV) DeepCopy() *ProxyProtocolTLV {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyProtocolTLV)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RateLimit) DeepCopyInto(out *RateLimit) {
 	*out = *in
@@ -1140,6 +1207,11 @@ func (in *ServersTransport) DeepCopyInto(out *ServersTransport) {
 		*out = new(ForwardingTimeouts)
 		**out = **in
 	}
+	if in.Spiffe != nil {
+		in, out := &in.Spiffe, &out.Spiffe
+		*out = new(Spiffe)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1205,6 +1277,27 @@ func (in *SourceCriterion) DeepCopy() *SourceCriterion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Spiffe) DeepCopyInto(out *Spiffe) {
+	*out = *in
+	if in.IDs != nil {
+		in, out := &in.IDs, &out.IDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Spiffe.
+func (in *Spiffe) DeepCopy() *Spiffe {
+	if in == nil {
+		return nil
+	}
+	out := new(Spiffe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sticky) DeepCopyInto(out *Sticky) {
 	*out = *in
@@ -1329,6 +1422,47 @@ func (in *TCPConfiguration) DeepCopy() *TCPConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPClientCertAuth) DeepCopyInto(out *TCPClientCertAuth) {
+	*out = *in
+	if in.Subjects != nil {
+		in, out := &in.Subjects, &out.Subjects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Issuers != nil {
+		in, out := &in.Issuers, &out.Issuers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.URIs != nil {
+		in, out := &in.URIs, &out.URIs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Fingerprints != nil {
+		in, out := &in.Fingerprints, &out.Fingerprints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPClientCertAuth.
+func (in *TCPClientCertAuth) DeepCopy() *TCPClientCertAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPClientCertAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPIPWhiteList) DeepCopyInto(out *TCPIPWhiteList) {
 	*out = *in
@@ -1363,6 +1497,11 @@ func (in *TCPMiddleware) DeepCopyInto(out *TCPMiddleware) {
 		*out = new(TCPRateLimit)
 		**out = **in
 	}
+	if in.ClientCertAuth != nil {
+		in, out := &in.ClientCertAuth, &out.ClientCertAuth
+		*out = new(TCPClientCertAuth)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1450,7 +1589,7 @@ func (in *TCPServersLoadBalancer) DeepCopyInto(out *TCPServersLoadBalancer) {
 	if in.ProxyProtocol != nil {
 		in, out := &in.ProxyProtocol, &out.ProxyProtocol
 		*out = new(ProxyProtocol)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Servers != nil {
 		in, out := &in.Servers, &out.Servers
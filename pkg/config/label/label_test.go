@@ -173,31 +173,43 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.services.Service1.loadbalancer.sticky":                           "false",
 		"traefik.http.services.Service1.loadbalancer.sticky.cookie.name":               "fui",
 
-		"traefik.tcp.middlewares.Middleware0.ipwhitelist.sourcerange":      "foobar, fiibar",
-		"traefik.TCP.Middlewares.Middleware1.RateLimit.Average":            "42",
-		"traefik.TCP.Middlewares.Middleware1.RateLimit.Period":             "42",
-		"traefik.TCP.Middlewares.Middleware1.RateLimit.Burst":              "42",
-		"traefik.tcp.routers.Router0.rule":                                 "foobar",
-		"traefik.tcp.routers.Router0.entrypoints":                          "foobar, fiibar",
-		"traefik.tcp.routers.Router0.service":                              "foobar",
-		"traefik.tcp.routers.Router0.tls.passthrough":                      "false",
-		"traefik.tcp.routers.Router0.tls.options":                          "foo",
-		"traefik.tcp.routers.Router1.rule":                                 "foobar",
-		"traefik.tcp.routers.Router1.entrypoints":                          "foobar, fiibar",
-		"traefik.tcp.routers.Router1.service":                              "foobar",
-		"traefik.tcp.routers.Router1.tls.options":                          "foo",
-		"traefik.tcp.routers.Router1.tls.passthrough":                      "false",
-		"traefik.tcp.services.Service0.loadbalancer.server.Port":           "42",
-		"traefik.tcp.services.Service0.loadbalancer.TerminationDelay":      "42",
-		"traefik.tcp.services.Service0.loadbalancer.proxyProtocol.version": "42",
-		"traefik.tcp.services.Service1.loadbalancer.server.Port":           "42",
-		"traefik.tcp.services.Service1.loadbalancer.TerminationDelay":      "42",
-		"traefik.tcp.services.Service1.loadbalancer.proxyProtocol":         "true",
+		"traefik.tcp.middlewares.Middleware0.ipwhitelist.sourcerange":            "foobar, fiibar",
+		"traefik.TCP.Middlewares.Middleware1.RateLimit.Average":                  "42",
+		"traefik.TCP.Middlewares.Middleware1.RateLimit.Period":                   "42",
+		"traefik.TCP.Middlewares.Middleware1.RateLimit.Burst":                    "42",
+		"traefik.tcp.routers.Router0.rule":                                       "foobar",
+		"traefik.tcp.routers.Router0.entrypoints":                                "foobar, fiibar",
+		"traefik.tcp.routers.Router0.service":                                    "foobar",
+		"traefik.tcp.routers.Router0.tls.passthrough":                            "false",
+		"traefik.tcp.routers.Router0.tls.options":                                "foo",
+		"traefik.tcp.routers.Router1.rule":                                       "foobar",
+		"traefik.tcp.routers.Router1.entrypoints":                                "foobar, fiibar",
+		"traefik.tcp.routers.Router1.service":                                    "foobar",
+		"traefik.tcp.routers.Router1.tls.options":                                "foo",
+		"traefik.tcp.routers.Router1.tls.passthrough":                            "false",
+		"traefik.tcp.services.Service0.loadbalancer.server.Port":                 "42",
+		"traefik.tcp.services.Service0.loadbalancer.TerminationDelay":            "42",
+		"traefik.tcp.services.Service0.loadbalancer.proxyProtocol.version":       "42",
+		"traefik.tcp.services.Service0.loadbalancer.dialTimeout":                 "42s",
+		"traefik.tcp.services.Service0.loadbalancer.dnsTTL":                      "42s",
+		"traefik.tcp.services.Service0.loadbalancer.idleTimeout":                 "42s",
+		"traefik.tcp.services.Service0.loadbalancer.maxLifetime":                 "42s",
+		"traefik.tcp.services.Service0.loadbalancer.drainTimeout":                "42s",
+		"traefik.tcp.services.Service0.loadbalancer.proxyProtocol.tlvs[0].type":  "0xE0",
+		"traefik.tcp.services.Service0.loadbalancer.proxyProtocol.tlvs[0].value": "foobar",
+		"traefik.tcp.services.Service0.loadbalancer.proxyProtocol.tlvs[1].from":  "sni",
+		"traefik.tcp.services.Service1.loadbalancer.server.Port":                 "42",
+		"traefik.tcp.services.Service1.loadbalancer.TerminationDelay":            "42",
+		"traefik.tcp.services.Service1.loadbalancer.proxyProtocol":               "true",
 
 		"traefik.udp.routers.Router0.entrypoints":                "foobar, fiibar",
 		"traefik.udp.routers.Router0.service":                    "foobar",
+		"traefik.udp.routers.Router0.rule":                       "foobar",
+		"traefik.udp.routers.Router0.priority":                   "42",
 		"traefik.udp.routers.Router1.entrypoints":                "foobar, fiibar",
 		"traefik.udp.routers.Router1.service":                    "foobar",
+		"traefik.udp.routers.Router1.rule":                       "foobar",
+		"traefik.udp.routers.Router1.priority":                   "42",
 		"traefik.udp.services.Service0.loadbalancer.server.Port": "42",
 		"traefik.udp.services.Service1.loadbalancer.server.Port": "42",
 	}
@@ -256,7 +268,18 @@ func TestDecodeConfiguration(t *testing.T) {
 							},
 						},
 						TerminationDelay: func(i int) *int { return &i }(42),
-						ProxyProtocol:    &dynamic.ProxyProtocol{Version: 42},
+						DialTimeout:      ptypes.Duration(42 * time.Second),
+						DNSTTL:           ptypes.Duration(42 * time.Second),
+						IdleTimeout:      ptypes.Duration(42 * time.Second),
+						MaxLifetime:      ptypes.Duration(42 * time.Second),
+						DrainTimeout:     ptypes.Duration(42 * time.Second),
+						ProxyProtocol: &dynamic.ProxyProtocol{
+							Version: 42,
+							TLVs: []dynamic.ProxyProtocolTLV{
+								{Type: "0xE0", Value: "foobar"},
+								{From: "sni"},
+							},
+						},
 					},
 				},
 				"Service1": {
@@ -279,14 +302,18 @@ func TestDecodeConfiguration(t *testing.T) {
 						"foobar",
 						"fiibar",
 					},
-					Service: "foobar",
+					Service:  "foobar",
+					Rule:     "foobar",
+					Priority: 42,
 				},
 				"Router1": {
 					EntryPoints: []string{
 						"foobar",
 						"fiibar",
 					},
-					Service: "foobar",
+					Service:  "foobar",
+					Rule:     "foobar",
+					Priority: 42,
 				},
 			},
 			Services: map[string]*dynamic.UDPService{
@@ -767,14 +794,18 @@ func TestEncodeConfiguration(t *testing.T) {
 						"foobar",
 						"fiibar",
 					},
-					Service: "foobar",
+					Service:  "foobar",
+					Rule:     "foobar",
+					Priority: 42,
 				},
 				"Router1": {
 					EntryPoints: []string{
 						"foobar",
 						"fiibar",
 					},
-					Service: "foobar",
+					Service:  "foobar",
+					Rule:     "foobar",
+					Priority: 42,
 				},
 			},
 			Services: map[string]*dynamic.UDPService{
@@ -1352,13 +1383,27 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.TCP.Routers.Router1.TLS.Options":                     "foo",
 		"traefik.TCP.Services.Service0.LoadBalancer.server.Port":      "42",
 		"traefik.TCP.Services.Service0.LoadBalancer.TerminationDelay": "42",
+		"traefik.TCP.Services.Service0.LoadBalancer.DialTimeout":      "0",
+		"traefik.TCP.Services.Service0.LoadBalancer.DNSTTL":           "0",
+		"traefik.TCP.Services.Service0.LoadBalancer.IdleTimeout":      "0",
+		"traefik.TCP.Services.Service0.LoadBalancer.MaxLifetime":      "0",
+		"traefik.TCP.Services.Service0.LoadBalancer.DrainTimeout":     "0",
 		"traefik.TCP.Services.Service1.LoadBalancer.server.Port":      "42",
 		"traefik.TCP.Services.Service1.LoadBalancer.TerminationDelay": "42",
+		"traefik.TCP.Services.Service1.LoadBalancer.DialTimeout":      "0",
+		"traefik.TCP.Services.Service1.LoadBalancer.DNSTTL":           "0",
+		"traefik.TCP.Services.Service1.LoadBalancer.IdleTimeout":      "0",
+		"traefik.TCP.Services.Service1.LoadBalancer.MaxLifetime":      "0",
+		"traefik.TCP.Services.Service1.LoadBalancer.DrainTimeout":     "0",
 
 		"traefik.UDP.Routers.Router0.EntryPoints":                "foobar, fiibar",
 		"traefik.UDP.Routers.Router0.Service":                    "foobar",
+		"traefik.UDP.Routers.Router0.Rule":                       "foobar",
+		"traefik.UDP.Routers.Router0.Priority":                   "42",
 		"traefik.UDP.Routers.Router1.EntryPoints":                "foobar, fiibar",
 		"traefik.UDP.Routers.Router1.Service":                    "foobar",
+		"traefik.UDP.Routers.Router1.Rule":                       "foobar",
+		"traefik.UDP.Routers.Router1.Priority":                   "42",
 		"traefik.UDP.Services.Service0.LoadBalancer.server.Port": "42",
 		"traefik.UDP.Services.Service1.LoadBalancer.server.Port": "42",
 	}
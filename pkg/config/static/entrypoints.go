@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/types"
@@ -18,11 +19,27 @@ type EntryPoint struct {
 	HTTP             HTTPConfig            `description:"HTTP configuration." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" export:"true"`
 	HTTP3            *HTTP3Config          `description:"HTTP3 configuration." json:"http3,omitempty" toml:"http3,omitempty" yaml:"http3,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	UDP              *UDPConfig            `description:"UDP configuration." json:"udp,omitempty" toml:"udp,omitempty" yaml:"udp,omitempty"`
+	UnixSocket       *UnixSocketConfig     `description:"Unix socket configuration." json:"unixSocket,omitempty" toml:"unixSocket,omitempty" yaml:"unixSocket,omitempty" export:"true"`
+	ConnectionLimits *ConnectionLimits     `description:"Limits on the connections accepted by the entry point." json:"connectionLimits,omitempty" toml:"connectionLimits,omitempty" yaml:"connectionLimits,omitempty" export:"true"`
+	StartTLS         *StartTLSConfig       `description:"Upgrades the connections to TLS in-band, before routing them as TLS connections." json:"startTLS,omitempty" toml:"startTLS,omitempty" yaml:"startTLS,omitempty" export:"true"`
+}
+
+// UnixSocketPrefix is the prefix of the entry point addresses targeting a unix socket.
+const UnixSocketPrefix = "unix://"
+
+// IsUnixSocket returns whether the entry point listens on a unix socket.
+func (ep EntryPoint) IsUnixSocket() bool {
+	return strings.HasPrefix(ep.Address, UnixSocketPrefix)
 }
 
 // GetAddress strips any potential protocol part of the address field of the
 // entry point, in order to return the actual address.
+// For a unix socket, it returns the path of the socket.
 func (ep EntryPoint) GetAddress() string {
+	if ep.IsUnixSocket() {
+		return strings.TrimPrefix(ep.Address, UnixSocketPrefix)
+	}
+
 	splitN := strings.SplitN(ep.Address, "/", 2)
 	return splitN[0]
 }
@@ -30,6 +47,11 @@ func (ep EntryPoint) GetAddress() string {
 // GetProtocol returns the protocol part of the address field of the entry point.
 // If none is specified, it defaults to "tcp".
 func (ep EntryPoint) GetProtocol() (string, error) {
+	// Unix sockets are stream sockets, handled as TCP.
+	if ep.IsUnixSocket() {
+		return "tcp", nil
+	}
+
 	splitN := strings.SplitN(ep.Address, "/", 2)
 	if len(splitN) < 2 {
 		return "tcp", nil
@@ -99,8 +121,9 @@ type ForwardedHeaders struct {
 
 // ProxyProtocol contains Proxy-Protocol configuration.
 type ProxyProtocol struct {
-	Insecure   bool     `description:"Trust all." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
-	TrustedIPs []string `description:"Trust only selected IPs." json:"trustedIPs,omitempty" toml:"trustedIPs,omitempty" yaml:"trustedIPs,omitempty"`
+	Insecure   bool              `description:"Trust all." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
+	TrustedIPs []string          `description:"Trust only selected IPs." json:"trustedIPs,omitempty" toml:"trustedIPs,omitempty" yaml:"trustedIPs,omitempty"`
+	TLVHeaders map[string]string `description:"Request headers set to the values of the Proxy Protocol TLVs, by header name." json:"tlvHeaders,omitempty" toml:"tlvHeaders,omitempty" yaml:"tlvHeaders,omitempty" export:"true"`
 }
 
 // EntryPoints holds the HTTP entry point list.
@@ -108,8 +131,9 @@ type EntryPoints map[string]*EntryPoint
 
 // EntryPointsTransport configures communication between clients and Traefik.
 type EntryPointsTransport struct {
-	LifeCycle          *LifeCycle          `description:"Timeouts influencing the server life cycle." json:"lifeCycle,omitempty" toml:"lifeCycle,omitempty" yaml:"lifeCycle,omitempty" export:"true"`
-	RespondingTimeouts *RespondingTimeouts `description:"Timeouts for incoming requests to the Traefik instance." json:"respondingTimeouts,omitempty" toml:"respondingTimeouts,omitempty" yaml:"respondingTimeouts,omitempty" export:"true"`
+	LifeCycle            *LifeCycle          `description:"Timeouts influencing the server life cycle." json:"lifeCycle,omitempty" toml:"lifeCycle,omitempty" yaml:"lifeCycle,omitempty" export:"true"`
+	RespondingTimeouts   *RespondingTimeouts `description:"Timeouts for incoming requests to the Traefik instance." json:"respondingTimeouts,omitempty" toml:"respondingTimeouts,omitempty" yaml:"respondingTimeouts,omitempty" export:"true"`
+	ProtocolSniffTimeout ptypes.Duration     `description:"Duration to wait for the first bytes of a non-TLS connection, to recognize its protocol for the TCP routers with the Protocol rule." json:"protocolSniffTimeout,omitempty" toml:"protocolSniffTimeout,omitempty" yaml:"protocolSniffTimeout,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -118,6 +142,42 @@ func (t *EntryPointsTransport) SetDefaults() {
 	t.LifeCycle.SetDefaults()
 	t.RespondingTimeouts = &RespondingTimeouts{}
 	t.RespondingTimeouts.SetDefaults()
+	t.ProtocolSniffTimeout = ptypes.Duration(DefaultProtocolSniffTimeout)
+}
+
+// UnixSocketConfig is the configuration of the unix socket of an entry point.
+type UnixSocketConfig struct {
+	Mode  string `description:"File mode of the socket, in octal notation (e.g. 0660)." json:"mode,omitempty" toml:"mode,omitempty" yaml:"mode,omitempty" export:"true"`
+	Owner string `description:"User owning the socket, as a name or an ID." json:"owner,omitempty" toml:"owner,omitempty" yaml:"owner,omitempty" export:"true"`
+	Group string `description:"Group owning the socket, as a name or an ID." json:"group,omitempty" toml:"group,omitempty" yaml:"group,omitempty" export:"true"`
+}
+
+// ConnectionLimits holds the limits on the connections accepted by an entry point.
+// The connections exceeding a limit are closed as soon as they are accepted, before being routed.
+type ConnectionLimits struct {
+	MaxConnections      int64       `description:"Maximum number of concurrent connections (0 means no limit)." json:"maxConnections,omitempty" toml:"maxConnections,omitempty" yaml:"maxConnections,omitempty" export:"true"`
+	MaxConnectionsPerIP int64       `description:"Maximum number of concurrent connections per client IP (0 means no limit)." json:"maxConnectionsPerIP,omitempty" toml:"maxConnectionsPerIP,omitempty" yaml:"maxConnectionsPerIP,omitempty" export:"true"`
+	AcceptRate          *AcceptRate `description:"Limits the rate at which connections are accepted." json:"acceptRate,omitempty" toml:"acceptRate,omitempty" yaml:"acceptRate,omitempty" export:"true"`
+}
+
+// AcceptRate limits the rate at which the connections are accepted by an entry point.
+// The rate is defined by dividing Average by Period.
+type AcceptRate struct {
+	Average int64           `description:"Maximum number of connections accepted per period (0 means no limit)." json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty" export:"true"`
+	Period  ptypes.Duration `description:"Period of the accept rate." json:"period,omitempty" toml:"period,omitempty" yaml:"period,omitempty" export:"true"`
+	Burst   int64           `description:"Maximum number of connections accepted at once." json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (a *AcceptRate) SetDefaults() {
+	a.Period = ptypes.Duration(time.Second)
+	a.Burst = 1
+}
+
+// StartTLSConfig is the configuration of the protocol with which the connections of an entry point are upgraded to TLS.
+type StartTLSConfig struct {
+	Protocol string `description:"Protocol upgrading the connections to TLS (smtp, imap, or postgres)." json:"protocol,omitempty" toml:"protocol,omitempty" yaml:"protocol,omitempty" export:"true"`
+	Hostname string `description:"Host name announced in the SMTP and IMAP greetings (defaults to the host name of the system)." json:"hostname,omitempty" toml:"hostname,omitempty" yaml:"hostname,omitempty"`
 }
 
 // UDPConfig is the UDP configuration of an entry point.
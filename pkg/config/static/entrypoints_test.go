@@ -42,7 +42,13 @@ func TestEntryPointProtocol(t *testing.T) {
 			expectedProtocol: "udp",
 			expectedError:    false,
 		},
-
+		{
+			name:             "Unix socket",
+			address:          "unix:///var/run/traefik.sock",
+			expectedAddress:  "/var/run/traefik.sock",
+			expectedProtocol: "tcp",
+			expectedError:    false,
+		},
 		{
 			name:          "With invalid protocol",
 			address:       "127.0.0.1:8080/toto/tata",
@@ -0,0 +1,203 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+)
+
+// DefaultSinkWriteTimeout is the default timeout of the writes of the TCP and syslog outputs,
+// so that a server which stops reading cannot block the shipping of the logs forever.
+const DefaultSinkWriteTimeout = 5 * time.Second
+
+// droppedLines holds the counter of the log lines dropped by the sinks.
+var droppedLines atomic.Value
+
+// SetDroppedLinesCounter sets the counter of the log lines dropped by the sinks,
+// either because their buffer was full, or because the lines could not be shipped.
+func SetDroppedLinesCounter(counter metrics.Counter) {
+	droppedLines.Store(counter)
+}
+
+// Line is a log line waiting to be shipped by a Sink.
+type Line struct {
+	Time time.Time
+	Data []byte
+}
+
+// Output ships log lines to a remote destination.
+type Output interface {
+	// Send ships the given lines, in order.
+	Send(lines []Line) error
+	Close() error
+}
+
+// SinkOptions holds the buffering options of a Sink.
+type SinkOptions struct {
+	// Name identifies the log shipped by the sink in the dropped lines metric.
+	Name string
+	// BufferSize is the maximum number of lines waiting to be shipped.
+	BufferSize int
+	// Block makes writes wait for room in the buffer, instead of dropping the lines.
+	Block bool
+	// BatchSize is the maximum number of lines sent at once to the output.
+	BatchSize int
+	// FlushInterval is the maximum duration a line waits for a batch to be complete.
+	FlushInterval time.Duration
+}
+
+// Sink is an io.WriteCloser which ships the written log lines to an Output, through a bounded buffer.
+type Sink struct {
+	// dropped is accessed atomically, and kept first for 64-bit alignment.
+	dropped int64
+
+	output  Output
+	options SinkOptions
+
+	lines chan Line
+	done  chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+
+	failing bool
+}
+
+// NewSink creates a new Sink and starts shipping lines to the given output.
+func NewSink(output Output, options SinkOptions) *Sink {
+	if options.BatchSize <= 0 {
+		options.BatchSize = 1
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = time.Second
+	}
+
+	s := &Sink{
+		output:  output,
+		options: options,
+		lines:   make(chan Line, options.BufferSize),
+		done:    make(chan struct{}),
+	}
+
+	go s.run()
+
+	return s
+}
+
+// Write implements io.Writer.
+// Each call is expected to hold a single log line, which is copied and buffered to be shipped.
+func (s *Sink) Write(p []byte) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return 0, os.ErrClosed
+	}
+
+	line := Line{Time: time.Now(), Data: make([]byte, len(p))}
+	copy(line.Data, p)
+
+	if s.options.Block {
+		s.lines <- line
+		return len(p), nil
+	}
+
+	select {
+	case s.lines <- line:
+	default:
+		s.drop(1)
+	}
+
+	return len(p), nil
+}
+
+// Close ships the buffered lines, and closes the output.
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.lines)
+	s.mu.Unlock()
+
+	<-s.done
+
+	return s.output.Close()
+}
+
+// Dropped returns the number of lines dropped by the sink.
+func (s *Sink) Dropped() int64 {
+	return atomic.LoadInt64(&s.dropped)
+}
+
+func (s *Sink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.options.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Line, 0, s.options.BatchSize)
+
+	for {
+		select {
+		case line, ok := <-s.lines:
+			if !ok {
+				s.send(batch)
+				return
+			}
+
+			batch = append(batch, line)
+			if len(batch) >= s.options.BatchSize {
+				s.send(batch)
+				batch = batch[:0]
+			}
+
+		case <-ticker.C:
+			s.send(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (s *Sink) send(batch []Line) {
+	if len(batch) == 0 {
+		return
+	}
+
+	err := s.output.Send(batch)
+	if err == nil {
+		s.failing = false
+		return
+	}
+
+	s.drop(len(batch))
+
+	// The error cannot be written to the logs, as they may be the ones being shipped,
+	// so it is reported only once on stderr until the output recovers.
+	if !s.failing {
+		s.failing = true
+		_, _ = fmt.Fprintf(os.Stderr, "Error while shipping %s logs: %v\n", s.options.Name, err)
+	}
+}
+
+func (s *Sink) drop(n int) {
+	atomic.AddInt64(&s.dropped, int64(n))
+
+	if counter, ok := droppedLines.Load().(metrics.Counter); ok {
+		counter.With("log", s.options.Name).Add(float64(n))
+	}
+}
+
+// trimNewline returns the given line without its trailing newline.
+func trimNewline(data []byte) []byte {
+	if len(data) > 0 && data[len(data)-1] == '\n' {
+		return data[:len(data)-1]
+	}
+	return data
+}
@@ -0,0 +1,151 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Formats of the batches sent by an HTTPOutput.
+const (
+	HTTPFormatNDJSON        = "ndjson"
+	HTTPFormatLoki          = "loki"
+	HTTPFormatElasticsearch = "elasticsearch"
+)
+
+// defaultHTTPTimeout is the timeout of the requests sent by an HTTPOutput created without a client.
+const defaultHTTPTimeout = 10 * time.Second
+
+// HTTPOutput ships batches of log lines with HTTP POST requests.
+type HTTPOutput struct {
+	url     string
+	format  string
+	headers map[string]string
+	labels  map[string]string
+	index   string
+	client  *http.Client
+}
+
+// NewHTTPOutput creates a new HTTPOutput.
+// The labels are the Loki stream labels, and the index is the Elasticsearch index,
+// which is only needed when the URL does not target an index.
+func NewHTTPOutput(url, format string, headers, labels map[string]string, index string, client *http.Client) (*HTTPOutput, error) {
+	switch format {
+	case HTTPFormatNDJSON, HTTPFormatLoki, HTTPFormatElasticsearch:
+	default:
+		return nil, fmt.Errorf("unsupported HTTP log format: %q", format)
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+
+	return &HTTPOutput{
+		url:     url,
+		format:  format,
+		headers: headers,
+		labels:  labels,
+		index:   index,
+		client:  client,
+	}, nil
+}
+
+// Send implements the Output interface.
+func (o *HTTPOutput) Send(lines []Line) error {
+	body, contentType, err := o.encode(lines)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, o.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	for name, value := range o.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Drains the body to allow the connection to be reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code from %s: %d", o.url, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Close implements the Output interface.
+func (o *HTTPOutput) Close() error {
+	o.client.CloseIdleConnections()
+	return nil
+}
+
+func (o *HTTPOutput) encode(lines []Line) ([]byte, string, error) {
+	var buf bytes.Buffer
+
+	switch o.format {
+	case HTTPFormatLoki:
+		values := make([][2]string, 0, len(lines))
+		for _, line := range lines {
+			values = append(values, [2]string{strconv.FormatInt(line.Time.UnixNano(), 10), string(trimNewline(line.Data))})
+		}
+
+		push := lokiPush{Streams: []lokiStream{{Stream: o.labels, Values: values}}}
+		if err := json.NewEncoder(&buf).Encode(push); err != nil {
+			return nil, "", err
+		}
+
+		return buf.Bytes(), "application/json", nil
+
+	case HTTPFormatElasticsearch:
+		action := []byte(`{"index":{}}`)
+		if o.index != "" {
+			data, err := json.Marshal(map[string]map[string]string{"index": {"_index": o.index}})
+			if err != nil {
+				return nil, "", err
+			}
+			action = data
+		}
+
+		for _, line := range lines {
+			buf.Write(action)
+			buf.WriteByte('\n')
+			buf.Write(trimNewline(line.Data))
+			buf.WriteByte('\n')
+		}
+
+		return buf.Bytes(), "application/x-ndjson", nil
+
+	default:
+		for _, line := range lines {
+			buf.Write(trimNewline(line.Data))
+			buf.WriteByte('\n')
+		}
+
+		return buf.Bytes(), "application/x-ndjson", nil
+	}
+}
+
+// lokiPush is the body of a Loki push API request.
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"bytes"
+	"crypto/tls"
+	"net"
+	"time"
+)
+
+// TCPOutput ships newline-delimited log lines (e.g. JSON) over a TCP connection.
+type TCPOutput struct {
+	address      string
+	tlsConfig    *tls.Config
+	writeTimeout time.Duration
+
+	conn net.Conn
+}
+
+// NewTCPOutput creates a new TCPOutput, which uses TLS if tlsConfig is not nil.
+// A write lasting more than writeTimeout (DefaultSinkWriteTimeout if not positive) fails, and the connection is dialed again on the next Send.
+func NewTCPOutput(address string, tlsConfig *tls.Config, writeTimeout time.Duration) *TCPOutput {
+	return &TCPOutput{address: address, tlsConfig: tlsConfig, writeTimeout: sinkWriteTimeout(writeTimeout)}
+}
+
+// Send implements the Output interface.
+func (o *TCPOutput) Send(lines []Line) error {
+	if o.conn == nil {
+		network := "tcp"
+		if o.tlsConfig != nil {
+			network = "tls"
+		}
+
+		conn, err := dial(network, o.address, o.tlsConfig)
+		if err != nil {
+			return err
+		}
+		o.conn = conn
+	}
+
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.Write(trimNewline(line.Data))
+		buf.WriteByte('\n')
+	}
+
+	if err := o.conn.SetWriteDeadline(time.Now().Add(o.writeTimeout)); err != nil {
+		_ = o.conn.Close()
+		o.conn = nil
+		return err
+	}
+
+	if _, err := o.conn.Write(buf.Bytes()); err != nil {
+		_ = o.conn.Close()
+		o.conn = nil
+		return err
+	}
+
+	return nil
+}
+
+// Close implements the Output interface.
+func (o *TCPOutput) Close() error {
+	if o.conn == nil {
+		return nil
+	}
+	return o.conn.Close()
+}
@@ -0,0 +1,443 @@
+package log
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSink_overflow(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		block           bool
+		expectedDropped int64
+		expectedSent    int
+	}{
+		{
+			desc:            "drop",
+			expectedDropped: 2,
+			expectedSent:    2,
+		},
+		{
+			desc:         "block",
+			block:        true,
+			expectedSent: 4,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			output := &blockingOutput{release: make(chan struct{})}
+			sink := NewSink(output, SinkOptions{Name: "test", BufferSize: 1, Block: test.block})
+
+			// The first line is held by the output, the second one fills the buffer.
+			_, err := sink.Write([]byte("line 1\n"))
+			require.NoError(t, err)
+
+			assert.Eventually(t, func() bool { return len(sink.lines) == 0 }, time.Second, 10*time.Millisecond)
+
+			_, err = sink.Write([]byte("line 2\n"))
+			require.NoError(t, err)
+
+			written := make(chan struct{})
+			go func() {
+				defer close(written)
+
+				for _, line := range []string{"line 3\n", "line 4\n"} {
+					_, err := sink.Write([]byte(line))
+					assert.NoError(t, err)
+				}
+			}()
+
+			if test.block {
+				select {
+				case <-written:
+					t.Fatal("writes should block while the buffer is full")
+				case <-time.After(50 * time.Millisecond):
+				}
+
+				close(output.release)
+				<-written
+			} else {
+				<-written
+				close(output.release)
+			}
+
+			require.NoError(t, sink.Close())
+
+			assert.Equal(t, test.expectedDropped, sink.Dropped())
+			assert.Len(t, output.lines, test.expectedSent)
+
+			_, err = sink.Write([]byte("closed\n"))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSink_batch(t *testing.T) {
+	output := &blockingOutput{release: make(chan struct{})}
+	close(output.release)
+
+	sink := NewSink(output, SinkOptions{BufferSize: 10, BatchSize: 3, FlushInterval: time.Hour})
+
+	for i := 0; i < 4; i++ {
+		_, err := sink.Write([]byte("line\n"))
+		require.NoError(t, err)
+	}
+
+	assert.Eventually(t, func() bool { return len(output.batches()) == 1 }, time.Second, 10*time.Millisecond)
+
+	// The remaining line is sent on close.
+	require.NoError(t, sink.Close())
+
+	assert.Equal(t, []int{3, 1}, output.batches())
+}
+
+func TestSyslogOutput(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		network  string
+		expected string
+	}{
+		{
+			desc:     "udp",
+			network:  "udp",
+			expected: `^<134>1 \S+ myhost traefik \d+ - - hello$`,
+		},
+		{
+			desc:     "tcp",
+			network:  "tcp",
+			expected: `^\d+ <134>1 \S+ myhost traefik \d+ - - hello$`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var address string
+			received := make(chan string, 1)
+
+			if test.network == "udp" {
+				conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+				require.NoError(t, err)
+				t.Cleanup(func() { _ = conn.Close() })
+
+				address = conn.LocalAddr().String()
+
+				go func() {
+					buf := make([]byte, 1024)
+					n, _, err := conn.ReadFrom(buf)
+					if err != nil {
+						return
+					}
+					received <- string(buf[:n])
+				}()
+			} else {
+				listener, err := net.Listen("tcp", "127.0.0.1:0")
+				require.NoError(t, err)
+				t.Cleanup(func() { _ = listener.Close() })
+
+				address = listener.Addr().String()
+
+				go func() {
+					conn, err := listener.Accept()
+					if err != nil {
+						return
+					}
+					defer conn.Close()
+
+					data, _ := io.ReadAll(conn)
+					received <- string(data)
+				}()
+			}
+
+			output, err := NewSyslogOutput(test.network, address, nil, "local0", "myhost", "traefik", 0)
+			require.NoError(t, err)
+
+			require.NoError(t, output.Send([]Line{{Time: time.Now(), Data: []byte("hello\n")}}))
+			require.NoError(t, output.Close())
+
+			select {
+			case msg := <-received:
+				assert.Regexp(t, regexp.MustCompile(test.expected), msg)
+			case <-time.After(5 * time.Second):
+				t.Fatal("syslog message not received")
+			}
+		})
+	}
+}
+
+func TestNewSyslogOutput_errors(t *testing.T) {
+	_, err := NewSyslogOutput("http", "localhost:514", nil, "local0", "", "traefik", 0)
+	assert.Error(t, err)
+
+	_, err = NewSyslogOutput("udp", "localhost:514", nil, "unknown", "", "traefik", 0)
+	assert.Error(t, err)
+}
+
+func TestTCPOutput(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var lines []string
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		received <- lines
+	}()
+
+	output := NewTCPOutput(listener.Addr().String(), nil, 0)
+
+	require.NoError(t, output.Send([]Line{{Data: []byte(`{"a":1}` + "\n")}, {Data: []byte(`{"b":2}`)}}))
+	require.NoError(t, output.Close())
+
+	select {
+	case lines := <-received:
+		assert.Equal(t, []string{`{"a":1}`, `{"b":2}`}, lines)
+	case <-time.After(5 * time.Second):
+		t.Fatal("lines not received")
+	}
+}
+
+func TestOutput_writeTimeout(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		newOutput func(t *testing.T, address string) Output
+	}{
+		{
+			desc: "tcp",
+			newOutput: func(t *testing.T, address string) Output {
+				t.Helper()
+
+				return NewTCPOutput(address, nil, 100*time.Millisecond)
+			},
+		},
+		{
+			desc: "syslog",
+			newOutput: func(t *testing.T, address string) Output {
+				t.Helper()
+
+				output, err := NewSyslogOutput("tcp", address, nil, "local0", "myhost", "traefik", 100*time.Millisecond)
+				require.NoError(t, err)
+
+				return output
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = listener.Close() })
+
+			// The server accepts the connections, but never reads from them.
+			accepted := make(chan net.Conn, 2)
+			go func() {
+				for {
+					conn, err := listener.Accept()
+					if err != nil {
+						return
+					}
+					accepted <- conn
+				}
+			}()
+			t.Cleanup(func() {
+				for {
+					select {
+					case conn := <-accepted:
+						_ = conn.Close()
+					default:
+						return
+					}
+				}
+			})
+
+			output := test.newOutput(t, listener.Addr().String())
+			t.Cleanup(func() { _ = output.Close() })
+
+			// Large enough to fill the socket buffers.
+			lines := []Line{{Time: time.Now(), Data: make([]byte, 16*1024*1024)}}
+
+			errCh := make(chan error, 1)
+			go func() { errCh <- output.Send(lines) }()
+
+			select {
+			case err := <-errCh:
+				var netErr net.Error
+				require.ErrorAs(t, err, &netErr)
+				assert.True(t, netErr.Timeout())
+			case <-time.After(5 * time.Second):
+				t.Fatal("the write is not bounded")
+			}
+
+			// The next batch dials a new connection.
+			require.Error(t, output.Send(lines))
+
+			select {
+			case <-accepted:
+			case <-time.After(5 * time.Second):
+				t.Fatal("no connection dialed")
+			}
+			select {
+			case <-accepted:
+			case <-time.After(5 * time.Second):
+				t.Fatal("the connection is not dialed again")
+			}
+		})
+	}
+}
+
+func TestHTTPOutput(t *testing.T) {
+	lineTime := time.Unix(0, 42)
+
+	testCases := []struct {
+		desc                string
+		format              string
+		index               string
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			desc:                "ndjson",
+			format:              HTTPFormatNDJSON,
+			expectedContentType: "application/x-ndjson",
+			expectedBody:        "{\"a\":1}\n{\"b\":2}\n",
+		},
+		{
+			desc:                "elasticsearch",
+			format:              HTTPFormatElasticsearch,
+			expectedContentType: "application/x-ndjson",
+			expectedBody:        "{\"index\":{}}\n{\"a\":1}\n{\"index\":{}}\n{\"b\":2}\n",
+		},
+		{
+			desc:                "elasticsearch with index",
+			format:              HTTPFormatElasticsearch,
+			index:               "traefik",
+			expectedContentType: "application/x-ndjson",
+			expectedBody:        "{\"index\":{\"_index\":\"traefik\"}}\n{\"a\":1}\n{\"index\":{\"_index\":\"traefik\"}}\n{\"b\":2}\n",
+		},
+		{
+			desc:                "loki",
+			format:              HTTPFormatLoki,
+			expectedContentType: "application/json",
+			expectedBody:        `{"streams":[{"stream":{"job":"traefik"},"values":[["42","{\"a\":1}"],["42","{\"b\":2}"]]}]}`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				contentType string
+				auth        string
+				body        []byte
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				contentType = req.Header.Get("Content-Type")
+				auth = req.Header.Get("Authorization")
+				body, _ = io.ReadAll(req.Body)
+			}))
+			t.Cleanup(server.Close)
+
+			headers := map[string]string{"Authorization": "Bearer token"}
+			labels := map[string]string{"job": "traefik"}
+
+			output, err := NewHTTPOutput(server.URL, test.format, headers, labels, test.index, nil)
+			require.NoError(t, err)
+
+			err = output.Send([]Line{
+				{Time: lineTime, Data: []byte(`{"a":1}` + "\n")},
+				{Time: lineTime, Data: []byte(`{"b":2}` + "\n")},
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedContentType, contentType)
+			assert.Equal(t, "Bearer token", auth)
+
+			if test.format == HTTPFormatLoki {
+				assert.JSONEq(t, test.expectedBody, string(body))
+				return
+			}
+			assert.Equal(t, test.expectedBody, string(body))
+		})
+	}
+}
+
+func TestHTTPOutput_errorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	output, err := NewHTTPOutput(server.URL, HTTPFormatNDJSON, nil, nil, "", nil)
+	require.NoError(t, err)
+
+	sink := NewSink(output, SinkOptions{BufferSize: 10})
+
+	_, err = sink.Write([]byte("line\n"))
+	require.NoError(t, err)
+	require.NoError(t, sink.Close())
+
+	assert.Equal(t, int64(1), sink.Dropped())
+}
+
+// blockingOutput records the lines it is sent, once released.
+type blockingOutput struct {
+	release chan struct{}
+
+	mu    sync.Mutex
+	lines []Line
+	sizes []int
+}
+
+func (o *blockingOutput) Send(lines []Line) error {
+	<-o.release
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.lines = append(o.lines, lines...)
+	o.sizes = append(o.sizes, len(lines))
+	return nil
+}
+
+func (o *blockingOutput) Close() error {
+	return nil
+}
+
+func (o *blockingOutput) batches() []int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return append([]int(nil), o.sizes...)
+}
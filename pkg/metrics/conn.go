@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"io"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+)
+
+// ConnMetrics records the metrics of the TCP connections, or UDP sessions, proxied to the servers of a service.
+// A nil *ConnMetrics is valid, and records nothing.
+type ConnMetrics struct {
+	protocol    string
+	serviceName string
+
+	opened       metrics.Counter
+	closed       metrics.Counter
+	active       metrics.Gauge
+	sent         metrics.Counter
+	received     metrics.Counter
+	duration     ScalableHistogram
+	dialFailures metrics.Counter
+}
+
+// NewConnMetrics creates the ConnMetrics of the given service for the given protocol (tcp or udp).
+// It returns nil if the registry is nil, or if service metrics are not enabled.
+func NewConnMetrics(registry Registry, protocol, serviceName string) *ConnMetrics {
+	if registry == nil || !registry.IsSvcEnabled() {
+		return nil
+	}
+
+	labels := []string{"protocol", protocol, "service", serviceName}
+
+	return &ConnMetrics{
+		protocol:     protocol,
+		serviceName:  serviceName,
+		opened:       registry.ServiceConnsOpenedCounter().With(labels...),
+		closed:       registry.ServiceConnsClosedCounter().With(labels...),
+		active:       registry.ServiceActiveConnsGauge().With(labels...),
+		sent:         registry.ServiceBytesSentCounter().With(labels...),
+		received:     registry.ServiceBytesReceivedCounter().With(labels...),
+		duration:     registry.ServiceConnDurationHistogram().With(labels...),
+		dialFailures: registry.ServiceDialFailuresCounter(),
+	}
+}
+
+// Opened records a connection which has been established with a server.
+func (m *ConnMetrics) Opened() {
+	if m == nil {
+		return
+	}
+
+	m.opened.Add(1)
+	m.active.Add(1)
+}
+
+// Closed records the end of a connection opened at start.
+func (m *ConnMetrics) Closed(start time.Time) {
+	if m == nil {
+		return
+	}
+
+	m.active.Add(-1)
+	m.closed.Add(1)
+	m.duration.ObserveFromStart(start)
+}
+
+// SentWriter returns a writer recording the bytes written to w, which are sent to the client, as they are written.
+// It returns w if m is nil.
+func (m *ConnMetrics) SentWriter(w io.Writer) io.Writer {
+	if m == nil {
+		return w
+	}
+
+	return countingWriter{Writer: w, counter: m.sent}
+}
+
+// ReceivedWriter returns a writer recording the bytes written to w, which are received from the client, as they are written.
+// It returns w if m is nil.
+func (m *ConnMetrics) ReceivedWriter(w io.Writer) io.Writer {
+	if m == nil {
+		return w
+	}
+
+	return countingWriter{Writer: w, counter: m.received}
+}
+
+// DialFailed records a failed attempt to connect to the server at address.
+func (m *ConnMetrics) DialFailed(address string) {
+	if m == nil {
+		return
+	}
+
+	m.dialFailures.With("protocol", m.protocol, "service", m.serviceName, "address", address).Add(1)
+}
+
+// countingWriter adds the number of bytes of each write to the counter.
+type countingWriter struct {
+	io.Writer
+	counter metrics.Counter
+}
+
+func (w countingWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	if n > 0 {
+		w.counter.Add(float64(n))
+	}
+	return n, err
+}
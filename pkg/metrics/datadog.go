@@ -23,11 +23,13 @@ const (
 	ddLastConfigReloadSuccessName   = "config.reload.lastSuccessTimestamp"
 	ddLastConfigReloadFailureName   = "config.reload.lastFailureTimestamp"
 	ddTLSCertsNotAfterTimestampName = "tls.certs.notAfterTimestamp"
+	ddLogDroppedLinesName           = "log.dropped.lines.total"
 
-	ddEntryPointReqsName        = "entrypoint.request.total"
-	ddEntryPointReqsTLSName     = "entrypoint.request.tls.total"
-	ddEntryPointReqDurationName = "entrypoint.request.duration"
-	ddEntryPointOpenConnsName   = "entrypoint.connections.open"
+	ddEntryPointReqsName         = "entrypoint.request.total"
+	ddEntryPointReqsTLSName      = "entrypoint.request.tls.total"
+	ddEntryPointReqDurationName  = "entrypoint.request.duration"
+	ddEntryPointOpenConnsName    = "entrypoint.connections.open"
+	ddEntryPointConnsRefusedName = "entrypoint.connections.refused.total"
 
 	ddMetricsRouterReqsName         = "router.request.total"
 	ddMetricsRouterReqsTLSName      = "router.request.tls.total"
@@ -40,6 +42,14 @@ const (
 	ddRetriesTotalName               = "service.retries.total"
 	ddOpenConnsName                  = "service.connections.open"
 	ddServerUpName                   = "service.server.up"
+
+	ddServiceConnsOpenedName   = "service.connections.opened.total"
+	ddServiceConnsClosedName   = "service.connections.closed.total"
+	ddServiceActiveConnsName   = "service.connections.active"
+	ddServiceBytesSentName     = "service.bytes.sent.total"
+	ddServiceBytesReceivedName = "service.bytes.received.total"
+	ddServiceConnDurationName  = "service.connections.duration"
+	ddServiceDialFailuresName  = "service.dial.failures.total"
 )
 
 // RegisterDatadog registers the metrics pusher if this didn't happen yet and creates a datadog Registry instance.
@@ -64,6 +74,7 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		lastConfigReloadSuccessGauge:   datadogClient.NewGauge(ddLastConfigReloadSuccessName),
 		lastConfigReloadFailureGauge:   datadogClient.NewGauge(ddLastConfigReloadFailureName),
 		tlsCertsNotAfterTimestampGauge: datadogClient.NewGauge(ddTLSCertsNotAfterTimestampName),
+		logDroppedLinesCounter:         datadogClient.NewCounter(ddLogDroppedLinesName, 1.0),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -72,6 +83,7 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.entryPointReqsTLSCounter = datadogClient.NewCounter(ddEntryPointReqsTLSName, 1.0)
 		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddEntryPointReqDurationName, 1.0), time.Second)
 		registry.entryPointOpenConnsGauge = datadogClient.NewGauge(ddEntryPointOpenConnsName)
+		registry.entryPointConnsRefusedCounter = datadogClient.NewCounter(ddEntryPointConnsRefusedName, 1.0)
 	}
 
 	if config.AddRoutersLabels {
@@ -90,6 +102,13 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.serviceRetriesCounter = datadogClient.NewCounter(ddRetriesTotalName, 1.0)
 		registry.serviceOpenConnsGauge = datadogClient.NewGauge(ddOpenConnsName)
 		registry.serviceServerUpGauge = datadogClient.NewGauge(ddServerUpName)
+		registry.serviceConnsOpenedCounter = datadogClient.NewCounter(ddServiceConnsOpenedName, 1.0)
+		registry.serviceConnsClosedCounter = datadogClient.NewCounter(ddServiceConnsClosedName, 1.0)
+		registry.serviceActiveConnsGauge = datadogClient.NewGauge(ddServiceActiveConnsName)
+		registry.serviceBytesSentCounter = datadogClient.NewCounter(ddServiceBytesSentName, 1.0)
+		registry.serviceBytesReceivedCounter = datadogClient.NewCounter(ddServiceBytesReceivedName, 1.0)
+		registry.serviceConnDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddServiceConnDurationName, 1.0), time.Second)
+		registry.serviceDialFailuresCounter = datadogClient.NewCounter(ddServiceDialFailuresName, 1.0)
 	}
 
 	return registry
@@ -51,10 +51,13 @@ func testDatadogRegistry(t *testing.T, metricsPrefix string, datadogRegistry Reg
 
 		metricsPrefix + ".tls.certs.notAfterTimestamp:1.000000|g|#key:value\n",
 
+		metricsPrefix + ".log.dropped.lines.total:1.000000|c|#log:access\n",
+
 		metricsPrefix + ".entrypoint.request.total:1.000000|c|#entrypoint:test\n",
 		metricsPrefix + ".entrypoint.request.tls.total:1.000000|c|#entrypoint:test,tls_version:foo,tls_cipher:bar\n",
 		metricsPrefix + ".entrypoint.request.duration:10000.000000|h|#entrypoint:test\n",
 		metricsPrefix + ".entrypoint.connections.open:1.000000|g|#entrypoint:test\n",
+		metricsPrefix + ".entrypoint.connections.refused.total:1.000000|c|#entrypoint:test,reason:max_connections\n",
 
 		metricsPrefix + ".router.request.total:1.000000|c|#router:demo,service:test,code:404,method:GET\n",
 		metricsPrefix + ".router.request.total:1.000000|c|#router:demo,service:test,code:200,method:GET\n",
@@ -70,6 +73,13 @@ func testDatadogRegistry(t *testing.T, metricsPrefix string, datadogRegistry Reg
 		metricsPrefix + ".service.retries.total:2.000000|c|#service:test\n",
 		metricsPrefix + ".service.request.duration:10000.000000|h|#service:test,code:200\n",
 		metricsPrefix + ".service.server.up:1.000000|g|#service:test,url:http://127.0.0.1,one:two\n",
+		metricsPrefix + ".service.connections.opened.total:1.000000|c|#protocol:tcp,service:test\n",
+		metricsPrefix + ".service.connections.closed.total:1.000000|c|#protocol:tcp,service:test\n",
+		metricsPrefix + ".service.connections.active:1.000000|g|#protocol:tcp,service:test\n",
+		metricsPrefix + ".service.bytes.sent.total:10.000000|c|#protocol:tcp,service:test\n",
+		metricsPrefix + ".service.bytes.received.total:5.000000|c|#protocol:tcp,service:test\n",
+		metricsPrefix + ".service.connections.duration:10000.000000|h|#protocol:tcp,service:test\n",
+		metricsPrefix + ".service.dial.failures.total:1.000000|c|#protocol:tcp,service:test,address:127.0.0.1:80\n",
 	}
 
 	udp.ShouldReceiveAll(t, expected, func() {
@@ -80,10 +90,13 @@ func testDatadogRegistry(t *testing.T, metricsPrefix string, datadogRegistry Reg
 
 		datadogRegistry.TLSCertsNotAfterTimestampGauge().With("key", "value").Set(1)
 
+		datadogRegistry.LogDroppedLinesCounter().With("log", "access").Add(1)
+
 		datadogRegistry.EntryPointReqsCounter().With("entrypoint", "test").Add(1)
 		datadogRegistry.EntryPointReqsTLSCounter().With("entrypoint", "test", "tls_version", "foo", "tls_cipher", "bar").Add(1)
 		datadogRegistry.EntryPointReqDurationHistogram().With("entrypoint", "test").Observe(10000)
 		datadogRegistry.EntryPointOpenConnsGauge().With("entrypoint", "test").Set(1)
+		datadogRegistry.EntryPointConnsRefusedCounter().With("entrypoint", "test", "reason", "max_connections").Add(1)
 
 		datadogRegistry.RouterReqsCounter().With("router", "demo", "service", "test", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).Add(1)
 		datadogRegistry.RouterReqsCounter().With("router", "demo", "service", "test", "code", strconv.Itoa(http.StatusNotFound), "method", http.MethodGet).Add(1)
@@ -99,5 +112,12 @@ func testDatadogRegistry(t *testing.T, metricsPrefix string, datadogRegistry Reg
 		datadogRegistry.ServiceRetriesCounter().With("service", "test").Add(1)
 		datadogRegistry.ServiceRetriesCounter().With("service", "test").Add(1)
 		datadogRegistry.ServiceServerUpGauge().With("service", "test", "url", "http://127.0.0.1", "one", "two").Set(1)
+		datadogRegistry.ServiceConnsOpenedCounter().With("protocol", "tcp", "service", "test").Add(1)
+		datadogRegistry.ServiceConnsClosedCounter().With("protocol", "tcp", "service", "test").Add(1)
+		datadogRegistry.ServiceActiveConnsGauge().With("protocol", "tcp", "service", "test").Set(1)
+		datadogRegistry.ServiceBytesSentCounter().With("protocol", "tcp", "service", "test").Add(10)
+		datadogRegistry.ServiceBytesReceivedCounter().With("protocol", "tcp", "service", "test").Add(5)
+		datadogRegistry.ServiceConnDurationHistogram().With("protocol", "tcp", "service", "test").Observe(10000)
+		datadogRegistry.ServiceDialFailuresCounter().With("protocol", "tcp", "service", "test", "address", "127.0.0.1:80").Add(1)
 	})
 }
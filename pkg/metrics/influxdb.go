@@ -28,11 +28,13 @@ const (
 	influxDBLastConfigReloadFailureName = "traefik.config.reload.lastFailureTimestamp"
 
 	influxDBTLSCertsNotAfterTimestampName = "traefik.tls.certs.notAfterTimestamp"
+	influxDBLogDroppedLinesName           = "traefik.log.dropped.lines.total"
 
-	influxDBEntryPointReqsName        = "traefik.entrypoint.requests.total"
-	influxDBEntryPointReqsTLSName     = "traefik.entrypoint.requests.tls.total"
-	influxDBEntryPointReqDurationName = "traefik.entrypoint.request.duration"
-	influxDBEntryPointOpenConnsName   = "traefik.entrypoint.connections.open"
+	influxDBEntryPointReqsName         = "traefik.entrypoint.requests.total"
+	influxDBEntryPointReqsTLSName      = "traefik.entrypoint.requests.tls.total"
+	influxDBEntryPointReqDurationName  = "traefik.entrypoint.request.duration"
+	influxDBEntryPointOpenConnsName    = "traefik.entrypoint.connections.open"
+	influxDBEntryPointConnsRefusedName = "traefik.entrypoint.connections.refused.total"
 
 	influxDBRouterReqsName         = "traefik.router.requests.total"
 	influxDBRouterReqsTLSName      = "traefik.router.requests.tls.total"
@@ -45,6 +47,14 @@ const (
 	influxDBServiceRetriesTotalName = "traefik.service.retries.total"
 	influxDBServiceOpenConnsName    = "traefik.service.connections.open"
 	influxDBServiceServerUpName     = "traefik.service.server.up"
+
+	influxDBServiceConnsOpenedName   = "traefik.service.connections.opened.total"
+	influxDBServiceConnsClosedName   = "traefik.service.connections.closed.total"
+	influxDBServiceActiveConnsName   = "traefik.service.connections.active"
+	influxDBServiceBytesSentName     = "traefik.service.bytes.sent.total"
+	influxDBServiceBytesReceivedName = "traefik.service.bytes.received.total"
+	influxDBServiceConnDurationName  = "traefik.service.connections.duration"
+	influxDBServiceDialFailuresName  = "traefik.service.dial.failures.total"
 )
 
 const (
@@ -67,6 +77,7 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		lastConfigReloadSuccessGauge:   influxDBClient.NewGauge(influxDBLastConfigReloadSuccessName),
 		lastConfigReloadFailureGauge:   influxDBClient.NewGauge(influxDBLastConfigReloadFailureName),
 		tlsCertsNotAfterTimestampGauge: influxDBClient.NewGauge(influxDBTLSCertsNotAfterTimestampName),
+		logDroppedLinesCounter:         influxDBClient.NewCounter(influxDBLogDroppedLinesName),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -75,6 +86,7 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		registry.entryPointReqsTLSCounter = influxDBClient.NewCounter(influxDBEntryPointReqsTLSName)
 		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBEntryPointReqDurationName), time.Second)
 		registry.entryPointOpenConnsGauge = influxDBClient.NewGauge(influxDBEntryPointOpenConnsName)
+		registry.entryPointConnsRefusedCounter = influxDBClient.NewCounter(influxDBEntryPointConnsRefusedName)
 	}
 
 	if config.AddRoutersLabels {
@@ -93,6 +105,13 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		registry.serviceRetriesCounter = influxDBClient.NewCounter(influxDBServiceRetriesTotalName)
 		registry.serviceOpenConnsGauge = influxDBClient.NewGauge(influxDBServiceOpenConnsName)
 		registry.serviceServerUpGauge = influxDBClient.NewGauge(influxDBServiceServerUpName)
+		registry.serviceConnsOpenedCounter = influxDBClient.NewCounter(influxDBServiceConnsOpenedName)
+		registry.serviceConnsClosedCounter = influxDBClient.NewCounter(influxDBServiceConnsClosedName)
+		registry.serviceActiveConnsGauge = influxDBClient.NewGauge(influxDBServiceActiveConnsName)
+		registry.serviceBytesSentCounter = influxDBClient.NewCounter(influxDBServiceBytesSentName)
+		registry.serviceBytesReceivedCounter = influxDBClient.NewCounter(influxDBServiceBytesReceivedName)
+		registry.serviceConnDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBServiceConnDurationName), time.Second)
+		registry.serviceDialFailuresCounter = influxDBClient.NewCounter(influxDBServiceDialFailuresName)
 	}
 
 	return registry
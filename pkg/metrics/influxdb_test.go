@@ -68,6 +68,7 @@ func TestInfluxDB(t *testing.T) {
 		`(traefik\.entrypoint\.requests\.tls\.total,entrypoint=test,tag1=val1,tls_cipher=bar,tls_version=foo count=1) [\d]{19}`,
 		`(traefik\.entrypoint\.request\.duration(?:,code=[\d]{3})?,entrypoint=test,tag1=val1 p50=10000,p90=10000,p95=10000,p99=10000) [\d]{19}`,
 		`(traefik\.entrypoint\.connections\.open,entrypoint=test,tag1=val1 value=1) [\d]{19}`,
+		`(traefik\.entrypoint\.connections\.refused\.total,entrypoint=test,reason=max_connections,tag1=val1 count=1) [\d]{19}`,
 	}
 
 	msgEntrypoint := udp.ReceiveString(t, func() {
@@ -75,6 +76,7 @@ func TestInfluxDB(t *testing.T) {
 		influxDBRegistry.EntryPointReqsTLSCounter().With("entrypoint", "test", "tls_version", "foo", "tls_cipher", "bar").Add(1)
 		influxDBRegistry.EntryPointReqDurationHistogram().With("entrypoint", "test").Observe(10000)
 		influxDBRegistry.EntryPointOpenConnsGauge().With("entrypoint", "test").Set(1)
+		influxDBRegistry.EntryPointConnsRefusedCounter().With("entrypoint", "test", "reason", "max_connections").Add(1)
 	})
 
 	assertMessage(t, msgEntrypoint, expectedEntrypoint)
@@ -105,6 +107,13 @@ func TestInfluxDB(t *testing.T) {
 		`(traefik\.service\.retries\.total(?:,code=[\d]{3},method=GET)?,service=test,tag1=val1 count=2) [\d]{19}`,
 		`(traefik\.service\.server\.up,service=test,tag1=val1,url=http://127.0.0.1 value=1) [\d]{19}`,
 		`(traefik\.service\.connections\.open,service=test,tag1=val1 value=1) [\d]{19}`,
+		`(traefik\.service\.connections\.opened\.total,protocol=tcp,service=test,tag1=val1 count=1) [\d]{19}`,
+		`(traefik\.service\.connections\.closed\.total,protocol=tcp,service=test,tag1=val1 count=1) [\d]{19}`,
+		`(traefik\.service\.connections\.active,protocol=tcp,service=test,tag1=val1 value=1) [\d]{19}`,
+		`(traefik\.service\.bytes\.sent\.total,protocol=tcp,service=test,tag1=val1 count=10) [\d]{19}`,
+		`(traefik\.service\.bytes\.received\.total,protocol=tcp,service=test,tag1=val1 count=5) [\d]{19}`,
+		`(traefik\.service\.connections\.duration,protocol=tcp,service=test,tag1=val1 p50=10000,p90=10000,p95=10000,p99=10000) [\d]{19}`,
+		`(traefik\.service\.dial\.failures\.total,address=127\.0\.0\.1:80,protocol=tcp,service=test,tag1=val1 count=1) [\d]{19}`,
 	}
 
 	msgService := udp.ReceiveString(t, func() {
@@ -116,6 +125,13 @@ func TestInfluxDB(t *testing.T) {
 		influxDBRegistry.ServiceRetriesCounter().With("service", "test").Add(1)
 		influxDBRegistry.ServiceRetriesCounter().With("service", "test").Add(1)
 		influxDBRegistry.ServiceServerUpGauge().With("service", "test", "url", "http://127.0.0.1").Set(1)
+		influxDBRegistry.ServiceConnsOpenedCounter().With("protocol", "tcp", "service", "test").Add(1)
+		influxDBRegistry.ServiceConnsClosedCounter().With("protocol", "tcp", "service", "test").Add(1)
+		influxDBRegistry.ServiceActiveConnsGauge().With("protocol", "tcp", "service", "test").Set(1)
+		influxDBRegistry.ServiceBytesSentCounter().With("protocol", "tcp", "service", "test").Add(10)
+		influxDBRegistry.ServiceBytesReceivedCounter().With("protocol", "tcp", "service", "test").Add(5)
+		influxDBRegistry.ServiceConnDurationHistogram().With("protocol", "tcp", "service", "test").Observe(10000)
+		influxDBRegistry.ServiceDialFailuresCounter().With("protocol", "tcp", "service", "test", "address", "127.0.0.1:80").Add(1)
 	})
 
 	assertMessage(t, msgService, expectedService)
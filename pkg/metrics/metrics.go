@@ -28,11 +28,15 @@ type Registry interface {
 	// TLS
 	TLSCertsNotAfterTimestampGauge() metrics.Gauge
 
+	// log metrics
+	LogDroppedLinesCounter() metrics.Counter
+
 	// entry point metrics
 	EntryPointReqsCounter() metrics.Counter
 	EntryPointReqsTLSCounter() metrics.Counter
 	EntryPointReqDurationHistogram() ScalableHistogram
 	EntryPointOpenConnsGauge() metrics.Gauge
+	EntryPointConnsRefusedCounter() metrics.Counter
 
 	// router metrics
 	RouterReqsCounter() metrics.Counter
@@ -47,6 +51,15 @@ type Registry interface {
 	ServiceOpenConnsGauge() metrics.Gauge
 	ServiceRetriesCounter() metrics.Counter
 	ServiceServerUpGauge() metrics.Gauge
+
+	// TCP and UDP service metrics
+	ServiceConnsOpenedCounter() metrics.Counter
+	ServiceConnsClosedCounter() metrics.Counter
+	ServiceActiveConnsGauge() metrics.Gauge
+	ServiceBytesSentCounter() metrics.Counter
+	ServiceBytesReceivedCounter() metrics.Counter
+	ServiceConnDurationHistogram() ScalableHistogram
+	ServiceDialFailuresCounter() metrics.Counter
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -64,10 +77,12 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var lastConfigReloadSuccessGauge []metrics.Gauge
 	var lastConfigReloadFailureGauge []metrics.Gauge
 	var tlsCertsNotAfterTimestampGauge []metrics.Gauge
+	var logDroppedLinesCounter []metrics.Counter
 	var entryPointReqsCounter []metrics.Counter
 	var entryPointReqsTLSCounter []metrics.Counter
 	var entryPointReqDurationHistogram []ScalableHistogram
 	var entryPointOpenConnsGauge []metrics.Gauge
+	var entryPointConnsRefusedCounter []metrics.Counter
 	var routerReqsCounter []metrics.Counter
 	var routerReqsTLSCounter []metrics.Counter
 	var routerReqDurationHistogram []ScalableHistogram
@@ -78,6 +93,13 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var serviceOpenConnsGauge []metrics.Gauge
 	var serviceRetriesCounter []metrics.Counter
 	var serviceServerUpGauge []metrics.Gauge
+	var serviceConnsOpenedCounter []metrics.Counter
+	var serviceConnsClosedCounter []metrics.Counter
+	var serviceActiveConnsGauge []metrics.Gauge
+	var serviceBytesSentCounter []metrics.Counter
+	var serviceBytesReceivedCounter []metrics.Counter
+	var serviceConnDurationHistogram []ScalableHistogram
+	var serviceDialFailuresCounter []metrics.Counter
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -95,6 +117,9 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.TLSCertsNotAfterTimestampGauge() != nil {
 			tlsCertsNotAfterTimestampGauge = append(tlsCertsNotAfterTimestampGauge, r.TLSCertsNotAfterTimestampGauge())
 		}
+		if r.LogDroppedLinesCounter() != nil {
+			logDroppedLinesCounter = append(logDroppedLinesCounter, r.LogDroppedLinesCounter())
+		}
 		if r.EntryPointReqsCounter() != nil {
 			entryPointReqsCounter = append(entryPointReqsCounter, r.EntryPointReqsCounter())
 		}
@@ -107,6 +132,9 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.EntryPointOpenConnsGauge() != nil {
 			entryPointOpenConnsGauge = append(entryPointOpenConnsGauge, r.EntryPointOpenConnsGauge())
 		}
+		if r.EntryPointConnsRefusedCounter() != nil {
+			entryPointConnsRefusedCounter = append(entryPointConnsRefusedCounter, r.EntryPointConnsRefusedCounter())
+		}
 		if r.RouterReqsCounter() != nil {
 			routerReqsCounter = append(routerReqsCounter, r.RouterReqsCounter())
 		}
@@ -137,21 +165,44 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceServerUpGauge() != nil {
 			serviceServerUpGauge = append(serviceServerUpGauge, r.ServiceServerUpGauge())
 		}
+		if r.ServiceConnsOpenedCounter() != nil {
+			serviceConnsOpenedCounter = append(serviceConnsOpenedCounter, r.ServiceConnsOpenedCounter())
+		}
+		if r.ServiceConnsClosedCounter() != nil {
+			serviceConnsClosedCounter = append(serviceConnsClosedCounter, r.ServiceConnsClosedCounter())
+		}
+		if r.ServiceActiveConnsGauge() != nil {
+			serviceActiveConnsGauge = append(serviceActiveConnsGauge, r.ServiceActiveConnsGauge())
+		}
+		if r.ServiceBytesSentCounter() != nil {
+			serviceBytesSentCounter = append(serviceBytesSentCounter, r.ServiceBytesSentCounter())
+		}
+		if r.ServiceBytesReceivedCounter() != nil {
+			serviceBytesReceivedCounter = append(serviceBytesReceivedCounter, r.ServiceBytesReceivedCounter())
+		}
+		if r.ServiceConnDurationHistogram() != nil {
+			serviceConnDurationHistogram = append(serviceConnDurationHistogram, r.ServiceConnDurationHistogram())
+		}
+		if r.ServiceDialFailuresCounter() != nil {
+			serviceDialFailuresCounter = append(serviceDialFailuresCounter, r.ServiceDialFailuresCounter())
+		}
 	}
 
 	return &standardRegistry{
 		epEnabled:                      len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0 || len(entryPointOpenConnsGauge) > 0,
-		svcEnabled:                     len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceOpenConnsGauge) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0,
+		svcEnabled:                     len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceOpenConnsGauge) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0 || len(serviceConnsOpenedCounter) > 0,
 		routerEnabled:                  len(routerReqsCounter) > 0 || len(routerReqDurationHistogram) > 0 || len(routerOpenConnsGauge) > 0,
 		configReloadsCounter:           multi.NewCounter(configReloadsCounter...),
 		configReloadsFailureCounter:    multi.NewCounter(configReloadsFailureCounter...),
 		lastConfigReloadSuccessGauge:   multi.NewGauge(lastConfigReloadSuccessGauge...),
 		lastConfigReloadFailureGauge:   multi.NewGauge(lastConfigReloadFailureGauge...),
 		tlsCertsNotAfterTimestampGauge: multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
+		logDroppedLinesCounter:         multi.NewCounter(logDroppedLinesCounter...),
 		entryPointReqsCounter:          multi.NewCounter(entryPointReqsCounter...),
 		entryPointReqsTLSCounter:       multi.NewCounter(entryPointReqsTLSCounter...),
 		entryPointReqDurationHistogram: NewMultiHistogram(entryPointReqDurationHistogram...),
 		entryPointOpenConnsGauge:       multi.NewGauge(entryPointOpenConnsGauge...),
+		entryPointConnsRefusedCounter:  multi.NewCounter(entryPointConnsRefusedCounter...),
 		routerReqsCounter:              multi.NewCounter(routerReqsCounter...),
 		routerReqsTLSCounter:           multi.NewCounter(routerReqsTLSCounter...),
 		routerReqDurationHistogram:     NewMultiHistogram(routerReqDurationHistogram...),
@@ -162,6 +213,13 @@ func NewMultiRegistry(registries []Registry) Registry {
 		serviceOpenConnsGauge:          multi.NewGauge(serviceOpenConnsGauge...),
 		serviceRetriesCounter:          multi.NewCounter(serviceRetriesCounter...),
 		serviceServerUpGauge:           multi.NewGauge(serviceServerUpGauge...),
+		serviceConnsOpenedCounter:      multi.NewCounter(serviceConnsOpenedCounter...),
+		serviceConnsClosedCounter:      multi.NewCounter(serviceConnsClosedCounter...),
+		serviceActiveConnsGauge:        multi.NewGauge(serviceActiveConnsGauge...),
+		serviceBytesSentCounter:        multi.NewCounter(serviceBytesSentCounter...),
+		serviceBytesReceivedCounter:    multi.NewCounter(serviceBytesReceivedCounter...),
+		serviceConnDurationHistogram:   NewMultiHistogram(serviceConnDurationHistogram...),
+		serviceDialFailuresCounter:     multi.NewCounter(serviceDialFailuresCounter...),
 	}
 }
 
@@ -174,10 +232,12 @@ type standardRegistry struct {
 	lastConfigReloadSuccessGauge   metrics.Gauge
 	lastConfigReloadFailureGauge   metrics.Gauge
 	tlsCertsNotAfterTimestampGauge metrics.Gauge
+	logDroppedLinesCounter         metrics.Counter
 	entryPointReqsCounter          metrics.Counter
 	entryPointReqsTLSCounter       metrics.Counter
 	entryPointReqDurationHistogram ScalableHistogram
 	entryPointOpenConnsGauge       metrics.Gauge
+	entryPointConnsRefusedCounter  metrics.Counter
 	routerReqsCounter              metrics.Counter
 	routerReqsTLSCounter           metrics.Counter
 	routerReqDurationHistogram     ScalableHistogram
@@ -188,6 +248,13 @@ type standardRegistry struct {
 	serviceOpenConnsGauge          metrics.Gauge
 	serviceRetriesCounter          metrics.Counter
 	serviceServerUpGauge           metrics.Gauge
+	serviceConnsOpenedCounter      metrics.Counter
+	serviceConnsClosedCounter      metrics.Counter
+	serviceActiveConnsGauge        metrics.Gauge
+	serviceBytesSentCounter        metrics.Counter
+	serviceBytesReceivedCounter    metrics.Counter
+	serviceConnDurationHistogram   ScalableHistogram
+	serviceDialFailuresCounter     metrics.Counter
 }
 
 func (r *standardRegistry) IsEpEnabled() bool {
@@ -222,6 +289,10 @@ func (r *standardRegistry) TLSCertsNotAfterTimestampGauge() metrics.Gauge {
 	return r.tlsCertsNotAfterTimestampGauge
 }
 
+func (r *standardRegistry) LogDroppedLinesCounter() metrics.Counter {
+	return r.logDroppedLinesCounter
+}
+
 func (r *standardRegistry) EntryPointReqsCounter() metrics.Counter {
 	return r.entryPointReqsCounter
 }
@@ -238,6 +309,10 @@ func (r *standardRegistry) EntryPointOpenConnsGauge() metrics.Gauge {
 	return r.entryPointOpenConnsGauge
 }
 
+func (r *standardRegistry) EntryPointConnsRefusedCounter() metrics.Counter {
+	return r.entryPointConnsRefusedCounter
+}
+
 func (r *standardRegistry) RouterReqsCounter() metrics.Counter {
 	return r.routerReqsCounter
 }
@@ -278,6 +353,34 @@ func (r *standardRegistry) ServiceServerUpGauge() metrics.Gauge {
 	return r.serviceServerUpGauge
 }
 
+func (r *standardRegistry) ServiceConnsOpenedCounter() metrics.Counter {
+	return r.serviceConnsOpenedCounter
+}
+
+func (r *standardRegistry) ServiceConnsClosedCounter() metrics.Counter {
+	return r.serviceConnsClosedCounter
+}
+
+func (r *standardRegistry) ServiceActiveConnsGauge() metrics.Gauge {
+	return r.serviceActiveConnsGauge
+}
+
+func (r *standardRegistry) ServiceBytesSentCounter() metrics.Counter {
+	return r.serviceBytesSentCounter
+}
+
+func (r *standardRegistry) ServiceBytesReceivedCounter() metrics.Counter {
+	return r.serviceBytesReceivedCounter
+}
+
+func (r *standardRegistry) ServiceConnDurationHistogram() ScalableHistogram {
+	return r.serviceConnDurationHistogram
+}
+
+func (r *standardRegistry) ServiceDialFailuresCounter() metrics.Counter {
+	return r.serviceDialFailuresCounter
+}
+
 // ScalableHistogram is a Histogram with a predefined time unit,
 // used when producing observations without explicitly setting the observed value.
 type ScalableHistogram interface {
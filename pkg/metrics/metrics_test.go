@@ -65,6 +65,36 @@ func TestNewMultiRegistry(t *testing.T) {
 	}
 }
 
+func TestConnMetrics_writers(t *testing.T) {
+	sent := generic.NewCounter("sent")
+	received := generic.NewCounter("received")
+	m := &ConnMetrics{sent: sent, received: received}
+
+	var sentBuf, receivedBuf bytes.Buffer
+	sentWriter := m.SentWriter(&sentBuf)
+	receivedWriter := m.ReceivedWriter(&receivedBuf)
+
+	// The bytes are recorded on each write, before the connection is closed.
+	_, err := sentWriter.Write([]byte("foo"))
+	require.NoError(t, err)
+	assert.Equal(t, float64(3), sent.Value())
+
+	_, err = sentWriter.Write([]byte("bar"))
+	require.NoError(t, err)
+	assert.Equal(t, float64(6), sent.Value())
+
+	_, err = receivedWriter.Write([]byte("foobar"))
+	require.NoError(t, err)
+	assert.Equal(t, float64(6), received.Value())
+
+	assert.Equal(t, "foobar", sentBuf.String())
+	assert.Equal(t, "foobar", receivedBuf.String())
+
+	// A nil ConnMetrics records nothing.
+	var nilMetrics *ConnMetrics
+	assert.Equal(t, &sentBuf, nilMetrics.SentWriter(&sentBuf))
+}
+
 func newCollectingRetryMetrics() Registry {
 	return &standardRegistry{
 		serviceReqsCounter:          &counterMock{},
@@ -0,0 +1,454 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/types"
+	"github.com/traefik/traefik/v2/pkg/version"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/unit"
+	otelhistogram "go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+	processor "go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"google.golang.org/grpc/credentials"
+)
+
+var (
+	openTelemetryController *controller.Controller
+	openTelemetryExporter   *otlpmetric.Exporter
+	openTelemetryGauges     *gaugeCollector
+)
+
+// RegisterOpenTelemetry registers all OpenTelemetry metrics.
+func RegisterOpenTelemetry(ctx context.Context, config *types.OpenTelemetry) Registry {
+	if openTelemetryController == nil {
+		var err error
+		if openTelemetryController, err = newOpenTelemetryController(ctx, config); err != nil {
+			log.FromContext(ctx).Error(err)
+			return nil
+		}
+	}
+
+	if openTelemetryGauges == nil {
+		openTelemetryGauges = newGaugeCollector()
+	}
+
+	meter := openTelemetryController.Meter("github.com/traefik/traefik",
+		otelmetric.WithInstrumentationVersion(version.Version))
+
+	reg := &standardRegistry{
+		epEnabled:                      config.AddEntryPointsLabels,
+		routerEnabled:                  config.AddRoutersLabels,
+		svcEnabled:                     config.AddServicesLabels,
+		configReloadsCounter:           newOTLPCounterFrom(meter, configReloadsTotalName, "Config reloads"),
+		configReloadsFailureCounter:    newOTLPCounterFrom(meter, configReloadsFailuresTotalName, "Config reload failures"),
+		lastConfigReloadSuccessGauge:   newOTLPGaugeFrom(meter, configLastReloadSuccessName, "Last config reload success", unit.Unit("s")),
+		lastConfigReloadFailureGauge:   newOTLPGaugeFrom(meter, configLastReloadFailureName, "Last config reload failure", unit.Unit("s")),
+		tlsCertsNotAfterTimestampGauge: newOTLPGaugeFrom(meter, tlsCertsNotAfterTimestamp, "Certificate expiration timestamp", unit.Unit("s")),
+		logDroppedLinesCounter:         newOTLPCounterFrom(meter, logDroppedLinesTotalName, "How many log lines were dropped by the log sinks, partitioned by log."),
+	}
+
+	if config.AddEntryPointsLabels {
+		reg.entryPointReqsCounter = newOTLPCounterFrom(meter, entryPointReqsTotalName,
+			"How many HTTP requests processed on an entrypoint, partitioned by status code, protocol, and method.")
+		reg.entryPointReqsTLSCounter = newOTLPCounterFrom(meter, entryPointReqsTLSTotalName,
+			"How many HTTP requests with TLS processed on an entrypoint, partitioned by TLS Version and TLS cipher Used.")
+		reg.entryPointReqDurationHistogram, _ = NewHistogramWithScale(newOTLPHistogramFrom(meter, entryPointReqDurationName,
+			"How long it took to process the request on an entrypoint, partitioned by status code, protocol, and method.",
+			unit.Unit("s")), time.Second)
+		reg.entryPointOpenConnsGauge = newOTLPGaugeFrom(meter, entryPointOpenConnsName,
+			"How many open connections exist on an entrypoint, partitioned by method and protocol.",
+			unit.Dimensionless)
+		reg.entryPointConnsRefusedCounter = newOTLPCounterFrom(meter, entryPointConnsRefusedName,
+			"How many connections were refused by the connection limits of an entrypoint, partitioned by reason.")
+	}
+
+	if config.AddRoutersLabels {
+		reg.routerReqsCounter = newOTLPCounterFrom(meter, routerReqsTotalName,
+			"How many HTTP requests are processed on a router, partitioned by service, status code, protocol, and method.")
+		reg.routerReqsTLSCounter = newOTLPCounterFrom(meter, routerReqsTLSTotalName,
+			"How many HTTP requests with TLS are processed on a router, partitioned by service, TLS Version, and TLS cipher Used.")
+		reg.routerReqDurationHistogram, _ = NewHistogramWithScale(newOTLPHistogramFrom(meter, routerReqDurationName,
+			"How long it took to process the request on a router, partitioned by service, status code, protocol, and method.",
+			unit.Unit("s")), time.Second)
+		reg.routerOpenConnsGauge = newOTLPGaugeFrom(meter, routerOpenConnsName,
+			"How many open connections exist on a router, partitioned by service, method, and protocol.",
+			unit.Dimensionless)
+	}
+
+	if config.AddServicesLabels {
+		reg.serviceReqsCounter = newOTLPCounterFrom(meter, serviceReqsTotalName,
+			"How many HTTP requests processed on a service, partitioned by status code, protocol, and method.")
+		reg.serviceReqsTLSCounter = newOTLPCounterFrom(meter, serviceReqsTLSTotalName,
+			"How many HTTP requests with TLS processed on a service, partitioned by TLS version and TLS cipher.")
+		reg.serviceReqDurationHistogram, _ = NewHistogramWithScale(newOTLPHistogramFrom(meter, serviceReqDurationName,
+			"How long it took to process the request on a service, partitioned by status code, protocol, and method.",
+			unit.Unit("s")), time.Second)
+		reg.serviceOpenConnsGauge = newOTLPGaugeFrom(meter, serviceOpenConnsName,
+			"How many open connections exist on a service, partitioned by method and protocol.",
+			unit.Dimensionless)
+		reg.serviceRetriesCounter = newOTLPCounterFrom(meter, serviceRetriesTotalName,
+			"How many request retries happened on a service.")
+		reg.serviceServerUpGauge = newOTLPGaugeFrom(meter, serviceServerUpName,
+			"service server is up, described by gauge value of 0 or 1.",
+			unit.Dimensionless)
+		reg.serviceConnsOpenedCounter = newOTLPCounterFrom(meter, serviceConnsOpenedTotalName,
+			"How many TCP connections or UDP sessions were opened to a service, partitioned by protocol.")
+		reg.serviceConnsClosedCounter = newOTLPCounterFrom(meter, serviceConnsClosedTotalName,
+			"How many TCP connections or UDP sessions to a service were closed, partitioned by protocol.")
+		reg.serviceActiveConnsGauge = newOTLPGaugeFrom(meter, serviceActiveConnsName,
+			"How many TCP connections or UDP sessions to a service are active, partitioned by protocol.",
+			unit.Dimensionless)
+		reg.serviceBytesSentCounter = newOTLPCounterFrom(meter, serviceBytesSentTotalName,
+			"How many bytes were sent to the clients of a TCP or UDP service, partitioned by protocol.")
+		reg.serviceBytesReceivedCounter = newOTLPCounterFrom(meter, serviceBytesReceivedTotalName,
+			"How many bytes were received from the clients of a TCP or UDP service, partitioned by protocol.")
+		reg.serviceConnDurationHistogram, _ = NewHistogramWithScale(newOTLPHistogramFrom(meter, serviceConnDurationName,
+			"How long the TCP connections or UDP sessions to a service lasted, partitioned by protocol.",
+			unit.Unit("s")), time.Second)
+		reg.serviceDialFailuresCounter = newOTLPCounterFrom(meter, serviceDialFailuresTotalName,
+			"How many times a TCP or UDP service failed to connect to a server, partitioned by protocol and server address.")
+	}
+
+	return reg
+}
+
+// StopOpenTelemetry stops and resets Open-Telemetry client.
+func StopOpenTelemetry() {
+	if openTelemetryController == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := openTelemetryController.Stop(ctx); err != nil {
+		log.WithoutContext().Errorf("Unable to stop OpenTelemetry controller: %v", err)
+	}
+
+	if err := openTelemetryExporter.Shutdown(ctx); err != nil {
+		log.WithoutContext().Errorf("Unable to shutdown OpenTelemetry exporter: %v", err)
+	}
+
+	openTelemetryController = nil
+	openTelemetryExporter = nil
+	openTelemetryGauges = nil
+}
+
+// newOpenTelemetryController creates a new controller.Controller pushing metrics to the configured collector.
+func newOpenTelemetryController(ctx context.Context, config *types.OpenTelemetry) (*controller.Controller, error) {
+	var exporter *otlpmetric.Exporter
+	var err error
+	if config.GRPC != nil {
+		exporter, err = newGRPCExporter(ctx, config.GRPC)
+	} else {
+		exporter, err = newHTTPExporter(ctx, config.HTTP)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String("traefik"),
+			semconv.ServiceVersionKey.String(version.Version),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("building resource: %w", err)
+	}
+
+	opts := []controller.Option{
+		controller.WithExporter(exporter),
+		controller.WithResource(res),
+	}
+	if config.PushInterval > 0 {
+		opts = append(opts, controller.WithCollectPeriod(time.Duration(config.PushInterval)))
+	}
+
+	// Exported histograms are cumulative, hence the processor has to remember the past checkpoints.
+	ctrl := controller.New(
+		processor.NewFactory(
+			simple.NewWithHistogramDistribution(otelhistogram.WithExplicitBoundaries(config.ExplicitBoundaries)),
+			exporter,
+			processor.WithMemory(true),
+		),
+		opts...,
+	)
+
+	if err = ctrl.Start(ctx); err != nil {
+		return nil, fmt.Errorf("starting controller: %w", err)
+	}
+
+	openTelemetryExporter = exporter
+
+	return ctrl, nil
+}
+
+func newHTTPExporter(ctx context.Context, config *types.OpenTelemetryHTTP) (*otlpmetric.Exporter, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid collector endpoint %q: %w", config.Endpoint, err)
+	}
+
+	if (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid collector endpoint %q: the URL must have an http or https scheme and a host", config.Endpoint)
+	}
+
+	opts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(endpoint.Host),
+		otlpmetrichttp.WithHeaders(config.Headers),
+	}
+
+	if endpoint.Scheme == "http" {
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	}
+
+	if endpoint.Path != "" {
+		opts = append(opts, otlpmetrichttp.WithURLPath(endpoint.Path))
+	}
+
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.CreateTLSConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("creating TLS client config: %w", err)
+		}
+
+		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsConfig))
+	}
+
+	return otlpmetric.New(ctx, otlpmetrichttp.NewClient(opts...))
+}
+
+func newGRPCExporter(ctx context.Context, config *types.OpenTelemetryGRPC) (*otlpmetric.Exporter, error) {
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithEndpoint(config.Endpoint),
+		otlpmetricgrpc.WithHeaders(config.Headers),
+	}
+
+	if config.Insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.CreateTLSConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("creating TLS client config: %w", err)
+		}
+
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+	}
+
+	return otlpmetric.New(ctx, otlpmetricgrpc.NewClient(opts...))
+}
+
+func newOTLPCounterFrom(meter otelmetric.Meter, name, desc string) *otelCounter {
+	c, err := meter.NewFloat64Counter(name,
+		otelmetric.WithDescription(desc),
+		otelmetric.WithUnit(unit.Dimensionless),
+	)
+	if err != nil {
+		log.WithoutContext().Errorf("Unable to create the OpenTelemetry counter %s: %v", name, err)
+	}
+
+	return &otelCounter{
+		ip: c,
+	}
+}
+
+type otelCounter struct {
+	labelNamesValues otelLabelNamesValues
+	ip               otelmetric.Float64Counter
+}
+
+func (c *otelCounter) With(labelValues ...string) metrics.Counter {
+	return &otelCounter{
+		labelNamesValues: c.labelNamesValues.With(labelValues...),
+		ip:               c.ip,
+	}
+}
+
+func (c *otelCounter) Add(delta float64) {
+	c.ip.Add(context.Background(), delta, c.labelNamesValues.ToLabels()...)
+}
+
+// gaugeValue holds the last value set for a label set.
+type gaugeValue struct {
+	labels otelLabelNamesValues
+	value  float64
+}
+
+// gaugeCollector stores the gauge values, as synchronous gauges are not supported by OpenTelemetry.
+// The values are reported by an asynchronous GaugeObserver at collect time.
+type gaugeCollector struct {
+	mu     sync.Mutex
+	values map[string]map[string]gaugeValue
+}
+
+func newGaugeCollector() *gaugeCollector {
+	return &gaugeCollector{
+		values: make(map[string]map[string]gaugeValue),
+	}
+}
+
+func (c *gaugeCollector) add(name string, delta float64, labels otelLabelNamesValues) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := labels.key()
+
+	if _, exists := c.values[name]; !exists {
+		c.values[name] = map[string]gaugeValue{}
+	}
+
+	v := c.values[name][key]
+	c.values[name][key] = gaugeValue{
+		labels: labels,
+		value:  v.value + delta,
+	}
+}
+
+func (c *gaugeCollector) set(name string, value float64, labels otelLabelNamesValues) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.values[name]; !exists {
+		c.values[name] = map[string]gaugeValue{}
+	}
+
+	c.values[name][labels.key()] = gaugeValue{
+		labels: labels,
+		value:  value,
+	}
+}
+
+func (c *gaugeCollector) observe(name string, result otelmetric.Float64ObserverResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, v := range c.values[name] {
+		result.Observe(v.value, v.labels.ToLabels()...)
+	}
+}
+
+func newOTLPGaugeFrom(meter otelmetric.Meter, name, desc string, u unit.Unit) *otelGauge {
+	collector := openTelemetryGauges
+
+	_, err := meter.NewFloat64GaugeObserver(name,
+		func(_ context.Context, result otelmetric.Float64ObserverResult) {
+			collector.observe(name, result)
+		},
+		otelmetric.WithDescription(desc),
+		otelmetric.WithUnit(u),
+	)
+	if err != nil {
+		log.WithoutContext().Errorf("Unable to create the OpenTelemetry gauge %s: %v", name, err)
+	}
+
+	return &otelGauge{
+		name:      name,
+		collector: collector,
+	}
+}
+
+type otelGauge struct {
+	labelNamesValues otelLabelNamesValues
+	name             string
+	collector        *gaugeCollector
+}
+
+func (g *otelGauge) With(labelValues ...string) metrics.Gauge {
+	return &otelGauge{
+		labelNamesValues: g.labelNamesValues.With(labelValues...),
+		name:             g.name,
+		collector:        g.collector,
+	}
+}
+
+func (g *otelGauge) Add(delta float64) {
+	g.collector.add(g.name, delta, g.labelNamesValues)
+}
+
+func (g *otelGauge) Set(value float64) {
+	g.collector.set(g.name, value, g.labelNamesValues)
+}
+
+func newOTLPHistogramFrom(meter otelmetric.Meter, name, desc string, u unit.Unit) *otelHistogram {
+	h, err := meter.NewFloat64Histogram(name,
+		otelmetric.WithDescription(desc),
+		otelmetric.WithUnit(u),
+	)
+	if err != nil {
+		log.WithoutContext().Errorf("Unable to create the OpenTelemetry histogram %s: %v", name, err)
+	}
+
+	return &otelHistogram{
+		ip: h,
+	}
+}
+
+type otelHistogram struct {
+	labelNamesValues otelLabelNamesValues
+	ip               otelmetric.Float64Histogram
+}
+
+func (h *otelHistogram) With(labelValues ...string) metrics.Histogram {
+	return &otelHistogram{
+		labelNamesValues: h.labelNamesValues.With(labelValues...),
+		ip:               h.ip,
+	}
+}
+
+func (h *otelHistogram) Observe(incr float64) {
+	h.ip.Record(context.Background(), incr, h.labelNamesValues.ToLabels()...)
+}
+
+// otelLabelNamesValues is the equivalent of prometheus' labelNamesValues
+// but adapted to OpenTelemetry.
+type otelLabelNamesValues []string
+
+// With validates the input, and returns a new aggregate otelLabelNamesValues.
+func (lvs otelLabelNamesValues) With(labelValues ...string) otelLabelNamesValues {
+	if len(labelValues)%2 != 0 {
+		labelValues = append(labelValues, "unknown")
+	}
+
+	labels := make([]string, len(lvs)+len(labelValues))
+	n := copy(labels, lvs)
+	copy(labels[n:], labelValues)
+
+	return labels
+}
+
+// ToLabels is a convenience method to convert a otelLabelNamesValues
+// to the native attribute.KeyValue.
+func (lvs otelLabelNamesValues) ToLabels() []attribute.KeyValue {
+	labels := make([]attribute.KeyValue, len(lvs)/2)
+	for i := 0; i < len(labels); i++ {
+		labels[i] = attribute.String(lvs[2*i], lvs[2*i+1])
+	}
+
+	return labels
+}
+
+// key returns a unique key for the label names and values,
+// which are joined with a separator that cannot be part of them to avoid collisions.
+func (lvs otelLabelNamesValues) key() string {
+	return strings.Join(lvs, "\x00")
+}
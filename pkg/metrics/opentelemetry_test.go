@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/types"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+func TestOpenTelemetry(t *testing.T) {
+	t.Cleanup(func() {
+		StopOpenTelemetry()
+	})
+
+	received := make(chan *colmetricpb.ExportMetricsServiceRequest, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/v1/metrics", req.URL.Path)
+
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		exportReq := &colmetricpb.ExportMetricsServiceRequest{}
+		require.NoError(t, proto.Unmarshal(body, exportReq))
+
+		received <- exportReq
+	}))
+	t.Cleanup(srv.Close)
+
+	config := &types.OpenTelemetry{}
+	config.SetDefaults()
+	config.HTTP.Endpoint = srv.URL + "/v1/metrics"
+	config.PushInterval = ptypes.Duration(time.Hour)
+	config.ExplicitBoundaries = []float64{1, 10}
+	config.AddRoutersLabels = true
+
+	registry := RegisterOpenTelemetry(context.Background(), config)
+	require.NotNil(t, registry)
+
+	if !registry.IsEpEnabled() || !registry.IsRouterEnabled() || !registry.IsSvcEnabled() {
+		t.Errorf("OpenTelemetry registry should return true for IsEnabled(), IsRouterEnabled() and IsSvcEnabled()")
+	}
+
+	registry.ConfigReloadsCounter().Add(1)
+	registry.LastConfigReloadSuccessGauge().Set(1)
+	registry.EntryPointReqsCounter().With("code", "200", "method", http.MethodGet, "protocol", "http", "entrypoint", "http").Add(1)
+	registry.EntryPointReqDurationHistogram().With("code", "200", "method", http.MethodGet, "protocol", "http", "entrypoint", "http").Observe(5)
+	registry.EntryPointOpenConnsGauge().With("method", http.MethodGet, "protocol", "http", "entrypoint", "http").Set(1)
+	registry.RouterReqsCounter().With("router", "demo", "service", "test", "code", "200", "method", http.MethodGet, "protocol", "http").Add(1)
+	registry.ServiceReqsCounter().With("service", "test", "code", "200", "method", http.MethodGet, "protocol", "http").Add(1)
+	registry.ServiceOpenConnsGauge().With("service", "test", "method", http.MethodGet, "protocol", "http").Add(2)
+	registry.ServiceOpenConnsGauge().With("service", "test", "method", http.MethodGet, "protocol", "http").Add(-1)
+
+	StopOpenTelemetry()
+
+	var req *colmetricpb.ExportMetricsServiceRequest
+	select {
+	case req = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no metrics exported to the collector")
+	}
+
+	got := map[string]*metricpb.Metric{}
+	for _, rm := range req.ResourceMetrics {
+		for _, ilm := range rm.InstrumentationLibraryMetrics {
+			for _, m := range ilm.Metrics {
+				got[m.Name] = m
+			}
+		}
+	}
+
+	require.Contains(t, got, configReloadsTotalName)
+	assert.Equal(t, float64(1), got[configReloadsTotalName].GetSum().DataPoints[0].GetAsDouble())
+
+	require.Contains(t, got, configLastReloadSuccessName)
+	assert.Equal(t, float64(1), got[configLastReloadSuccessName].GetGauge().DataPoints[0].GetAsDouble())
+
+	require.Contains(t, got, entryPointReqsTotalName)
+	require.Contains(t, got, routerReqsTotalName)
+	require.Contains(t, got, serviceReqsTotalName)
+
+	require.Contains(t, got, entryPointReqDurationName)
+	histogram := got[entryPointReqDurationName].GetHistogram().DataPoints[0]
+	assert.Equal(t, []float64{1, 10}, histogram.ExplicitBounds)
+	assert.Equal(t, []uint64{0, 1, 0}, histogram.BucketCounts)
+	assert.Equal(t, float64(5), histogram.Sum)
+
+	require.Contains(t, got, serviceOpenConnsName)
+	assert.Equal(t, float64(1), got[serviceOpenConnsName].GetGauge().DataPoints[0].GetAsDouble())
+}
+
+func TestNewHTTPExporter_invalidEndpoint(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		endpoint string
+	}{
+		{
+			desc:     "without scheme",
+			endpoint: "collector:4318",
+		},
+		{
+			desc:     "unsupported scheme",
+			endpoint: "ftp://collector:4318/v1/metrics",
+		},
+		{
+			desc:     "without host",
+			endpoint: "http:///v1/metrics",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newHTTPExporter(context.Background(), &types.OpenTelemetryHTTP{Endpoint: test.endpoint})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestGaugeCollector_labelsCollision(t *testing.T) {
+	collector := newGaugeCollector()
+
+	collector.set("gauge", 1, otelLabelNamesValues{"service", "ab"})
+	collector.set("gauge", 2, otelLabelNamesValues{"servicea", "b"})
+	collector.add("gauge", 3, otelLabelNamesValues{"service", "ab"})
+
+	require.Len(t, collector.values["gauge"], 2)
+	assert.Equal(t, float64(4), collector.values["gauge"][otelLabelNamesValues{"service", "ab"}.key()].value)
+	assert.Equal(t, float64(2), collector.values["gauge"][otelLabelNamesValues{"servicea", "b"}.key()].value)
+}
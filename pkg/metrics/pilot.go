@@ -32,6 +32,15 @@ const (
 	pilotServiceOpenConnsName    = pilotServicePrefix + "OpenConnections"
 	pilotServiceRetriesTotalName = pilotServicePrefix + "RetriesTotal"
 	pilotServiceServerUpName     = pilotServicePrefix + "ServerUp"
+
+	// service level, for TCP and UDP.
+	pilotServiceConnsOpenedTotalName   = pilotServicePrefix + "ConnectionsOpenedTotal"
+	pilotServiceConnsClosedTotalName   = pilotServicePrefix + "ConnectionsClosedTotal"
+	pilotServiceActiveConnsName        = pilotServicePrefix + "ActiveConnections"
+	pilotServiceBytesSentTotalName     = pilotServicePrefix + "BytesSentTotal"
+	pilotServiceBytesReceivedTotalName = pilotServicePrefix + "BytesReceivedTotal"
+	pilotServiceConnDurationName       = pilotServicePrefix + "ConnectionDurationSeconds"
+	pilotServiceDialFailuresTotalName  = pilotServicePrefix + "DialFailuresTotal"
 )
 
 const root = "value"
@@ -67,6 +76,14 @@ func RegisterPilot() *PilotRegistry {
 	standardRegistry.serviceRetriesCounter = pr.newCounter(pilotServiceRetriesTotalName)
 	standardRegistry.serviceServerUpGauge = pr.newGauge(pilotServiceServerUpName)
 
+	standardRegistry.serviceConnsOpenedCounter = pr.newCounter(pilotServiceConnsOpenedTotalName)
+	standardRegistry.serviceConnsClosedCounter = pr.newCounter(pilotServiceConnsClosedTotalName)
+	standardRegistry.serviceActiveConnsGauge = pr.newGauge(pilotServiceActiveConnsName)
+	standardRegistry.serviceBytesSentCounter = pr.newCounter(pilotServiceBytesSentTotalName)
+	standardRegistry.serviceBytesReceivedCounter = pr.newCounter(pilotServiceBytesReceivedTotalName)
+	standardRegistry.serviceConnDurationHistogram, _ = NewHistogramWithScale(pr.newHistogram(pilotServiceConnDurationName), time.Second)
+	standardRegistry.serviceDialFailuresCounter = pr.newCounter(pilotServiceDialFailuresTotalName)
+
 	return pr
 }
 
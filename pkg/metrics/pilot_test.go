@@ -163,6 +163,35 @@ func TestPilotMetrics(t *testing.T) {
 		With("service", "service1", "url", "http://127.0.0.10:80").
 		Set(1)
 
+	pilotRegistry.
+		ServiceConnsOpenedCounter().
+		With("protocol", "tcp", "service", "service1").
+		Add(1)
+	pilotRegistry.
+		ServiceConnsClosedCounter().
+		With("protocol", "tcp", "service", "service1").
+		Add(1)
+	pilotRegistry.
+		ServiceActiveConnsGauge().
+		With("protocol", "tcp", "service", "service1").
+		Set(1)
+	pilotRegistry.
+		ServiceBytesSentCounter().
+		With("protocol", "tcp", "service", "service1").
+		Add(1)
+	pilotRegistry.
+		ServiceBytesReceivedCounter().
+		With("protocol", "tcp", "service", "service1").
+		Add(1)
+	pilotRegistry.
+		ServiceConnDurationHistogram().
+		With("protocol", "tcp", "service", "service1").
+		Observe(1)
+	pilotRegistry.
+		ServiceDialFailuresCounter().
+		With("protocol", "tcp", "service", "service1", "address", "127.0.0.10:80").
+		Add(1)
+
 	data := pilotRegistry.Data()
 
 	testCases := []struct {
@@ -259,6 +288,63 @@ func TestPilotMetrics(t *testing.T) {
 			},
 			assert: buildPilotGaugeAssert(t, pilotServiceServerUpName, 1),
 		},
+		{
+			name: pilotServiceConnsOpenedTotalName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"service":  "service1",
+			},
+			assert: buildPilotCounterAssert(t, pilotServiceConnsOpenedTotalName, 1),
+		},
+		{
+			name: pilotServiceConnsClosedTotalName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"service":  "service1",
+			},
+			assert: buildPilotCounterAssert(t, pilotServiceConnsClosedTotalName, 1),
+		},
+		{
+			name: pilotServiceActiveConnsName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"service":  "service1",
+			},
+			assert: buildPilotGaugeAssert(t, pilotServiceActiveConnsName, 1),
+		},
+		{
+			name: pilotServiceBytesSentTotalName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"service":  "service1",
+			},
+			assert: buildPilotCounterAssert(t, pilotServiceBytesSentTotalName, 1),
+		},
+		{
+			name: pilotServiceBytesReceivedTotalName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"service":  "service1",
+			},
+			assert: buildPilotCounterAssert(t, pilotServiceBytesReceivedTotalName, 1),
+		},
+		{
+			name: pilotServiceConnDurationName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"service":  "service1",
+			},
+			assert: buildPilotHistogramAssert(t, pilotServiceConnDurationName, 1),
+		},
+		{
+			name: pilotServiceDialFailuresTotalName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"service":  "service1",
+				"address":  "127.0.0.10:80",
+			},
+			assert: buildPilotCounterAssert(t, pilotServiceDialFailuresTotalName, 1),
+		},
 	}
 
 	for _, test := range testCases {
@@ -34,12 +34,17 @@ const (
 	metricsTLSPrefix          = MetricNamePrefix + "tls_"
 	tlsCertsNotAfterTimestamp = metricsTLSPrefix + "certs_not_after"
 
+	// log.
+	metricLogPrefix          = MetricNamePrefix + "log_"
+	logDroppedLinesTotalName = metricLogPrefix + "dropped_lines_total"
+
 	// entry point.
 	metricEntryPointPrefix     = MetricNamePrefix + "entrypoint_"
 	entryPointReqsTotalName    = metricEntryPointPrefix + "requests_total"
 	entryPointReqsTLSTotalName = metricEntryPointPrefix + "requests_tls_total"
 	entryPointReqDurationName  = metricEntryPointPrefix + "request_duration_seconds"
 	entryPointOpenConnsName    = metricEntryPointPrefix + "open_connections"
+	entryPointConnsRefusedName = metricEntryPointPrefix + "connections_refused_total"
 
 	// router level.
 	metricRouterPrefix     = MetricNamePrefix + "router_"
@@ -56,6 +61,15 @@ const (
 	serviceOpenConnsName    = metricServicePrefix + "open_connections"
 	serviceRetriesTotalName = metricServicePrefix + "retries_total"
 	serviceServerUpName     = metricServicePrefix + "server_up"
+
+	// TCP and UDP service level.
+	serviceConnsOpenedTotalName   = metricServicePrefix + "connections_opened_total"
+	serviceConnsClosedTotalName   = metricServicePrefix + "connections_closed_total"
+	serviceActiveConnsName        = metricServicePrefix + "active_connections"
+	serviceBytesSentTotalName     = metricServicePrefix + "bytes_sent_total"
+	serviceBytesReceivedTotalName = metricServicePrefix + "bytes_received_total"
+	serviceConnDurationName       = metricServicePrefix + "connection_duration_seconds"
+	serviceDialFailuresTotalName  = metricServicePrefix + "dial_failures_total"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -135,6 +149,10 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Name: tlsCertsNotAfterTimestamp,
 		Help: "Certificate expiration timestamp",
 	}, []string{"cn", "serial", "sans"})
+	logDroppedLines := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: logDroppedLinesTotalName,
+		Help: "How many log lines were dropped by the log sinks, partitioned by log.",
+	}, []string{"log"})
 
 	promState.describers = []func(chan<- *stdprometheus.Desc){
 		configReloads.cv.Describe,
@@ -142,6 +160,7 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		lastConfigReloadSuccess.gv.Describe,
 		lastConfigReloadFailure.gv.Describe,
 		tlsCertsNotAfterTimesptamp.gv.Describe,
+		logDroppedLines.cv.Describe,
 	}
 
 	reg := &standardRegistry{
@@ -153,6 +172,7 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		lastConfigReloadSuccessGauge:   lastConfigReloadSuccess,
 		lastConfigReloadFailureGauge:   lastConfigReloadFailure,
 		tlsCertsNotAfterTimestampGauge: tlsCertsNotAfterTimesptamp,
+		logDroppedLinesCounter:         logDroppedLines,
 	}
 
 	if config.AddEntryPointsLabels {
@@ -173,18 +193,24 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: entryPointOpenConnsName,
 			Help: "How many open connections exist on an entrypoint, partitioned by method and protocol.",
 		}, []string{"method", "protocol", "entrypoint"})
+		entryPointConnsRefused := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: entryPointConnsRefusedName,
+			Help: "How many connections were refused by the connection limits of an entrypoint, partitioned by reason.",
+		}, []string{"reason", "entrypoint"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			entryPointReqs.cv.Describe,
 			entryPointReqsTLS.cv.Describe,
 			entryPointReqDurations.hv.Describe,
 			entryPointOpenConns.gv.Describe,
+			entryPointConnsRefused.cv.Describe,
 		}...)
 
 		reg.entryPointReqsCounter = entryPointReqs
 		reg.entryPointReqsTLSCounter = entryPointReqsTLS
 		reg.entryPointReqDurationHistogram, _ = NewHistogramWithScale(entryPointReqDurations, time.Second)
 		reg.entryPointOpenConnsGauge = entryPointOpenConns
+		reg.entryPointConnsRefusedCounter = entryPointConnsRefused
 	}
 
 	if config.AddRoutersLabels {
@@ -245,6 +271,36 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Help: "service server is up, described by gauge value of 0 or 1.",
 		}, []string{"service", "url"})
 
+		serviceConnsOpened := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceConnsOpenedTotalName,
+			Help: "How many TCP connections or UDP sessions were opened to a service, partitioned by protocol.",
+		}, []string{"protocol", "service"})
+		serviceConnsClosed := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceConnsClosedTotalName,
+			Help: "How many TCP connections or UDP sessions to a service were closed, partitioned by protocol.",
+		}, []string{"protocol", "service"})
+		serviceActiveConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: serviceActiveConnsName,
+			Help: "How many TCP connections or UDP sessions to a service are active, partitioned by protocol.",
+		}, []string{"protocol", "service"})
+		serviceBytesSent := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceBytesSentTotalName,
+			Help: "How many bytes were sent to the clients of a TCP or UDP service, partitioned by protocol.",
+		}, []string{"protocol", "service"})
+		serviceBytesReceived := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceBytesReceivedTotalName,
+			Help: "How many bytes were received from the clients of a TCP or UDP service, partitioned by protocol.",
+		}, []string{"protocol", "service"})
+		serviceConnDurations := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+			Name:    serviceConnDurationName,
+			Help:    "How long the TCP connections or UDP sessions to a service lasted, partitioned by protocol.",
+			Buckets: buckets,
+		}, []string{"protocol", "service"})
+		serviceDialFailures := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceDialFailuresTotalName,
+			Help: "How many times a TCP or UDP service failed to connect to a server, partitioned by protocol and server address.",
+		}, []string{"protocol", "service", "address"})
+
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			serviceReqs.cv.Describe,
 			serviceReqsTLS.cv.Describe,
@@ -252,6 +308,13 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			serviceOpenConns.gv.Describe,
 			serviceRetries.cv.Describe,
 			serviceServerUp.gv.Describe,
+			serviceConnsOpened.cv.Describe,
+			serviceConnsClosed.cv.Describe,
+			serviceActiveConns.gv.Describe,
+			serviceBytesSent.cv.Describe,
+			serviceBytesReceived.cv.Describe,
+			serviceConnDurations.hv.Describe,
+			serviceDialFailures.cv.Describe,
 		}...)
 
 		reg.serviceReqsCounter = serviceReqs
@@ -260,6 +323,13 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		reg.serviceOpenConnsGauge = serviceOpenConns
 		reg.serviceRetriesCounter = serviceRetries
 		reg.serviceServerUpGauge = serviceServerUp
+		reg.serviceConnsOpenedCounter = serviceConnsOpened
+		reg.serviceConnsClosedCounter = serviceConnsClosed
+		reg.serviceActiveConnsGauge = serviceActiveConns
+		reg.serviceBytesSentCounter = serviceBytesSent
+		reg.serviceBytesReceivedCounter = serviceBytesReceived
+		reg.serviceConnDurationHistogram, _ = NewHistogramWithScale(serviceConnDurations, time.Second)
+		reg.serviceDialFailuresCounter = serviceDialFailures
 	}
 
 	return reg
@@ -298,7 +368,7 @@ func OnConfigurationUpdate(conf dynamic.Configuration, entryPoints []string) {
 	}
 
 	for serviceName, service := range conf.HTTP.Services {
-		dynamicConfig.services[serviceName] = make(map[string]bool)
+		dynamicConfig.addService(serviceName)
 		if service.LoadBalancer != nil {
 			for _, server := range service.LoadBalancer.Servers {
 				dynamicConfig.services[serviceName][server.URL] = true
@@ -306,6 +376,36 @@ func OnConfigurationUpdate(conf dynamic.Configuration, entryPoints []string) {
 		}
 	}
 
+	if conf.TCP != nil {
+		for name := range conf.TCP.Routers {
+			dynamicConfig.routers[name] = true
+		}
+
+		for serviceName, service := range conf.TCP.Services {
+			dynamicConfig.addService(serviceName)
+			if service.LoadBalancer != nil {
+				for _, server := range service.LoadBalancer.Servers {
+					dynamicConfig.services[serviceName][server.Address] = true
+				}
+			}
+		}
+	}
+
+	if conf.UDP != nil {
+		for name := range conf.UDP.Routers {
+			dynamicConfig.routers[name] = true
+		}
+
+		for serviceName, service := range conf.UDP.Services {
+			dynamicConfig.addService(serviceName)
+			if service.LoadBalancer != nil {
+				for _, server := range service.LoadBalancer.Servers {
+					dynamicConfig.services[serviceName][server.Address] = true
+				}
+			}
+		}
+	}
+
 	promState.SetDynamicConfig(dynamicConfig)
 }
 
@@ -394,6 +494,9 @@ func (ps *prometheusState) isOutdated(collector *collector) bool {
 		if url, ok := labels["url"]; ok && !ps.dynamicConfig.hasServerURL(serviceName, url) {
 			return true
 		}
+		if address, ok := labels["address"]; ok && !ps.dynamicConfig.hasServerURL(serviceName, address) {
+			return true
+		}
 	}
 
 	return false
@@ -422,6 +525,13 @@ func (d *dynamicConfig) hasEntryPoint(entrypointName string) bool {
 	return ok
 }
 
+// addService adds a service, keeping the servers of an HTTP, TCP or UDP service with the same name.
+func (d *dynamicConfig) addService(serviceName string) {
+	if _, ok := d.services[serviceName]; !ok {
+		d.services[serviceName] = make(map[string]bool)
+	}
+}
+
 func (d *dynamicConfig) hasService(serviceName string) bool {
 	_, ok := d.services[serviceName]
 	return ok
@@ -3,6 +3,7 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"testing"
@@ -121,6 +122,11 @@ func TestPrometheus(t *testing.T) {
 		With("cn", "value", "serial", "value", "sans", "value").
 		Set(float64(time.Now().Unix()))
 
+	prometheusRegistry.
+		LogDroppedLinesCounter().
+		With("log", "access").
+		Add(1)
+
 	prometheusRegistry.
 		EntryPointReqsCounter().
 		With("code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http", "entrypoint", "http").
@@ -133,6 +139,10 @@ func TestPrometheus(t *testing.T) {
 		EntryPointOpenConnsGauge().
 		With("method", http.MethodGet, "protocol", "http", "entrypoint", "http").
 		Set(1)
+	prometheusRegistry.
+		EntryPointConnsRefusedCounter().
+		With("reason", "max_connections", "entrypoint", "http").
+		Add(1)
 
 	prometheusRegistry.
 		RouterReqsCounter().
@@ -176,6 +186,14 @@ func TestPrometheus(t *testing.T) {
 		With("service", "service1", "url", "http://127.0.0.10:80").
 		Set(1)
 
+	connMetrics := NewConnMetrics(prometheusRegistry, "tcp", "service1")
+	connMetrics.Opened()
+	connMetrics.Opened()
+	connMetrics.Closed(time.Now())
+	_, _ = connMetrics.SentWriter(io.Discard).Write(make([]byte, 10))
+	_, _ = connMetrics.ReceivedWriter(io.Discard).Write(make([]byte, 5))
+	connMetrics.DialFailed("127.0.0.10:80")
+
 	delayForTrackingCompletion()
 
 	metricsFamilies := mustScrape()
@@ -210,6 +228,13 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildTimestampAssert(t, tlsCertsNotAfterTimestamp),
 		},
+		{
+			name: logDroppedLinesTotalName,
+			labels: map[string]string{
+				"log": "access",
+			},
+			assert: buildCounterAssert(t, logDroppedLinesTotalName, 1),
+		},
 		{
 			name: entryPointReqsTotalName,
 			labels: map[string]string{
@@ -239,6 +264,14 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, entryPointOpenConnsName, 1),
 		},
+		{
+			name: entryPointConnsRefusedName,
+			labels: map[string]string{
+				"reason":     "max_connections",
+				"entrypoint": "http",
+			},
+			assert: buildCounterAssert(t, entryPointConnsRefusedName, 1),
+		},
 		{
 			name: routerReqsTotalName,
 			labels: map[string]string{
@@ -334,6 +367,63 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, serviceServerUpName, 1),
 		},
+		{
+			name: serviceConnsOpenedTotalName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"service":  "service1",
+			},
+			assert: buildCounterAssert(t, serviceConnsOpenedTotalName, 2),
+		},
+		{
+			name: serviceConnsClosedTotalName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"service":  "service1",
+			},
+			assert: buildCounterAssert(t, serviceConnsClosedTotalName, 1),
+		},
+		{
+			name: serviceActiveConnsName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"service":  "service1",
+			},
+			assert: buildGaugeAssert(t, serviceActiveConnsName, 1),
+		},
+		{
+			name: serviceBytesSentTotalName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"service":  "service1",
+			},
+			assert: buildCounterAssert(t, serviceBytesSentTotalName, 10),
+		},
+		{
+			name: serviceBytesReceivedTotalName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"service":  "service1",
+			},
+			assert: buildCounterAssert(t, serviceBytesReceivedTotalName, 5),
+		},
+		{
+			name: serviceConnDurationName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"service":  "service1",
+			},
+			assert: buildHistogramAssert(t, serviceConnDurationName, 1),
+		},
+		{
+			name: serviceDialFailuresTotalName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"service":  "service1",
+				"address":  "127.0.0.10:80",
+			},
+			assert: buildCounterAssert(t, serviceDialFailuresTotalName, 1),
+		},
 	}
 
 	for _, test := range testCases {
@@ -429,6 +519,91 @@ func TestPrometheusMetricRemoval(t *testing.T) {
 	assertMetricsExist(t, mustScrape(), routerReqsTotalName)
 }
 
+func TestPrometheusTCPMetricRemoval(t *testing.T) {
+	promState = newPrometheusState()
+	promRegistry = prometheus.NewRegistry()
+	// Reset state of global promState.
+	defer promState.reset()
+
+	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddServicesLabels: true})
+	defer promRegistry.Unregister(promState)
+
+	conf := dynamic.Configuration{
+		HTTP: th.BuildConfiguration(),
+		TCP: &dynamic.TCPConfiguration{
+			Services: map[string]*dynamic.TCPService{
+				"bar@providerName": {
+					LoadBalancer: &dynamic.TCPServersLoadBalancer{
+						Servers: []dynamic.TCPServer{{Address: "localhost:9000"}},
+					},
+				},
+			},
+		},
+	}
+
+	OnConfigurationUpdate(conf, nil)
+
+	// Metrics of unknown services or servers should be removed after the first scrape.
+	NewConnMetrics(prometheusRegistry, "tcp", "service2").Opened()
+	NewConnMetrics(prometheusRegistry, "tcp", "bar@providerName").DialFailed("localhost:9999")
+
+	assertMetricsExist(t, mustScrape(), serviceConnsOpenedTotalName, serviceDialFailuresTotalName)
+	assertMetricsAbsent(t, mustScrape(), serviceConnsOpenedTotalName, serviceDialFailuresTotalName)
+
+	NewConnMetrics(prometheusRegistry, "tcp", "bar@providerName").Opened()
+	NewConnMetrics(prometheusRegistry, "tcp", "bar@providerName").DialFailed("localhost:9000")
+
+	delayForTrackingCompletion()
+
+	assertMetricsExist(t, mustScrape(), serviceConnsOpenedTotalName, serviceDialFailuresTotalName)
+	assertMetricsExist(t, mustScrape(), serviceConnsOpenedTotalName, serviceDialFailuresTotalName)
+}
+
+func TestPrometheusSameNameServicesMetrics(t *testing.T) {
+	promState = newPrometheusState()
+	promRegistry = prometheus.NewRegistry()
+	// Reset state of global promState.
+	defer promState.reset()
+
+	RegisterPrometheus(context.Background(), &types.Prometheus{AddServicesLabels: true})
+	defer promRegistry.Unregister(promState)
+
+	conf := dynamic.Configuration{
+		HTTP: th.BuildConfiguration(
+			th.WithLoadBalancerServices(th.WithService("bar@providerName",
+				th.WithServers(th.WithServer("http://localhost:9000"))),
+			),
+		),
+		TCP: &dynamic.TCPConfiguration{
+			Services: map[string]*dynamic.TCPService{
+				"bar@providerName": {
+					LoadBalancer: &dynamic.TCPServersLoadBalancer{
+						Servers: []dynamic.TCPServer{{Address: "localhost:9001"}},
+					},
+				},
+			},
+		},
+		UDP: &dynamic.UDPConfiguration{
+			Services: map[string]*dynamic.UDPService{
+				"bar@providerName": {
+					LoadBalancer: &dynamic.UDPServersLoadBalancer{
+						Servers: []dynamic.UDPServer{{Address: "localhost:9002"}},
+					},
+				},
+			},
+		},
+	}
+
+	OnConfigurationUpdate(conf, nil)
+
+	promState.mtx.Lock()
+	defer promState.mtx.Unlock()
+
+	assert.True(t, promState.dynamicConfig.hasServerURL("bar@providerName", "http://localhost:9000"))
+	assert.True(t, promState.dynamicConfig.hasServerURL("bar@providerName", "localhost:9001"))
+	assert.True(t, promState.dynamicConfig.hasServerURL("bar@providerName", "localhost:9002"))
+}
+
 func TestPrometheusRemovedMetricsReset(t *testing.T) {
 	// Reset state of global promState.
 	defer promState.reset()
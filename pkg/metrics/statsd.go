@@ -23,11 +23,13 @@ const (
 	statsdLastConfigReloadFailureName = "config.reload.lastFailureTimestamp"
 
 	statsdTLSCertsNotAfterTimestampName = "tls.certs.notAfterTimestamp"
+	statsdLogDroppedLinesName           = "log.dropped.lines.total"
 
-	statsdEntryPointReqsName        = "entrypoint.request.total"
-	statsdEntryPointReqsTLSName     = "entrypoint.request.tls.total"
-	statsdEntryPointReqDurationName = "entrypoint.request.duration"
-	statsdEntryPointOpenConnsName   = "entrypoint.connections.open"
+	statsdEntryPointReqsName         = "entrypoint.request.total"
+	statsdEntryPointReqsTLSName      = "entrypoint.request.tls.total"
+	statsdEntryPointReqDurationName  = "entrypoint.request.duration"
+	statsdEntryPointOpenConnsName    = "entrypoint.connections.open"
+	statsdEntryPointConnsRefusedName = "entrypoint.connections.refused.total"
 
 	statsdRouterReqsName         = "router.request.total"
 	statsdRouterReqsTLSName      = "router.request.tls.total"
@@ -40,6 +42,14 @@ const (
 	statsdServiceRetriesTotalName = "service.retries.total"
 	statsdServiceServerUpName     = "service.server.up"
 	statsdServiceOpenConnsName    = "service.connections.open"
+
+	statsdServiceConnsOpenedName   = "service.connections.opened.total"
+	statsdServiceConnsClosedName   = "service.connections.closed.total"
+	statsdServiceActiveConnsName   = "service.connections.active"
+	statsdServiceBytesSentName     = "service.bytes.sent.total"
+	statsdServiceBytesReceivedName = "service.bytes.received.total"
+	statsdServiceConnDurationName  = "service.connections.duration"
+	statsdServiceDialFailuresName  = "service.dial.failures.total"
 )
 
 // RegisterStatsd registers the metrics pusher if this didn't happen yet and creates a statsd Registry instance.
@@ -64,6 +74,7 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		lastConfigReloadSuccessGauge:   statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
 		lastConfigReloadFailureGauge:   statsdClient.NewGauge(statsdLastConfigReloadFailureName),
 		tlsCertsNotAfterTimestampGauge: statsdClient.NewGauge(statsdTLSCertsNotAfterTimestampName),
+		logDroppedLinesCounter:         statsdClient.NewCounter(statsdLogDroppedLinesName, 1.0),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -72,6 +83,7 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.entryPointReqsTLSCounter = statsdClient.NewCounter(statsdEntryPointReqsTLSName, 1.0)
 		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdEntryPointReqDurationName, 1.0), time.Millisecond)
 		registry.entryPointOpenConnsGauge = statsdClient.NewGauge(statsdEntryPointOpenConnsName)
+		registry.entryPointConnsRefusedCounter = statsdClient.NewCounter(statsdEntryPointConnsRefusedName, 1.0)
 	}
 
 	if config.AddRoutersLabels {
@@ -90,6 +102,13 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.serviceRetriesCounter = statsdClient.NewCounter(statsdServiceRetriesTotalName, 1.0)
 		registry.serviceOpenConnsGauge = statsdClient.NewGauge(statsdServiceOpenConnsName)
 		registry.serviceServerUpGauge = statsdClient.NewGauge(statsdServiceServerUpName)
+		registry.serviceConnsOpenedCounter = statsdClient.NewCounter(statsdServiceConnsOpenedName, 1.0)
+		registry.serviceConnsClosedCounter = statsdClient.NewCounter(statsdServiceConnsClosedName, 1.0)
+		registry.serviceActiveConnsGauge = statsdClient.NewGauge(statsdServiceActiveConnsName)
+		registry.serviceBytesSentCounter = statsdClient.NewCounter(statsdServiceBytesSentName, 1.0)
+		registry.serviceBytesReceivedCounter = statsdClient.NewCounter(statsdServiceBytesReceivedName, 1.0)
+		registry.serviceConnDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdServiceConnDurationName, 1.0), time.Millisecond)
+		registry.serviceDialFailuresCounter = statsdClient.NewCounter(statsdServiceDialFailuresName, 1.0)
 	}
 
 	return registry
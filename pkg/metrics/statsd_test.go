@@ -59,6 +59,7 @@ func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 		metricsPrefix + ".entrypoint.request.tls.total:1.000000|c\n",
 		metricsPrefix + ".entrypoint.request.duration:10000.000000|ms",
 		metricsPrefix + ".entrypoint.connections.open:1.000000|g\n",
+		metricsPrefix + ".entrypoint.connections.refused.total:1.000000|c\n",
 
 		metricsPrefix + ".router.request.total:2.000000|c\n",
 		metricsPrefix + ".router.request.tls.total:1.000000|c\n",
@@ -71,6 +72,13 @@ func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 		metricsPrefix + ".service.connections.open:1.000000|g\n",
 		metricsPrefix + ".service.retries.total:2.000000|c\n",
 		metricsPrefix + ".service.server.up:1.000000|g\n",
+		metricsPrefix + ".service.connections.opened.total:1.000000|c\n",
+		metricsPrefix + ".service.connections.closed.total:1.000000|c\n",
+		metricsPrefix + ".service.connections.active:1.000000|g\n",
+		metricsPrefix + ".service.bytes.sent.total:10.000000|c\n",
+		metricsPrefix + ".service.bytes.received.total:5.000000|c\n",
+		metricsPrefix + ".service.connections.duration:10000.000000|ms",
+		metricsPrefix + ".service.dial.failures.total:1.000000|c\n",
 	}
 
 	udp.ShouldReceiveAll(t, expected, func() {
@@ -85,6 +93,7 @@ func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 		registry.EntryPointReqsTLSCounter().With("entrypoint", "test", "tls_version", "foo", "tls_cipher", "bar").Add(1)
 		registry.EntryPointReqDurationHistogram().With("entrypoint", "test").Observe(10000)
 		registry.EntryPointOpenConnsGauge().With("entrypoint", "test").Set(1)
+		registry.EntryPointConnsRefusedCounter().With("entrypoint", "test", "reason", "max_connections").Add(1)
 
 		registry.RouterReqsCounter().With("router", "demo", "service", "test", "code", strconv.Itoa(http.StatusNotFound), "method", http.MethodGet).Add(1)
 		registry.RouterReqsCounter().With("router", "demo", "service", "test", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).Add(1)
@@ -100,5 +109,12 @@ func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 		registry.ServiceRetriesCounter().With("service", "test").Add(1)
 		registry.ServiceRetriesCounter().With("service", "test").Add(1)
 		registry.ServiceServerUpGauge().With("service:test", "url", "http://127.0.0.1").Set(1)
+		registry.ServiceConnsOpenedCounter().With("protocol", "tcp", "service", "test").Add(1)
+		registry.ServiceConnsClosedCounter().With("protocol", "tcp", "service", "test").Add(1)
+		registry.ServiceActiveConnsGauge().With("protocol", "tcp", "service", "test").Set(1)
+		registry.ServiceBytesSentCounter().With("protocol", "tcp", "service", "test").Add(10)
+		registry.ServiceBytesReceivedCounter().With("protocol", "tcp", "service", "test").Add(5)
+		registry.ServiceConnDurationHistogram().With("protocol", "tcp", "service", "test").Observe(10000)
+		registry.ServiceDialFailuresCounter().With("protocol", "tcp", "service", "test", "address", "127.0.0.1:80").Add(1)
 	})
 }
@@ -75,6 +75,14 @@ const (
 	TLSVersion = "TLSVersion"
 	// TLSCipher is the cipher used in the request.
 	TLSCipher = "TLSCipher"
+	// TLSServerName is the server name indicated by the client in the TLS handshake (SNI), for TCP connections.
+	TLSServerName = "TLSServerName"
+
+	// CloseReason is the map key used for the reason why a TCP connection or a UDP session was closed.
+	CloseReason = "CloseReason"
+
+	// ProxyProtocolTLVs is the map key used for the values of the TLVs of the Proxy Protocol header of the client, by TLV.
+	ProxyProtocolTLVs = "ProxyProtocolTLVs"
 )
 
 // These are written out in the default case when no config is provided to specify keys of interest.
@@ -118,6 +126,9 @@ func init() {
 	allCoreKeys[RetryAttempts] = struct{}{}
 	allCoreKeys[TLSVersion] = struct{}{}
 	allCoreKeys[TLSCipher] = struct{}{}
+	allCoreKeys[TLSServerName] = struct{}{}
+	allCoreKeys[CloseReason] = struct{}{}
+	allCoreKeys[ProxyProtocolTLVs] = struct{}{}
 }
 
 // CoreLogData holds the fields computed from the request/response.
@@ -19,6 +19,7 @@ import (
 	"github.com/sirupsen/logrus"
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/proxyprotocol"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
 	"github.com/traefik/traefik/v2/pkg/types"
 )
@@ -26,7 +27,7 @@ import (
 type key string
 
 const (
-	// DataTableKey is the key within the request context used to store the Log Data Table.
+	// DataTableKey is the key within the request context, or the UDP session context, used to store the Log Data Table.
 	DataTableKey key = "LogDataTable"
 
 	// CommonFormat is the common logging format (CLF).
@@ -51,6 +52,8 @@ func (n noopCloser) Close() error {
 
 type handlerParams struct {
 	logDataTable *LogData
+	// connection is true when the log data describes a TCP connection or a UDP session.
+	connection bool
 }
 
 // Handler will write each request and its response to the access log.
@@ -76,7 +79,14 @@ func WrapHandler(handler *Handler) alice.Constructor {
 // NewHandler creates a new Handler.
 func NewHandler(config *types.AccessLog) (*Handler, error) {
 	var file io.WriteCloser = noopCloser{os.Stdout}
-	if len(config.FilePath) > 0 {
+	switch {
+	case config.Sink != nil:
+		sink, err := config.Sink.CreateSink(context.Background(), "access")
+		if err != nil {
+			return nil, fmt.Errorf("error creating access log sink: %w", err)
+		}
+		file = sink
+	case len(config.FilePath) > 0:
 		f, err := openAccessLogFile(config.FilePath)
 		if err != nil {
 			return nil, fmt.Errorf("error opening access log file: %w", err)
@@ -135,6 +145,10 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 		go func() {
 			defer logHandler.wg.Done()
 			for handlerParams := range logHandler.logHandlerChan {
+				if handlerParams.connection {
+					logHandler.logTheConnection(handlerParams.logDataTable)
+					continue
+				}
 				logHandler.logTheRoundTrip(handlerParams.logDataTable)
 			}
 		}()
@@ -222,6 +236,10 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http
 		core[ClientHost] = forwardedFor
 	}
 
+	if tlvs := proxyprotocol.TLVsFromContext(req.Context()); len(tlvs) > 0 {
+		core[ProxyProtocolTLVs] = proxyprotocol.Values(tlvs)
+	}
+
 	crw := newCaptureResponseWriter(rw)
 
 	next.ServeHTTP(crw, reqWithDataTable)
@@ -257,7 +275,7 @@ func (h *Handler) Close() error {
 
 // Rotate closes and reopens the log file to allow for rotation by an external source.
 func (h *Handler) Rotate() error {
-	if h.config.FilePath == "" {
+	if h.config.FilePath == "" || h.config.Sink != nil {
 		return nil
 	}
 
@@ -326,22 +344,26 @@ func (h *Handler) logTheRoundTrip(logDataTable *LogData) {
 			core[Overhead] = totalDuration - origin.(time.Duration)
 		}
 
-		fields := logrus.Fields{}
+		h.writeLogData(logDataTable)
+	}
+}
+
+func (h *Handler) writeLogData(logDataTable *LogData) {
+	fields := logrus.Fields{}
 
-		for k, v := range logDataTable.Core {
-			if h.config.Fields.Keep(k) {
-				fields[k] = v
-			}
+	for k, v := range logDataTable.Core {
+		if h.config.Fields.Keep(k) {
+			fields[k] = v
 		}
+	}
 
-		h.redactHeaders(logDataTable.Request.headers, fields, "request_")
-		h.redactHeaders(logDataTable.OriginResponse, fields, "origin_")
-		h.redactHeaders(logDataTable.DownstreamResponse.headers, fields, "downstream_")
+	h.redactHeaders(logDataTable.Request.headers, fields, "request_")
+	h.redactHeaders(logDataTable.OriginResponse, fields, "origin_")
+	h.redactHeaders(logDataTable.DownstreamResponse.headers, fields, "downstream_")
 
-		h.mu.Lock()
-		defer h.mu.Unlock()
-		h.logger.WithFields(fields).Println()
-	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.logger.WithFields(fields).Println()
 }
 
 func (h *Handler) redactHeaders(headers http.Header, fields logrus.Fields, prefix string) {
@@ -0,0 +1,286 @@
+package accesslog
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/proxyprotocol"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+)
+
+// Reasons why a TCP connection or a UDP session was closed.
+const (
+	closeReasonClientClosed       = "client_closed"
+	closeReasonBackendClosed      = "backend_closed"
+	closeReasonClientError        = "client_error"
+	closeReasonTimeout            = "timeout"
+	closeReasonBackendUnreachable = "backend_unreachable"
+	closeReasonIdleTimeout        = "idle_timeout"
+	closeReasonRejected           = "rejected"
+	closeReasonClosed             = "closed"
+)
+
+// TCPFieldApply function hook to add data in accesslog for TCP connections.
+type TCPFieldApply func(conn tcp.WriteCloser, next tcp.Handler, data *LogData)
+
+// TCPFieldHandler sends a new field to the logger for TCP connections.
+type TCPFieldHandler struct {
+	next    tcp.Handler
+	name    string
+	value   string
+	applyFn TCPFieldApply
+}
+
+// NewTCPFieldHandler creates a Field handler for TCP connections.
+func NewTCPFieldHandler(next tcp.Handler, name, value string, applyFn TCPFieldApply) tcp.Handler {
+	return &TCPFieldHandler{next: next, name: name, value: value, applyFn: applyFn}
+}
+
+// ServeTCP implements the tcp.Handler interface.
+func (f *TCPFieldHandler) ServeTCP(conn tcp.WriteCloser) {
+	table := GetTCPLogData(conn)
+	if table == nil {
+		f.next.ServeTCP(conn)
+		return
+	}
+
+	table.Core[f.name] = f.value
+
+	if f.applyFn != nil {
+		f.applyFn(conn, f.next, table)
+	} else {
+		f.next.ServeTCP(conn)
+	}
+}
+
+// AddTCPServiceFields add service fields for TCP connections.
+func AddTCPServiceFields(conn tcp.WriteCloser, next tcp.Handler, data *LogData) {
+	if addr, ok := data.Core[ServiceAddr].(string); ok {
+		data.Core[ServiceURL] = (&url.URL{Scheme: "tcp", Host: addr}).String()
+	}
+
+	next.ServeTCP(conn)
+}
+
+// WrapTCPHandler Wraps access log handler into a TCP middleware constructor.
+func WrapTCPHandler(handler *Handler) tcp.Constructor {
+	return func(next tcp.Handler) (tcp.Handler, error) {
+		return tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+			handler.ServeTCP(conn, next)
+		}), nil
+	}
+}
+
+// GetTCPLogData gets the object that contains logging data for a TCP connection.
+// This creates data as the connection passes through the handler chain.
+// The connection may be wrapped, e.g. by a load balancer, in which case it is unwrapped.
+func GetTCPLogData(conn tcp.WriteCloser) *LogData {
+	for {
+		switch c := conn.(type) {
+		case *captureConn:
+			return c.logData
+		case interface{ Unwrap() tcp.WriteCloser }:
+			conn = c.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+// ServeTCP logs the given TCP connection once it has been handled by next.
+func (h *Handler) ServeTCP(conn tcp.WriteCloser, next tcp.Handler) {
+	now := time.Now().UTC()
+
+	core := CoreLogData{
+		StartUTC:   now,
+		StartLocal: now.Local(),
+	}
+
+	logDataTable := &LogData{Core: core}
+
+	core[RequestCount] = nextRequestCount()
+	core[RequestProtocol] = "TCP"
+	core[RequestScheme] = "tcp"
+
+	remoteAddr := conn.RemoteAddr().String()
+	core[ClientAddr] = remoteAddr
+	core[ClientHost], core[ClientPort] = silentSplitHostPort(remoteAddr)
+
+	if c, ok := conn.(*tcp.Conn); ok && c.ServerName != "" {
+		core[TLSServerName] = c.ServerName
+	}
+
+	if tlvs := proxyprotocol.GetTLVs(conn.RemoteAddr()); len(tlvs) > 0 {
+		core[ProxyProtocolTLVs] = proxyprotocol.Values(tlvs)
+	}
+
+	ccn := &captureConn{WriteCloser: conn, logData: logDataTable}
+
+	next.ServeTCP(ccn)
+
+	// The TLS handshake, if any, happened while the connection was being served.
+	if tlsConn, ok := tcp.GetTLSConn(conn); ok {
+		state := tlsConn.ConnectionState()
+		if state.HandshakeComplete {
+			core[RequestScheme] = "tls"
+			core[TLSVersion] = traefiktls.GetVersion(&state)
+			core[TLSCipher] = traefiktls.GetCipherName(&state)
+			core[TLSServerName] = state.ServerName
+		}
+	}
+
+	logDataTable.Request.size = ccn.bytesRead()
+	logDataTable.DownstreamResponse.size = ccn.bytesWritten()
+	core[CloseReason] = ccn.closeReason()
+
+	if h.config.BufferingSize > 0 {
+		h.logHandlerChan <- handlerParams{
+			logDataTable: logDataTable,
+			connection:   true,
+		}
+	} else {
+		h.logTheConnection(logDataTable)
+	}
+}
+
+// logTheConnection logs a TCP connection or a UDP session.
+func (h *Handler) logTheConnection(logDataTable *LogData) {
+	core := logDataTable.Core
+
+	core[RequestContentSize] = logDataTable.Request.size
+	core[DownstreamContentSize] = logDataTable.DownstreamResponse.size
+
+	// n.b. take care to perform time arithmetic using UTC to avoid errors at DST boundaries.
+	totalDuration := time.Now().UTC().Sub(core[StartUTC].(time.Time))
+	core[Duration] = totalDuration
+
+	if h.keepConnectionLog(totalDuration) {
+		h.writeLogData(logDataTable)
+	}
+}
+
+// keepConnectionLog returns whether a TCP connection or a UDP session is kept by the filters.
+// The status codes filter does not apply to connections, which are never retried either.
+func (h *Handler) keepConnectionLog(duration time.Duration) bool {
+	if h.config.Filters == nil {
+		return true
+	}
+
+	if !h.config.Filters.RetryAttempts && h.config.Filters.MinDuration == 0 {
+		// Only the status codes filter, if any, was specified.
+		return true
+	}
+
+	return h.config.Filters.MinDuration > 0 && ptypes.Duration(duration) > h.config.Filters.MinDuration
+}
+
+// captureConn is a TCP connection which keeps track of the bytes exchanged with the client,
+// and of the first event which led to its termination.
+type captureConn struct {
+	tcp.WriteCloser
+
+	logData *LogData
+
+	mu      sync.Mutex
+	read    int64
+	written int64
+	reason  string
+}
+
+// Unwrap returns the connection wrapped by the captureConn.
+func (c *captureConn) Unwrap() tcp.WriteCloser {
+	return c.WriteCloser
+}
+
+func (c *captureConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.read += int64(n)
+
+	if err != nil && c.reason == "" {
+		var netErr net.Error
+		switch {
+		case errors.Is(err, io.EOF):
+			c.reason = closeReasonClientClosed
+		case errors.As(err, &netErr) && netErr.Timeout():
+			c.reason = closeReasonTimeout
+		default:
+			c.reason = closeReasonClientError
+		}
+	}
+
+	return n, err
+}
+
+func (c *captureConn) Write(p []byte) (int, error) {
+	n, err := c.WriteCloser.Write(p)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.written += int64(n)
+
+	if err != nil && c.reason == "" {
+		c.reason = closeReasonClientError
+	}
+
+	return n, err
+}
+
+// CloseWrite is called once the backend has nothing more to send.
+func (c *captureConn) CloseWrite() error {
+	c.mu.Lock()
+	if c.reason == "" {
+		c.reason = closeReasonBackendClosed
+	}
+	c.mu.Unlock()
+
+	return c.WriteCloser.CloseWrite()
+}
+
+// SetCloseReason is called when the proxy closes the connection itself, e.g. once it has been idle for too long.
+func (c *captureConn) SetCloseReason(reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.reason == "" {
+		c.reason = reason
+	}
+}
+
+func (c *captureConn) bytesRead() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.read
+}
+
+func (c *captureConn) bytesWritten() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.written
+}
+
+func (c *captureConn) closeReason() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.reason != "" {
+		return c.reason
+	}
+
+	// Nothing happened on the connection: either it never reached a backend,
+	// or the backend could not be reached.
+	if _, ok := c.logData.Core[ServiceAddr]; ok {
+		return closeReasonBackendUnreachable
+	}
+	return closeReasonRejected
+}
@@ -0,0 +1,253 @@
+package accesslog
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestLoggerTCP(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		backend        func(conn net.Conn)
+		unreachable    bool
+		expectedIn     float64
+		expectedOut    float64
+		expectedReason string
+	}{
+		{
+			desc: "client closes first",
+			backend: func(conn net.Conn) {
+				_, _ = io.ReadAll(conn)
+				_, _ = conn.Write([]byte("pong"))
+				_ = conn.Close()
+			},
+			expectedIn:     4,
+			expectedOut:    4,
+			expectedReason: closeReasonClientClosed,
+		},
+		{
+			desc: "backend closes first",
+			backend: func(conn net.Conn) {
+				_, _ = conn.Write([]byte("pong!"))
+				_ = conn.Close()
+			},
+			expectedIn:     0,
+			expectedOut:    5,
+			expectedReason: closeReasonBackendClosed,
+		},
+		{
+			desc:           "unreachable backend",
+			unreachable:    true,
+			expectedReason: closeReasonBackendUnreachable,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			logFilePath := filepath.Join(t.TempDir(), "access.log")
+
+			logHandler, err := NewHandler(&types.AccessLog{FilePath: logFilePath, Format: JSONFormat})
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = logHandler.Close() })
+
+			backendAddr := startTCPBackend(t, test.backend, test.unreachable)
+
+			proxy, err := tcp.NewProxy(backendAddr, 100*time.Millisecond, nil, tcp.DialConfig{}, tcp.ConnTimeouts{}, nil)
+			require.NoError(t, err)
+
+			handler, err := tcp.NewChain(WrapTCPHandler(logHandler), func(next tcp.Handler) (tcp.Handler, error) {
+				return NewTCPFieldHandler(next, RouterName, testRouterName, nil), nil
+			}).Then(NewTCPFieldHandler(proxy, ServiceAddr, backendAddr, AddTCPServiceFields))
+			require.NoError(t, err)
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = listener.Close() })
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				handler.ServeTCP(conn.(*net.TCPConn))
+			}()
+
+			conn, err := net.Dial("tcp", listener.Addr().String())
+			require.NoError(t, err)
+
+			if !test.unreachable && test.expectedIn > 0 {
+				_, err = conn.Write([]byte("ping"))
+				require.NoError(t, err)
+				require.NoError(t, conn.(*net.TCPConn).CloseWrite())
+			}
+
+			_, _ = io.ReadAll(conn)
+			require.NoError(t, conn.Close())
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("connection was not handled")
+			}
+
+			logData, err := os.ReadFile(logFilePath)
+			require.NoError(t, err)
+
+			jsonData := make(map[string]interface{})
+			require.NoError(t, json.Unmarshal(logData, &jsonData))
+
+			assert.Equal(t, "TCP", jsonData[RequestProtocol])
+			assert.Equal(t, "tcp", jsonData[RequestScheme])
+			assert.Equal(t, "127.0.0.1", jsonData[ClientHost])
+			assert.Equal(t, testRouterName, jsonData[RouterName])
+			assert.Equal(t, backendAddr, jsonData[ServiceAddr])
+			assert.Equal(t, "tcp://"+backendAddr, jsonData[ServiceURL])
+			assert.Equal(t, test.expectedIn, jsonData[RequestContentSize])
+			assert.Equal(t, test.expectedOut, jsonData[DownstreamContentSize])
+			assert.Equal(t, test.expectedReason, jsonData[CloseReason])
+			assert.NotZero(t, jsonData[Duration])
+		})
+	}
+}
+
+func TestLoggerTCP_filters(t *testing.T) {
+	logFilePath := filepath.Join(t.TempDir(), "access.log")
+
+	config := &types.AccessLog{
+		FilePath: logFilePath,
+		Format:   CommonFormat,
+		Filters:  &types.AccessLogFilters{MinDuration: ptypes.Duration(time.Hour)},
+	}
+	logHandler, err := NewHandler(config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = logHandler.Close() })
+
+	handler, err := tcp.NewChain(WrapTCPHandler(logHandler)).Then(tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		_ = conn.Close()
+	}))
+	require.NoError(t, err)
+
+	client, server := net.Pipe()
+	t.Cleanup(func() { _ = client.Close() })
+
+	handler.ServeTCP(&pipeConn{Conn: server})
+
+	logData, err := os.ReadFile(logFilePath)
+	require.NoError(t, err)
+	assert.Empty(t, logData)
+
+	config.Filters.MinDuration = 0
+	handler.ServeTCP(&pipeConn{Conn: server})
+
+	logData, err = os.ReadFile(logFilePath)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(logData), `"- - TCP"`), string(logData))
+}
+
+func TestLoggerTCP_statusCodesFilter(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		filters  *types.AccessLogFilters
+		expected bool
+	}{
+		{
+			desc:     "status codes filter only",
+			filters:  &types.AccessLogFilters{StatusCodes: []string{"200", "500-599"}},
+			expected: true,
+		},
+		{
+			desc: "status codes and minimal duration filters",
+			filters: &types.AccessLogFilters{
+				StatusCodes: []string{"200"},
+				MinDuration: ptypes.Duration(time.Hour),
+			},
+		},
+		{
+			desc: "status codes and retry attempts filters",
+			filters: &types.AccessLogFilters{
+				StatusCodes:   []string{"200"},
+				RetryAttempts: true,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			logFilePath := filepath.Join(t.TempDir(), "access.log")
+
+			logHandler, err := NewHandler(&types.AccessLog{
+				FilePath: logFilePath,
+				Format:   CommonFormat,
+				Filters:  test.filters,
+			})
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = logHandler.Close() })
+
+			handler, err := tcp.NewChain(WrapTCPHandler(logHandler)).Then(tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				_ = conn.Close()
+			}))
+			require.NoError(t, err)
+
+			client, server := net.Pipe()
+			t.Cleanup(func() { _ = client.Close() })
+
+			handler.ServeTCP(&pipeConn{Conn: server})
+
+			logData, err := os.ReadFile(logFilePath)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, strings.Contains(string(logData), `"- - TCP"`), string(logData))
+		})
+	}
+}
+
+type pipeConn struct {
+	net.Conn
+}
+
+func (p *pipeConn) CloseWrite() error {
+	return nil
+}
+
+func startTCPBackend(t *testing.T, serve func(conn net.Conn), unreachable bool) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	if unreachable {
+		addr := listener.Addr().String()
+		require.NoError(t, listener.Close())
+		return addr
+	}
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		serve(conn)
+	}()
+
+	return listener.Addr().String()
+}
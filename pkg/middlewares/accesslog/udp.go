@@ -0,0 +1,114 @@
+package accesslog
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/udp"
+)
+
+// UDPFieldApply function hook to add data in accesslog for UDP sessions.
+type UDPFieldApply func(conn *udp.Conn, next udp.Handler, data *LogData)
+
+// UDPFieldHandler sends a new field to the logger for UDP sessions.
+type UDPFieldHandler struct {
+	next    udp.Handler
+	name    string
+	value   string
+	applyFn UDPFieldApply
+}
+
+// NewUDPFieldHandler creates a Field handler for UDP sessions.
+func NewUDPFieldHandler(next udp.Handler, name, value string, applyFn UDPFieldApply) udp.Handler {
+	return &UDPFieldHandler{next: next, name: name, value: value, applyFn: applyFn}
+}
+
+// ServeUDP implements the udp.Handler interface.
+func (f *UDPFieldHandler) ServeUDP(conn *udp.Conn) {
+	table := GetUDPLogData(conn)
+	if table == nil {
+		f.next.ServeUDP(conn)
+		return
+	}
+
+	table.Core[f.name] = f.value
+
+	if f.applyFn != nil {
+		f.applyFn(conn, f.next, table)
+	} else {
+		f.next.ServeUDP(conn)
+	}
+}
+
+// AddUDPServiceFields add service fields for UDP sessions.
+func AddUDPServiceFields(conn *udp.Conn, next udp.Handler, data *LogData) {
+	if addr, ok := data.Core[ServiceAddr].(string); ok {
+		data.Core[ServiceURL] = (&url.URL{Scheme: "udp", Host: addr}).String()
+	}
+
+	next.ServeUDP(conn)
+}
+
+// WrapUDPHandler Wraps access log handler around a UDP handler.
+func WrapUDPHandler(handler *Handler, next udp.Handler) udp.Handler {
+	return udp.HandlerFunc(func(conn *udp.Conn) {
+		handler.ServeUDP(conn, next)
+	})
+}
+
+// GetUDPLogData gets the object that contains logging data for a UDP session.
+// This creates data as the session passes through the handler chain.
+func GetUDPLogData(conn *udp.Conn) *LogData {
+	if ld, ok := conn.Context().Value(DataTableKey).(*LogData); ok {
+		return ld
+	}
+	return nil
+}
+
+// ServeUDP logs the given UDP session once it has been handled by next.
+func (h *Handler) ServeUDP(conn *udp.Conn, next udp.Handler) {
+	now := time.Now().UTC()
+
+	core := CoreLogData{
+		StartUTC:   now,
+		StartLocal: now.Local(),
+	}
+
+	logDataTable := &LogData{Core: core}
+
+	core[RequestCount] = nextRequestCount()
+	core[RequestProtocol] = "UDP"
+	core[RequestScheme] = "udp"
+
+	remoteAddr := conn.RemoteAddr().String()
+	core[ClientAddr] = remoteAddr
+	core[ClientHost], core[ClientPort] = silentSplitHostPort(remoteAddr)
+
+	conn.SetContext(context.WithValue(conn.Context(), DataTableKey, logDataTable))
+	next.ServeUDP(conn)
+
+	logDataTable.Request.size = conn.BytesRead()
+	logDataTable.DownstreamResponse.size = conn.BytesWritten()
+
+	switch _, ok := core[ServiceAddr]; {
+	case conn.Expired():
+		core[CloseReason] = closeReasonIdleTimeout
+	case !ok:
+		core[CloseReason] = closeReasonRejected
+	case conn.BytesRead() == 0:
+		// The first datagram of the session is always read, unless the backend could not be reached.
+		core[CloseReason] = closeReasonBackendUnreachable
+	default:
+		core[CloseReason] = closeReasonClosed
+	}
+
+	if h.config.BufferingSize > 0 {
+		h.logHandlerChan <- handlerParams{
+			logDataTable: logDataTable,
+			connection:   true,
+		}
+	} else {
+		h.logTheConnection(logDataTable)
+	}
+}
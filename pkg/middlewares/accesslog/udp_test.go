@@ -0,0 +1,98 @@
+package accesslog
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/types"
+	"github.com/traefik/traefik/v2/pkg/udp"
+)
+
+func TestLoggerUDP(t *testing.T) {
+	logFilePath := filepath.Join(t.TempDir(), "access.log")
+
+	logHandler, err := NewHandler(&types.AccessLog{FilePath: logFilePath, Format: JSONFormat})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = logHandler.Close() })
+
+	backend, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backend.Close() })
+
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := backend.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			_, _ = backend.WriteTo(buf[:n], addr)
+		}
+	}()
+
+	backendAddr := backend.LocalAddr().String()
+
+	proxy, err := udp.NewProxy(backendAddr, nil)
+	require.NoError(t, err)
+
+	handler := WrapUDPHandler(logHandler, NewUDPFieldHandler(
+		NewUDPFieldHandler(proxy, ServiceAddr, backendAddr, AddUDPServiceFields),
+		RouterName, testRouterName, nil))
+
+	listener, err := udp.Listen("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")}, 100*time.Millisecond)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		handler.ServeUDP(conn)
+	}()
+
+	conn, err := net.Dial("udp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf[:n]))
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("session was not handled")
+	}
+
+	logData, err := os.ReadFile(logFilePath)
+	require.NoError(t, err)
+
+	jsonData := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(logData, &jsonData))
+
+	assert.Equal(t, "UDP", jsonData[RequestProtocol])
+	assert.Equal(t, "udp", jsonData[RequestScheme])
+	assert.Equal(t, "127.0.0.1", jsonData[ClientHost])
+	assert.Equal(t, testRouterName, jsonData[RouterName])
+	assert.Equal(t, backendAddr, jsonData[ServiceAddr])
+	assert.Equal(t, "udp://"+backendAddr, jsonData[ServiceURL])
+	assert.Equal(t, float64(4), jsonData[RequestContentSize])
+	assert.Equal(t, float64(4), jsonData[DownstreamContentSize])
+	assert.Equal(t, closeReasonIdleTimeout, jsonData[CloseReason])
+	assert.NotZero(t, jsonData[Duration])
+}
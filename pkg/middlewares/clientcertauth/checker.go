@@ -0,0 +1,217 @@
+package clientcertauth
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// Checker checks the TLS client certificates against the authorization criteria.
+type Checker struct {
+	subjects     []string
+	issuers      []string
+	dnsNames     []string
+	uris         []string
+	fingerprints map[string]struct{}
+}
+
+// NewChecker builds a new Checker given the authorization criteria.
+func NewChecker(config dynamic.ClientCertAuth) (*Checker, error) {
+	if len(config.Subjects) == 0 && len(config.Issuers) == 0 && len(config.DNSNames) == 0 &&
+		len(config.URIs) == 0 && len(config.Fingerprints) == 0 {
+		return nil, errors.New("no authorization criterion defined")
+	}
+
+	checker := &Checker{
+		fingerprints: make(map[string]struct{}),
+	}
+
+	for _, subject := range config.Subjects {
+		checker.subjects = append(checker.subjects, normalizeDN(subject))
+	}
+
+	for _, issuer := range config.Issuers {
+		checker.issuers = append(checker.issuers, normalizeDN(issuer))
+	}
+
+	for _, pattern := range config.DNSNames {
+		pattern = strings.ToLower(pattern)
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid DNS name pattern %q: %w", pattern, err)
+		}
+		checker.dnsNames = append(checker.dnsNames, pattern)
+	}
+
+	for _, pattern := range config.URIs {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid URI pattern %q: %w", pattern, err)
+		}
+		checker.uris = append(checker.uris, pattern)
+	}
+
+	for _, fingerprint := range config.Fingerprints {
+		fingerprint = strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+		if decoded, err := hex.DecodeString(fingerprint); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("invalid SHA-256 fingerprint %q", fingerprint)
+		}
+		checker.fingerprints[fingerprint] = struct{}{}
+	}
+
+	return checker, nil
+}
+
+// CheckConnectionState checks the client certificate of a TLS connection.
+// The fields of a certificate which has not been verified against the client CAs cannot be trusted,
+// so such a certificate can only be authorized by its fingerprint,
+// as the handshake proves that the client holds its private key.
+func (c *Checker) CheckConnectionState(state *tls.ConnectionState) error {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return errors.New("no client certificate")
+	}
+
+	if len(state.VerifiedChains) == 0 && !c.checksFingerprintsOnly() {
+		return errors.New("client certificate not verified")
+	}
+
+	return c.Check(state.PeerCertificates[0])
+}
+
+// checksFingerprintsOnly reports whether the fingerprints are the only criteria of the checker.
+func (c *Checker) checksFingerprintsOnly() bool {
+	return len(c.fingerprints) > 0 && len(c.subjects) == 0 && len(c.issuers) == 0 &&
+		len(c.dnsNames) == 0 && len(c.uris) == 0
+}
+
+// Check checks that the certificate matches all the criteria of the checker.
+func (c *Checker) Check(cert *x509.Certificate) error {
+	if len(c.subjects) > 0 && !containsDN(c.subjects, cert.Subject.String()) {
+		return fmt.Errorf("subject %q not allowed", cert.Subject)
+	}
+
+	if len(c.issuers) > 0 && !containsDN(c.issuers, cert.Issuer.String()) {
+		return fmt.Errorf("issuer %q not allowed", cert.Issuer)
+	}
+
+	if len(c.dnsNames) > 0 && !matchAnyDNSName(c.dnsNames, cert.DNSNames) {
+		return fmt.Errorf("DNS names %q not allowed", cert.DNSNames)
+	}
+
+	if len(c.uris) > 0 {
+		uris := make([]string, 0, len(cert.URIs))
+		for _, uri := range cert.URIs {
+			uris = append(uris, uri.String())
+		}
+
+		if !matchAny(c.uris, uris) {
+			return fmt.Errorf("URIs %q not allowed", uris)
+		}
+	}
+
+	if len(c.fingerprints) > 0 {
+		sum := sha256.Sum256(cert.Raw)
+		fingerprint := hex.EncodeToString(sum[:])
+
+		if _, ok := c.fingerprints[fingerprint]; !ok {
+			return fmt.Errorf("fingerprint %s not allowed", fingerprint)
+		}
+	}
+
+	return nil
+}
+
+// matchAny checks whether one of the values matches one of the patterns.
+func matchAny(patterns, values []string) bool {
+	for _, value := range values {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, value); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchAnyDNSName checks whether one of the DNS names matches one of the patterns.
+func matchAnyDNSName(patterns, dnsNames []string) bool {
+	for _, dnsName := range dnsNames {
+		for _, pattern := range patterns {
+			if matchDNSName(pattern, strings.ToLower(dnsName)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchDNSName matches a DNS name against a pattern label by label,
+// so that the wildcards of a label cannot match across the dots,
+// e.g. *.example.com matches foo.example.com, but not foo.bar.example.com.
+func matchDNSName(pattern, dnsName string) bool {
+	patternLabels := strings.Split(pattern, ".")
+	labels := strings.Split(dnsName, ".")
+
+	if len(patternLabels) != len(labels) {
+		return false
+	}
+
+	for i, patternLabel := range patternLabels {
+		if ok, _ := path.Match(patternLabel, labels[i]); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsDN(dns []string, dn string) bool {
+	dn = normalizeDN(dn)
+
+	for _, allowed := range dns {
+		if allowed == dn {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeDN normalizes a distinguished name in its RFC 2253 string form,
+// by removing the spaces around the attributes, and lower casing it.
+func normalizeDN(dn string) string {
+	var attributes []string
+
+	var attribute strings.Builder
+	escaped := false
+	for _, r := range dn {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			attributes = append(attributes, strings.TrimSpace(attribute.String()))
+			attribute.Reset()
+			continue
+		}
+
+		attribute.WriteRune(r)
+	}
+	attributes = append(attributes, strings.TrimSpace(attribute.String()))
+
+	for i, attr := range attributes {
+		if parts := strings.SplitN(attr, "=", 2); len(parts) == 2 {
+			attributes[i] = strings.TrimSpace(parts[0]) + "=" + strings.TrimSpace(parts[1])
+		}
+	}
+
+	return strings.ToLower(strings.Join(attributes, ","))
+}
@@ -0,0 +1,281 @@
+package clientcertauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// createCertificate creates a client certificate, signed by a CA named "Clients CA".
+func createCertificate(t *testing.T) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Clients CA", Organization: []string{"Example"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	spiffeID, err := url.Parse("spiffe://example.org/ns/prod/sa/client")
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "client", Organization: []string{"Example"}, Country: []string{"FR"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"client.prod.example.com"},
+		URIs:         []*url.URL{spiffeID},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, key.Public(), key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert
+}
+
+func fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestNewChecker(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.ClientCertAuth
+		expectedErr bool
+	}{
+		{
+			desc:        "no criterion",
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid DNS name pattern",
+			config:      dynamic.ClientCertAuth{DNSNames: []string{"[a-"}},
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid URI pattern",
+			config:      dynamic.ClientCertAuth{URIs: []string{"spiffe://example.org/[a-"}},
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid fingerprint",
+			config:      dynamic.ClientCertAuth{Fingerprints: []string{"ab:cd"}},
+			expectedErr: true,
+		},
+		{
+			desc: "valid criteria",
+			config: dynamic.ClientCertAuth{
+				Subjects:     []string{"CN=client"},
+				Fingerprints: []string{"AB:" + hex.EncodeToString(make([]byte, 31))},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewChecker(test.config)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestChecker_Check(t *testing.T) {
+	cert := createCertificate(t)
+
+	testCases := []struct {
+		desc        string
+		config      dynamic.ClientCertAuth
+		expectedErr bool
+	}{
+		{
+			desc:   "allowed subject",
+			config: dynamic.ClientCertAuth{Subjects: []string{"CN=other", "cn=client, o=Example, c=FR"}},
+		},
+		{
+			desc:        "subject not allowed",
+			config:      dynamic.ClientCertAuth{Subjects: []string{"CN=client,O=Example"}},
+			expectedErr: true,
+		},
+		{
+			desc:   "allowed issuer",
+			config: dynamic.ClientCertAuth{Issuers: []string{"CN=Clients CA,O=Example"}},
+		},
+		{
+			desc:        "issuer not allowed",
+			config:      dynamic.ClientCertAuth{Issuers: []string{"CN=Other CA,O=Example"}},
+			expectedErr: true,
+		},
+		{
+			desc:   "allowed DNS name",
+			config: dynamic.ClientCertAuth{DNSNames: []string{"*.PROD.example.com"}},
+		},
+		{
+			desc:        "DNS name not allowed",
+			config:      dynamic.ClientCertAuth{DNSNames: []string{"*.staging.example.com"}},
+			expectedErr: true,
+		},
+		{
+			desc:        "DNS name wildcard not matching across the dots",
+			config:      dynamic.ClientCertAuth{DNSNames: []string{"*.example.com"}},
+			expectedErr: true,
+		},
+		{
+			desc:   "allowed SPIFFE ID",
+			config: dynamic.ClientCertAuth{URIs: []string{"spiffe://example.org/ns/*/sa/client"}},
+		},
+		{
+			desc:        "SPIFFE ID not allowed",
+			config:      dynamic.ClientCertAuth{URIs: []string{"spiffe://example.org/ns/*/sa/admin"}},
+			expectedErr: true,
+		},
+		{
+			desc:   "pinned fingerprint",
+			config: dynamic.ClientCertAuth{Fingerprints: []string{fingerprint(cert)}},
+		},
+		{
+			desc:        "fingerprint not pinned",
+			config:      dynamic.ClientCertAuth{Fingerprints: []string{hex.EncodeToString(make([]byte, 32))}},
+			expectedErr: true,
+		},
+		{
+			desc: "all criteria matching",
+			config: dynamic.ClientCertAuth{
+				Subjects: []string{"CN=client,O=Example,C=FR"},
+				Issuers:  []string{"CN=Clients CA,O=Example"},
+				URIs:     []string{"spiffe://example.org/ns/prod/sa/client"},
+			},
+		},
+		{
+			desc: "one criterion not matching",
+			config: dynamic.ClientCertAuth{
+				Subjects: []string{"CN=client,O=Example,C=FR"},
+				Issuers:  []string{"CN=Other CA"},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			checker, err := NewChecker(test.config)
+			require.NoError(t, err)
+
+			err = checker.Check(cert)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestChecker_CheckConnectionState(t *testing.T) {
+	cert := createCertificate(t)
+
+	testCases := []struct {
+		desc        string
+		config      dynamic.ClientCertAuth
+		state       *tls.ConnectionState
+		expectedErr bool
+	}{
+		{
+			desc:        "no TLS",
+			config:      dynamic.ClientCertAuth{Fingerprints: []string{fingerprint(cert)}},
+			expectedErr: true,
+		},
+		{
+			desc:        "no client certificate",
+			config:      dynamic.ClientCertAuth{Fingerprints: []string{fingerprint(cert)}},
+			state:       &tls.ConnectionState{},
+			expectedErr: true,
+		},
+		{
+			desc:   "verified certificate",
+			config: dynamic.ClientCertAuth{URIs: []string{"spiffe://example.org/ns/prod/sa/client"}},
+			state: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{cert},
+				VerifiedChains:   [][]*x509.Certificate{{cert}},
+			},
+		},
+		{
+			desc:        "unverified certificate",
+			config:      dynamic.ClientCertAuth{URIs: []string{"spiffe://example.org/ns/prod/sa/client"}},
+			state:       &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+			expectedErr: true,
+		},
+		{
+			desc:   "unverified certificate with a pinned fingerprint",
+			config: dynamic.ClientCertAuth{Fingerprints: []string{fingerprint(cert)}},
+			state:  &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+		},
+		{
+			desc:        "unverified certificate with a fingerprint not pinned",
+			config:      dynamic.ClientCertAuth{Fingerprints: []string{hex.EncodeToString(make([]byte, 32))}},
+			state:       &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+			expectedErr: true,
+		},
+		{
+			desc: "unverified certificate with a pinned fingerprint and other criteria",
+			config: dynamic.ClientCertAuth{
+				Fingerprints: []string{fingerprint(cert)},
+				URIs:         []string{"spiffe://example.org/ns/prod/sa/client"},
+			},
+			state:       &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			checker, err := NewChecker(test.config)
+			require.NoError(t, err)
+
+			err = checker.CheckConnectionState(test.state)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package clientcertauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const typeName = "ClientCertAuth"
+
+// clientCertAuth is a middleware authorizing the requests based on the fields of their verified TLS client certificate.
+type clientCertAuth struct {
+	next    http.Handler
+	checker *Checker
+	name    string
+}
+
+// New builds a new ClientCertAuth middleware given the authorization criteria.
+func New(ctx context.Context, next http.Handler, config dynamic.ClientCertAuth, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	checker, err := NewChecker(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientCertAuth{
+		next:    next,
+		checker: checker,
+		name:    name,
+	}, nil
+}
+
+func (c *clientCertAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return c.name, tracing.SpanKindNoneEnum
+}
+
+func (c *clientCertAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	ctx := middlewares.GetLoggerCtx(req.Context(), c.name, typeName)
+	logger := log.FromContext(ctx)
+
+	err := c.authorize(req)
+	if err != nil {
+		logMessage := fmt.Sprintf("rejecting request from %s: %v", req.RemoteAddr, err)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+		reject(ctx, rw)
+		return
+	}
+
+	c.next.ServeHTTP(rw, req)
+}
+
+func (c *clientCertAuth) authorize(req *http.Request) error {
+	return c.checker.CheckConnectionState(req.TLS)
+}
+
+func reject(ctx context.Context, rw http.ResponseWriter) {
+	statusCode := http.StatusForbidden
+
+	rw.WriteHeader(statusCode)
+	_, err := rw.Write([]byte(http.StatusText(statusCode)))
+	if err != nil {
+		log.FromContext(ctx).Error(err)
+	}
+}
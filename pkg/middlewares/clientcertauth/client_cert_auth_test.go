@@ -0,0 +1,69 @@
+package clientcertauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestClientCertAuth_ServeHTTP(t *testing.T) {
+	cert := createCertificate(t)
+
+	testCases := []struct {
+		desc               string
+		tlsState           *tls.ConnectionState
+		expectedStatusCode int
+	}{
+		{
+			desc:               "no TLS",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			desc:               "no client certificate",
+			tlsState:           &tls.ConnectionState{},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			desc: "client certificate not verified",
+			tlsState: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{cert},
+			},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			desc: "authorized client certificate",
+			tlsState: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{cert},
+				VerifiedChains:   [][]*x509.Certificate{{cert}},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	handler, err := New(context.Background(), next, dynamic.ClientCertAuth{Subjects: []string{"CN=client,O=Example,C=FR"}}, "clientCertAuth")
+	require.NoError(t, err)
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "https://localhost", nil)
+			req.TLS = test.tlsState
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package tcpclientcertauth
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/clientcertauth"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+const typeName = "ClientCertAuthTCP"
+
+// handshakeTimeout bounds the TLS handshake done to get the client certificate,
+// as the deadlines of the entry point are removed once the connection is routed.
+const handshakeTimeout = 10 * time.Second
+
+// clientCertAuth is a middleware authorizing the connections based on the fields of their verified TLS client certificate.
+type clientCertAuth struct {
+	next    tcp.Handler
+	checker *clientcertauth.Checker
+	name    string
+}
+
+// New builds a new TCP ClientCertAuth middleware given the authorization criteria.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPClientCertAuth, name string) (tcp.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	checker, err := clientcertauth.NewChecker(dynamic.ClientCertAuth(config))
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientCertAuth{
+		next:    next,
+		checker: checker,
+		name:    name,
+	}, nil
+}
+
+func (c *clientCertAuth) ServeTCP(conn tcp.WriteCloser) {
+	ctx := middlewares.GetLoggerCtx(context.Background(), c.name, typeName)
+	logger := log.FromContext(ctx)
+
+	err := c.authorize(conn)
+	if err != nil {
+		logger.Debugf("Connection from %s rejected: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	c.next.ServeTCP(conn)
+}
+
+func (c *clientCertAuth) authorize(conn tcp.WriteCloser) error {
+	tlsConn, ok := tcp.GetTLSConn(conn)
+	if !ok {
+		return errors.New("the TLS connection is not terminated by the router")
+	}
+
+	// The client certificate is only available once the handshake is done.
+	if err := handshake(tlsConn); err != nil {
+		return err
+	}
+
+	state := tlsConn.ConnectionState()
+
+	return c.checker.CheckConnectionState(&state)
+}
+
+func handshake(conn *tls.Conn) error {
+	if err := conn.SetDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		return fmt.Errorf("unable to set the handshake deadline: %w", err)
+	}
+
+	if err := conn.Handshake(); err != nil {
+		return err
+	}
+
+	return conn.SetDeadline(time.Time{})
+}
@@ -0,0 +1,124 @@
+package tcpclientcertauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestClientCertAuth_ServeTCP(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Clients CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, caKey.Public(), caKey)
+	require.NoError(t, err)
+	ca, err = x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	createCert := func(commonName string, extKeyUsage x509.ExtKeyUsage) tls.Certificate {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(time.Now().UnixNano()),
+			Subject:      pkix.Name{CommonName: commonName},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			ExtKeyUsage:  []x509.ExtKeyUsage{extKeyUsage},
+			DNSNames:     []string{commonName},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, key.Public(), caKey)
+		require.NoError(t, err)
+
+		return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	}
+
+	serverCert := createCert("server.example.com", x509.ExtKeyUsageServerAuth)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ca)
+
+	testCases := []struct {
+		desc         string
+		clientCerts  []tls.Certificate
+		expectedData string
+	}{
+		{
+			desc:         "authorized client certificate",
+			clientCerts:  []tls.Certificate{createCert("client.example.com", x509.ExtKeyUsageClientAuth)},
+			expectedData: "OK",
+		},
+		{
+			desc:        "non authorized client certificate",
+			clientCerts: []tls.Certificate{createCert("other.example.com", x509.ExtKeyUsageClientAuth)},
+		},
+		{
+			desc: "no client certificate",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				_, err := conn.Write([]byte("OK"))
+				require.NoError(t, err)
+
+				err = conn.Close()
+				require.NoError(t, err)
+			})
+
+			handler, err := New(context.Background(), next, dynamic.TCPClientCertAuth{DNSNames: []string{"client.example.com"}}, "clientCertAuth")
+			require.NoError(t, err)
+
+			server, client := net.Pipe()
+
+			go func() {
+				handler.ServeTCP(tls.Server(server, &tls.Config{
+					Certificates: []tls.Certificate{serverCert},
+					ClientCAs:    clientCAs,
+					ClientAuth:   tls.VerifyClientCertIfGiven,
+				}))
+			}()
+
+			tlsClient := tls.Client(client, &tls.Config{
+				ServerName:   "server.example.com",
+				RootCAs:      rootCAs,
+				Certificates: test.clientCerts,
+			})
+
+			// The handshake is done before the middleware checks the certificate,
+			// so the rejection shows as a closed connection.
+			read, _ := io.ReadAll(tlsClient)
+			assert.Equal(t, test.expectedData, string(read))
+		})
+	}
+}
@@ -8,10 +8,10 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/http01"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/safe"
@@ -19,49 +19,35 @@ import (
 
 // ChallengeHTTP HTTP challenge provider implements challenge.Provider.
 type ChallengeHTTP struct {
-	httpChallenges map[string]map[string][]byte
-	lock           sync.RWMutex
-}
+	store ChallengeStore
 
-// NewChallengeHTTP creates a new ChallengeHTTP.
-func NewChallengeHTTP() *ChallengeHTTP {
-	return &ChallengeHTTP{
-		httpChallenges: make(map[string]map[string][]byte),
-	}
+	// stores are the stores in which the challenges are looked up,
+	// as the resolver which presented a challenge is unknown when the CA requests it.
+	stores []ChallengeStore
 }
 
-// Present presents a challenge to obtain new ACME certificate.
-func (c *ChallengeHTTP) Present(domain, token, keyAuth string) error {
-	c.lock.Lock()
-	defer c.lock.Unlock()
+// NewChallengeHTTP creates a new ChallengeHTTP, storing the challenges in the given store.
+func NewChallengeHTTP(store ChallengeStore) *ChallengeHTTP {
+	return &ChallengeHTTP{store: store, stores: []ChallengeStore{store}}
+}
 
-	if _, ok := c.httpChallenges[token]; !ok {
-		c.httpChallenges[token] = map[string][]byte{}
-	}
+// WithStore returns a challenge provider storing the challenges in the given store (e.g. the one of a resolver),
+// and adds this store to the ones in which c looks up the challenges.
+// It must be called before c serves the challenges.
+func (c *ChallengeHTTP) WithStore(store ChallengeStore) challenge.Provider {
+	c.stores = append(c.stores, store)
 
-	c.httpChallenges[token][domain] = []byte(keyAuth)
+	return NewChallengeHTTP(store)
+}
 
-	return nil
+// Present presents a challenge to obtain new ACME certificate.
+func (c *ChallengeHTTP) Present(domain, token, keyAuth string) error {
+	return c.store.SetHTTPChallengeToken(token, domain, []byte(keyAuth))
 }
 
 // CleanUp cleans the challenges when certificate is obtained.
 func (c *ChallengeHTTP) CleanUp(domain, token, _ string) error {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	if c.httpChallenges == nil && len(c.httpChallenges) == 0 {
-		return nil
-	}
-
-	if _, ok := c.httpChallenges[token]; ok {
-		delete(c.httpChallenges[token], domain)
-
-		if len(c.httpChallenges[token]) == 0 {
-			delete(c.httpChallenges, token)
-		}
-	}
-
-	return nil
+	return c.store.RemoveHTTPChallengeToken(token, domain)
 }
 
 // Timeout calculates the maximum of time allowed to resolved an ACME challenge.
@@ -108,20 +94,25 @@ func (c *ChallengeHTTP) getTokenValue(ctx context.Context, token, domain string)
 	var result []byte
 
 	operation := func() error {
-		c.lock.RLock()
-		defer c.lock.RUnlock()
+		var errs []error
+		for _, store := range c.stores {
+			var err error
+			result, err = store.GetHTTPChallengeToken(token, domain)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
 
-		if _, ok := c.httpChallenges[token]; !ok {
-			return fmt.Errorf("cannot find challenge for token %s", token)
+			if len(result) > 0 {
+				return nil
+			}
 		}
 
-		var ok bool
-		result, ok = c.httpChallenges[token][domain]
-		if !ok {
-			return fmt.Errorf("cannot find challenge for domain %s", domain)
+		if len(errs) > 0 {
+			return fmt.Errorf("cannot get challenge for token %s: %v", token, errs)
 		}
 
-		return nil
+		return fmt.Errorf("cannot find challenge for token %s and domain %s", token, domain)
 	}
 
 	notify := func(err error, time time.Duration) {
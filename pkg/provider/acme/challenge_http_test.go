@@ -0,0 +1,81 @@
+package acme
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/http01"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChallengeHTTP_ServeHTTP(t *testing.T) {
+	localStore := NewLocalChallengeStore()
+	client := newKVClientMock()
+
+	testCases := []struct {
+		desc           string
+		presenterStore ChallengeStore
+		serverStore    ChallengeStore
+	}{
+		{
+			desc:           "local store",
+			presenterStore: localStore,
+			serverStore:    localStore,
+		},
+		{
+			desc:           "KV store shared with another instance",
+			presenterStore: newKVStore(client, "traefik", time.Second),
+			serverStore:    newKVStore(client, "traefik", time.Second),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			presenter := NewChallengeHTTP(test.presenterStore)
+
+			err := presenter.Present("foo.com", "token", "keyAuth")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.com"+http01.ChallengePath("token"), nil)
+			rw := httptest.NewRecorder()
+
+			NewChallengeHTTP(test.serverStore).ServeHTTP(rw, req)
+
+			assert.Equal(t, http.StatusOK, rw.Code)
+			assert.Equal(t, "keyAuth", rw.Body.String())
+
+			err = presenter.CleanUp("foo.com", "token", "keyAuth")
+			require.NoError(t, err)
+
+			keyAuth, err := test.serverStore.GetHTTPChallengeToken("token", "foo.com")
+			require.NoError(t, err)
+			assert.Empty(t, keyAuth)
+		})
+	}
+}
+
+func TestChallengeHTTP_WithStore(t *testing.T) {
+	client := newKVClientMock()
+
+	handler := NewChallengeHTTP(NewLocalChallengeStore())
+	handler.WithStore(newKVStore(newKVClientMock(), "traefik", time.Second))
+	handler.WithStore(newKVStore(client, "traefik", time.Second))
+
+	// Presented by the resolver of another instance, sharing the KV store of the second resolver.
+	presenter := NewChallengeHTTP(NewLocalChallengeStore()).WithStore(newKVStore(client, "traefik", time.Second))
+
+	err := presenter.Present("foo.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://foo.com"+http01.ChallengePath("token"), nil)
+	rw := httptest.NewRecorder()
+
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "keyAuth", rw.Body.String())
+}
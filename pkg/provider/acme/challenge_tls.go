@@ -1,12 +1,17 @@
 package acme
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/job"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
@@ -22,23 +27,39 @@ type ChallengeTLSALPN struct {
 	chans   map[string]chan struct{}
 	muChans sync.Mutex
 
-	certs   map[string]*Certificate
+	store ChallengeStore
+	// stores are the stores from which the challenges are provided.
+	stores  []ChallengeStore
 	muCerts sync.Mutex
 
 	configurationChan chan<- dynamic.Message
 }
 
-// NewChallengeTLSALPN creates a new ChallengeTLSALPN.
-func NewChallengeTLSALPN(timeout time.Duration) *ChallengeTLSALPN {
+// NewChallengeTLSALPN creates a new ChallengeTLSALPN, storing the challenges in the given store.
+func NewChallengeTLSALPN(timeout time.Duration, store ChallengeStore) *ChallengeTLSALPN {
 	return &ChallengeTLSALPN{
 		Timeout: timeout,
 		chans:   make(map[string]chan struct{}),
-		certs:   make(map[string]*Certificate),
+		store:   store,
+		stores:  []ChallengeStore{store},
 	}
 }
 
+// WithStore returns a challenge provider storing the challenges in the given store (e.g. the one of a resolver),
+// and adds this store to the ones from which c provides the challenges.
+// It must be called before c is provided.
+func (c *ChallengeTLSALPN) WithStore(store ChallengeStore) challenge.Provider {
+	c.stores = append(c.stores, store)
+
+	return &tlsALPNPresenter{challenge: c, store: store}
+}
+
 // Present presents a challenge to obtain new ACME certificate.
 func (c *ChallengeTLSALPN) Present(domain, _, keyAuth string) error {
+	return c.present(c.store, domain, keyAuth)
+}
+
+func (c *ChallengeTLSALPN) present(store ChallengeStore, domain, keyAuth string) error {
 	logger := log.WithoutContext().WithField(log.ProviderName, providerNameALPN)
 	logger.Debugf("TLS Challenge Present temp certificate for %s", domain)
 
@@ -54,10 +75,14 @@ func (c *ChallengeTLSALPN) Present(domain, _, keyAuth string) error {
 	c.chans[string(certPEMBlock)] = ch
 	c.muChans.Unlock()
 
-	c.muCerts.Lock()
-	c.certs[keyAuth] = cert
-	conf := createMessage(c.certs)
-	c.muCerts.Unlock()
+	conf, err := c.updateChallenges(func() error { return store.AddTLSChallenge(keyAuth, cert) })
+	if err != nil {
+		c.muChans.Lock()
+		c.cleanChan(string(certPEMBlock))
+		c.muChans.Unlock()
+
+		return err
+	}
 
 	c.configurationChan <- conf
 
@@ -71,7 +96,7 @@ func (c *ChallengeTLSALPN) Present(domain, _, keyAuth string) error {
 		c.cleanChan(string(certPEMBlock))
 		c.muChans.Unlock()
 
-		err = c.CleanUp(domain, "", keyAuth)
+		err = c.cleanUp(store, domain, keyAuth)
 		if err != nil {
 			logger.Errorf("Failed to clean up TLS challenge: %v", err)
 		}
@@ -85,31 +110,119 @@ func (c *ChallengeTLSALPN) Present(domain, _, keyAuth string) error {
 
 // CleanUp cleans the challenges when certificate is obtained.
 func (c *ChallengeTLSALPN) CleanUp(domain, _, keyAuth string) error {
+	return c.cleanUp(c.store, domain, keyAuth)
+}
+
+func (c *ChallengeTLSALPN) cleanUp(store ChallengeStore, domain, keyAuth string) error {
 	log.WithoutContext().WithField(log.ProviderName, providerNameALPN).
 		Debugf("TLS Challenge CleanUp temp certificate for %s", domain)
 
-	c.muCerts.Lock()
-	delete(c.certs, keyAuth)
-	conf := createMessage(c.certs)
-	c.muCerts.Unlock()
+	conf, err := c.updateChallenges(func() error { return store.RemoveTLSChallenge(keyAuth) })
+	if err != nil {
+		return err
+	}
 
 	c.configurationChan <- conf
 
 	return nil
 }
 
+// updateChallenges applies the update to a store, and returns the configuration of the challenges of all the stores.
+func (c *ChallengeTLSALPN) updateChallenges(update func() error) (dynamic.Message, error) {
+	c.muCerts.Lock()
+	defer c.muCerts.Unlock()
+
+	if err := update(); err != nil {
+		return dynamic.Message{}, err
+	}
+
+	return createMessage(c.getChallenges()), nil
+}
+
+// getChallenges returns the challenges of all the stores, skipping the stores which cannot be read.
+func (c *ChallengeTLSALPN) getChallenges() []*Certificate {
+	var certs []*Certificate
+	for _, store := range c.stores {
+		storeCerts, err := store.GetTLSChallenges()
+		if err != nil {
+			log.WithoutContext().WithField(log.ProviderName, providerNameALPN).
+				Errorf("Unable to get the TLS challenges: %v", err)
+			continue
+		}
+
+		certs = append(certs, storeCerts...)
+	}
+
+	return certs
+}
+
 // Init the provider.
 func (c *ChallengeTLSALPN) Init() error {
 	return nil
 }
 
 // Provide allows the provider to provide configurations to traefik using the given configuration channel.
-func (c *ChallengeTLSALPN) Provide(configurationChan chan<- dynamic.Message, _ *safe.Pool) error {
+func (c *ChallengeTLSALPN) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
 	c.configurationChan = configurationChan
 
+	for _, store := range c.stores {
+		watcher, ok := store.(ChallengeWatcher)
+		if !ok {
+			continue
+		}
+
+		pool.GoCtx(func(ctx context.Context) {
+			c.watchChallenges(ctx, watcher)
+		})
+	}
+
 	return nil
 }
 
+// watchChallenges provides the TLS challenges presented by the other Traefik instances sharing the store.
+func (c *ChallengeTLSALPN) watchChallenges(ctx context.Context, watcher ChallengeWatcher) {
+	logger := log.WithoutContext().WithField(log.ProviderName, providerNameALPN)
+
+	operation := func() error {
+		certsChan, err := watcher.WatchTLSChallenges(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to watch TLS challenges: %w", err)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case _, ok := <-certsChan:
+				if !ok {
+					return errors.New("the TLS challenges watch channel is closed")
+				}
+
+				// The challenges of the other stores are provided along with the ones of the watched store.
+				c.muCerts.Lock()
+				conf := createMessage(c.getChallenges())
+				c.muCerts.Unlock()
+
+				select {
+				case c.configurationChan <- conf:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}
+	}
+
+	notify := func(err error, time time.Duration) {
+		logger.Errorf("Error while watching TLS challenges: %v, retrying in %s", err, time)
+	}
+
+	err := backoff.RetryNotify(safe.OperationWithRecover(operation),
+		backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctx), notify)
+	if err != nil {
+		logger.Errorf("Cannot watch TLS challenges: %v", err)
+	}
+}
+
 // ListenConfiguration sets a new Configuration into the configurationChan.
 func (c *ChallengeTLSALPN) ListenConfiguration(conf dynamic.Configuration) {
 	c.muChans.Lock()
@@ -132,7 +245,24 @@ func (c *ChallengeTLSALPN) cleanChan(key string) {
 	}
 }
 
-func createMessage(certs map[string]*Certificate) dynamic.Message {
+// tlsALPNPresenter presents the TLS challenges in the store of a resolver,
+// which are provided by the ChallengeTLSALPN along with the ones of its other stores.
+type tlsALPNPresenter struct {
+	challenge *ChallengeTLSALPN
+	store     ChallengeStore
+}
+
+// Present presents a challenge to obtain new ACME certificate.
+func (p *tlsALPNPresenter) Present(domain, _, keyAuth string) error {
+	return p.challenge.present(p.store, domain, keyAuth)
+}
+
+// CleanUp cleans the challenges when certificate is obtained.
+func (p *tlsALPNPresenter) CleanUp(domain, _, keyAuth string) error {
+	return p.challenge.cleanUp(p.store, domain, keyAuth)
+}
+
+func createMessage(certs []*Certificate) dynamic.Message {
 	conf := dynamic.Message{
 		ProviderName: providerNameALPN,
 		Configuration: &dynamic.Configuration{
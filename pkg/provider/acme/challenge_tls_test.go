@@ -0,0 +1,28 @@
+package acme
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestChallengeTLSALPN_getChallenges(t *testing.T) {
+	localStore := NewLocalChallengeStore()
+	kvStore := newKVStore(newKVClientMock(), "traefik", time.Second)
+
+	c := NewChallengeTLSALPN(time.Second, localStore)
+	c.WithStore(kvStore)
+
+	localCert := &Certificate{Domain: types.Domain{Main: "TEMP-foo.com"}, Certificate: []byte("foo"), Key: []byte("key")}
+	err := localStore.AddTLSChallenge("foo", localCert)
+	require.NoError(t, err)
+
+	kvCert := &Certificate{Domain: types.Domain{Main: "TEMP-bar.com"}, Certificate: []byte("bar"), Key: []byte("key")}
+	err = kvStore.AddTLSChallenge("bar", kvCert)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []*Certificate{localCert, kvCert}, c.getChallenges())
+}
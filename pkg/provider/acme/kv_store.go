@@ -0,0 +1,337 @@
+package acme
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/abronan/valkeyrie/store"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider/kv"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+var (
+	_ Store            = (*KVStore)(nil)
+	_ Locker           = (*KVStore)(nil)
+	_ Watcher          = (*KVStore)(nil)
+	_ ChallengeStore   = (*KVStore)(nil)
+	_ ChallengeWatcher = (*KVStore)(nil)
+)
+
+// challengeTTL is the duration after which the challenges expire from the KV store,
+// so that the challenges of an instance stopped before cleaning them up do not pile up.
+const challengeTTL = 10 * time.Minute
+
+var kvBackends = map[string]store.Backend{
+	"consul":    store.CONSUL,
+	"etcd":      store.ETCDV3,
+	"redis":     store.REDIS,
+	"zookeeper": store.ZK,
+}
+
+// KVStorage holds the configuration of the KV store in which the ACME data is shared between several Traefik instances.
+type KVStorage struct {
+	Backend   string           `description:"KV store backend: consul, etcd, redis or zookeeper." json:"backend,omitempty" toml:"backend,omitempty" yaml:"backend,omitempty" export:"true"`
+	RootKey   string           `description:"Root key under which the ACME data is stored." json:"rootKey,omitempty" toml:"rootKey,omitempty" yaml:"rootKey,omitempty" export:"true"`
+	Endpoints []string         `description:"KV store endpoints." json:"endpoints,omitempty" toml:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	Username  string           `description:"KV Username." json:"username,omitempty" toml:"username,omitempty" yaml:"username,omitempty"`
+	Password  string           `description:"KV Password." json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
+	TLS       *types.ClientTLS `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	LockTTL   ptypes.Duration  `description:"Duration after which the locks held by an unresponsive instance expire." json:"lockTTL,omitempty" toml:"lockTTL,omitempty" yaml:"lockTTL,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (s *KVStorage) SetDefaults() {
+	s.RootKey = "traefik"
+	s.LockTTL = ptypes.Duration(30 * time.Second)
+}
+
+// KVStore is a Store and ChallengeStore implementation on top of a KV store, shared between several Traefik instances.
+// The data of a resolver is stored under the <rootKey>/acme/<resolverName> key,
+// and the challenges under the <rootKey>/acme/challenges key.
+type KVStore struct {
+	client  store.Store
+	rootKey string
+	lockTTL time.Duration
+}
+
+// NewKVStore creates a new KVStore from the given configuration.
+func NewKVStore(ctx context.Context, config *KVStorage) (*KVStore, error) {
+	backend, ok := kvBackends[config.Backend]
+	if !ok {
+		return nil, fmt.Errorf("unsupported KV store backend: %q", config.Backend)
+	}
+
+	if len(config.Endpoints) == 0 {
+		return nil, errors.New("no KV store endpoints")
+	}
+
+	client, err := kv.CreateKVClient(ctx, backend, config.Endpoints, config.Username, config.Password, config.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to KV store: %w", err)
+	}
+
+	return newKVStore(client, config.RootKey, time.Duration(config.LockTTL)), nil
+}
+
+func newKVStore(client store.Store, rootKey string, lockTTL time.Duration) *KVStore {
+	return &KVStore{client: client, rootKey: rootKey, lockTTL: lockTTL}
+}
+
+// GetAccount returns ACME Account.
+func (s *KVStore) GetAccount(resolverName string) (*Account, error) {
+	var account *Account
+	if err := s.get(s.key(resolverName, "account"), &account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// SaveAccount stores ACME Account.
+func (s *KVStore) SaveAccount(resolverName string, account *Account) error {
+	return s.put(s.key(resolverName, "account"), account)
+}
+
+// GetCertificates returns ACME Certificates list.
+func (s *KVStore) GetCertificates(resolverName string) ([]*CertAndStore, error) {
+	var certificates []*CertAndStore
+	if err := s.get(s.key(resolverName, "certificates"), &certificates); err != nil {
+		return nil, err
+	}
+
+	return withoutEmptyCertificates(certificates), nil
+}
+
+// SaveCertificates stores ACME Certificates list.
+func (s *KVStore) SaveCertificates(resolverName string, certificates []*CertAndStore) error {
+	return s.put(s.key(resolverName, "certificates"), certificates)
+}
+
+// Lock implements the Locker interface.
+// A lock is released after the lock TTL if the instance holding it stops responding.
+func (s *KVStore) Lock(ctx context.Context, resolverName, name string) (func(), error) {
+	renew := make(chan struct{})
+
+	locker, err := s.client.NewLock(s.key(resolverName, "locks", name), &store.LockOptions{TTL: s.lockTTL, RenewLock: renew})
+	if err != nil {
+		return nil, err
+	}
+
+	stop := make(chan struct{})
+	acquired := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			close(stop)
+		case <-acquired:
+		}
+	}()
+
+	_, err = locker.Lock(stop)
+	close(acquired)
+	if err != nil {
+		// The renewal of the lock session is started with the lock, and must be stopped whether it was acquired or not.
+		close(renew)
+		return nil, err
+	}
+
+	if ctx.Err() != nil {
+		close(renew)
+		_ = locker.Unlock()
+		return nil, ctx.Err()
+	}
+
+	return func() {
+		close(renew)
+
+		if err := locker.Unlock(); err != nil {
+			log.FromContext(ctx).Errorf("Unable to release the ACME lock %q: %v", name, err)
+		}
+	}, nil
+}
+
+// WatchCertificates implements the Watcher interface.
+func (s *KVStore) WatchCertificates(ctx context.Context, resolverName string) (<-chan []*CertAndStore, error) {
+	pairs, err := s.client.Watch(s.key(resolverName, "certificates"), ctx.Done(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	certificatesChan := make(chan []*CertAndStore)
+	go func() {
+		defer close(certificatesChan)
+
+		for pair := range pairs {
+			if pair == nil {
+				continue
+			}
+
+			var certificates []*CertAndStore
+			if err := json.Unmarshal(pair.Value, &certificates); err != nil {
+				log.FromContext(ctx).Errorf("Unable to read the ACME certificates from the KV store: %v", err)
+				continue
+			}
+
+			select {
+			case certificatesChan <- withoutEmptyCertificates(certificates):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return certificatesChan, nil
+}
+
+// GetHTTPChallengeToken returns the key authorization of the HTTP challenge for the token and domain.
+func (s *KVStore) GetHTTPChallengeToken(token, domain string) ([]byte, error) {
+	pair, err := s.client.Get(s.challengeKey("http", token, domain), nil)
+	if errors.Is(err, store.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil || pair == nil {
+		return nil, err
+	}
+
+	return pair.Value, nil
+}
+
+// SetHTTPChallengeToken stores the key authorization of the HTTP challenge for the token and domain.
+func (s *KVStore) SetHTTPChallengeToken(token, domain string, keyAuth []byte) error {
+	return s.client.Put(s.challengeKey("http", token, domain), keyAuth, &store.WriteOptions{TTL: challengeTTL})
+}
+
+// RemoveHTTPChallengeToken removes the HTTP challenge for the token and domain.
+func (s *KVStore) RemoveHTTPChallengeToken(token, domain string) error {
+	return s.delete(s.challengeKey("http", token, domain))
+}
+
+// GetTLSChallenges returns the certificates of the TLS challenges.
+func (s *KVStore) GetTLSChallenges() ([]*Certificate, error) {
+	pairs, err := s.client.List(s.challengeKey("tls"), nil)
+	if errors.Is(err, store.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTLSChallenges(pairs), nil
+}
+
+// AddTLSChallenge stores the certificate of the TLS challenge for the key authorization.
+func (s *KVStore) AddTLSChallenge(keyAuth string, cert *Certificate) error {
+	data, err := json.Marshal(cert)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Put(s.challengeKey("tls", keyAuth), data, &store.WriteOptions{TTL: challengeTTL})
+}
+
+// RemoveTLSChallenge removes the TLS challenge for the key authorization.
+func (s *KVStore) RemoveTLSChallenge(keyAuth string) error {
+	return s.delete(s.challengeKey("tls", keyAuth))
+}
+
+// WatchTLSChallenges implements the ChallengeWatcher interface.
+func (s *KVStore) WatchTLSChallenges(ctx context.Context) (<-chan []*Certificate, error) {
+	pairsChan, err := s.client.WatchTree(s.challengeKey("tls"), ctx.Done(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	certsChan := make(chan []*Certificate)
+	go func() {
+		defer close(certsChan)
+
+		for pairs := range pairsChan {
+			select {
+			case certsChan <- decodeTLSChallenges(pairs):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return certsChan, nil
+}
+
+func (s *KVStore) key(resolverName string, parts ...string) string {
+	return path.Join(append([]string{s.rootKey, "acme", resolverName}, parts...)...)
+}
+
+func (s *KVStore) challengeKey(parts ...string) string {
+	return path.Join(append([]string{s.rootKey, "acme", "challenges"}, parts...)...)
+}
+
+func (s *KVStore) get(key string, value interface{}) error {
+	pair, err := s.client.Get(key, nil)
+	if errors.Is(err, store.ErrKeyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if pair == nil || len(pair.Value) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(pair.Value, value)
+}
+
+func (s *KVStore) put(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Put(key, data, nil)
+}
+
+func (s *KVStore) delete(key string) error {
+	err := s.client.Delete(key)
+	if errors.Is(err, store.ErrKeyNotFound) {
+		return nil
+	}
+	return err
+}
+
+// decodeTLSChallenges decodes the certificates of the TLS challenges, skipping the invalid ones.
+func decodeTLSChallenges(pairs []*store.KVPair) []*Certificate {
+	var certs []*Certificate
+	for _, pair := range pairs {
+		if pair == nil || len(pair.Value) == 0 {
+			continue
+		}
+
+		var cert Certificate
+		if err := json.Unmarshal(pair.Value, &cert); err != nil {
+			log.WithoutContext().Errorf("Unable to read the TLS challenge %q from the KV store: %v", pair.Key, err)
+			continue
+		}
+		certs = append(certs, &cert)
+	}
+
+	return certs
+}
+
+// withoutEmptyCertificates filters out the certificates with no value.
+func withoutEmptyCertificates(certificates []*CertAndStore) []*CertAndStore {
+	var result []*CertAndStore
+	for _, certificate := range certificates {
+		if len(certificate.Certificate.Certificate) == 0 || len(certificate.Key) == 0 {
+			continue
+		}
+		result = append(result, certificate)
+	}
+
+	return result
+}
@@ -0,0 +1,515 @@
+package acme
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abronan/valkeyrie/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestKVStore_account(t *testing.T) {
+	client := newKVClientMock()
+	s := newKVStore(client, "traefik", time.Second)
+
+	account, err := s.GetAccount("test")
+	require.NoError(t, err)
+	assert.Nil(t, account)
+
+	err = s.SaveAccount("test", &Account{Email: "some42@email.com"})
+	require.NoError(t, err)
+
+	assert.Contains(t, client.keys(), "traefik/acme/test/account")
+
+	account, err = s.GetAccount("test")
+	require.NoError(t, err)
+	assert.Equal(t, &Account{Email: "some42@email.com"}, account)
+
+	// Another instance sharing the KV store gets the same account.
+	account, err = newKVStore(client, "traefik", time.Second).GetAccount("test")
+	require.NoError(t, err)
+	assert.Equal(t, &Account{Email: "some42@email.com"}, account)
+}
+
+func TestKVStore_certificates(t *testing.T) {
+	client := newKVClientMock()
+	s := newKVStore(client, "traefik", time.Second)
+
+	certificates := []*CertAndStore{
+		{
+			Certificate: Certificate{Domain: types.Domain{Main: "foo.com"}, Certificate: []byte("cert"), Key: []byte("key")},
+			Store:       "default",
+		},
+		{
+			Certificate: Certificate{Domain: types.Domain{Main: "empty.com"}},
+			Store:       "default",
+		},
+	}
+
+	err := s.SaveCertificates("test", certificates)
+	require.NoError(t, err)
+
+	assert.Contains(t, client.keys(), "traefik/acme/test/certificates")
+
+	stored, err := s.GetCertificates("test")
+	require.NoError(t, err)
+	assert.Equal(t, certificates[:1], stored)
+
+	stored, err = s.GetCertificates("other")
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
+
+func TestKVStore_Lock(t *testing.T) {
+	client := newKVClientMock()
+	s := newKVStore(client, "traefik", time.Second)
+
+	unlock, err := s.Lock(context.Background(), "test", "domains/foo.com")
+	require.NoError(t, err)
+
+	// The lock is held by another instance.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = newKVStore(client, "traefik", time.Second).Lock(ctx, "test", "domains/foo.com")
+	assert.Error(t, err)
+
+	// Other locks are independent.
+	unlockBar, err := s.Lock(context.Background(), "test", "domains/bar.com")
+	require.NoError(t, err)
+	unlockBar()
+
+	acquired := make(chan struct{})
+	go func() {
+		unlock, err := newKVStore(client, "traefik", time.Second).Lock(context.Background(), "test", "domains/foo.com")
+		if assert.NoError(t, err) {
+			unlock()
+		}
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("the lock should not be acquired before it is released")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("the lock should be acquired once released")
+	}
+}
+
+func TestKVStore_WatchCertificates(t *testing.T) {
+	client := newKVClientMock()
+	s := newKVStore(client, "traefik", time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	certificatesChan, err := s.WatchCertificates(ctx, "test")
+	require.NoError(t, err)
+
+	certificates := []*CertAndStore{{
+		Certificate: Certificate{Domain: types.Domain{Main: "foo.com"}, Certificate: []byte("cert"), Key: []byte("key")},
+		Store:       "default",
+	}}
+
+	// Saved by another instance.
+	err = newKVStore(client, "traefik", time.Second).SaveCertificates("test", certificates)
+	require.NoError(t, err)
+
+	select {
+	case stored := <-certificatesChan:
+		assert.Equal(t, certificates, stored)
+	case <-time.After(time.Second):
+		t.Fatal("certificates not received")
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-certificatesChan:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("the channel should be closed")
+	}
+}
+
+func TestKVStore_httpChallenges(t *testing.T) {
+	client := newKVClientMock()
+	s := newKVStore(client, "traefik", time.Second)
+
+	keyAuth, err := s.GetHTTPChallengeToken("token", "foo.com")
+	require.NoError(t, err)
+	assert.Nil(t, keyAuth)
+
+	err = s.SetHTTPChallengeToken("token", "foo.com", []byte("keyAuth"))
+	require.NoError(t, err)
+
+	assert.Equal(t, challengeTTL, client.ttl("traefik/acme/challenges/http/token/foo.com"))
+
+	keyAuth, err = s.GetHTTPChallengeToken("token", "foo.com")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("keyAuth"), keyAuth)
+
+	keyAuth, err = s.GetHTTPChallengeToken("token", "bar.com")
+	require.NoError(t, err)
+	assert.Nil(t, keyAuth)
+
+	err = s.RemoveHTTPChallengeToken("token", "foo.com")
+	require.NoError(t, err)
+
+	keyAuth, err = s.GetHTTPChallengeToken("token", "foo.com")
+	require.NoError(t, err)
+	assert.Nil(t, keyAuth)
+
+	// Removing a missing challenge is not an error.
+	err = s.RemoveHTTPChallengeToken("token", "foo.com")
+	require.NoError(t, err)
+}
+
+func TestKVStore_tlsChallenges(t *testing.T) {
+	client := newKVClientMock()
+	s := newKVStore(client, "traefik", time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	certsChan, err := s.WatchTLSChallenges(ctx)
+	require.NoError(t, err)
+
+	certs, err := s.GetTLSChallenges()
+	require.NoError(t, err)
+	assert.Empty(t, certs)
+
+	cert := &Certificate{Domain: types.Domain{Main: "TEMP-foo.com"}, Certificate: []byte("cert"), Key: []byte("key")}
+
+	// Presented by another instance.
+	err = newKVStore(client, "traefik", time.Second).AddTLSChallenge("keyAuth", cert)
+	require.NoError(t, err)
+
+	assert.Equal(t, challengeTTL, client.ttl("traefik/acme/challenges/tls/keyAuth"))
+
+	select {
+	case certs := <-certsChan:
+		assert.Equal(t, []*Certificate{cert}, certs)
+	case <-time.After(time.Second):
+		t.Fatal("TLS challenges not received")
+	}
+
+	certs, err = s.GetTLSChallenges()
+	require.NoError(t, err)
+	assert.Equal(t, []*Certificate{cert}, certs)
+
+	err = s.RemoveTLSChallenge("keyAuth")
+	require.NoError(t, err)
+
+	select {
+	case certs := <-certsChan:
+		assert.Empty(t, certs)
+	case <-time.After(time.Second):
+		t.Fatal("TLS challenges not received")
+	}
+}
+
+func TestNewKVStore_errors(t *testing.T) {
+	_, err := NewKVStore(context.Background(), &KVStorage{Backend: "unknown", Endpoints: []string{"127.0.0.1:8500"}})
+	assert.Error(t, err)
+
+	_, err = NewKVStore(context.Background(), &KVStorage{Backend: "consul"})
+	assert.Error(t, err)
+}
+
+// kvClientMock is an in-memory KV store, supporting the operations used by the KVStore.
+func TestKVStore_Lock_renewalStopped(t *testing.T) {
+	client := newKVClientMock()
+	s := newKVStore(client, "traefik", time.Second)
+
+	unlock, err := s.Lock(context.Background(), "test", "domains/foo.com")
+	require.NoError(t, err)
+
+	// The lock cannot be acquired before the context is canceled.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = s.Lock(ctx, "test", "domains/foo.com")
+	assert.Error(t, err)
+
+	unlock()
+
+	// The lock is acquired, but the context is already canceled.
+	canceledCtx, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+
+	_, err = s.Lock(canceledCtx, "test", "domains/foo.com")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	require.Len(t, client.renewChans, 3)
+	for _, renew := range client.renewChans {
+		select {
+		case <-renew:
+		default:
+			t.Error("the renewal of the lock session was not stopped")
+		}
+	}
+}
+
+type kvClientMock struct {
+	mu           sync.Mutex
+	pairs        map[string][]byte
+	ttls         map[string]time.Duration
+	locks        map[string]chan struct{}
+	renewChans   []chan struct{}
+	watchers     map[string][]chan *store.KVPair
+	treeWatchers map[string][]chan []*store.KVPair
+}
+
+func newKVClientMock() *kvClientMock {
+	return &kvClientMock{
+		pairs:        map[string][]byte{},
+		ttls:         map[string]time.Duration{},
+		locks:        map[string]chan struct{}{},
+		watchers:     map[string][]chan *store.KVPair{},
+		treeWatchers: map[string][]chan []*store.KVPair{},
+	}
+}
+
+// notifyTrees notifies the watchers of the trees containing the key, with the lock held.
+func (m *kvClientMock) notifyTrees(key string) {
+	for directory, watchers := range m.treeWatchers {
+		if !strings.HasPrefix(key, directory) {
+			continue
+		}
+
+		pairs := m.list(directory)
+		for _, watcher := range watchers {
+			watcher <- pairs
+		}
+	}
+}
+
+func (m *kvClientMock) list(directory string) []*store.KVPair {
+	var pairs []*store.KVPair
+	for key, value := range m.pairs {
+		if strings.HasPrefix(key, directory) {
+			pairs = append(pairs, &store.KVPair{Key: key, Value: value})
+		}
+	}
+	return pairs
+}
+
+func (m *kvClientMock) keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var keys []string
+	for key := range m.pairs {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (m *kvClientMock) ttl(key string) time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.ttls[key]
+}
+
+func (m *kvClientMock) Put(key string, value []byte, options *store.WriteOptions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pairs[key] = value
+
+	delete(m.ttls, key)
+	if options != nil {
+		m.ttls[key] = options.TTL
+	}
+
+	for _, watcher := range m.watchers[key] {
+		watcher <- &store.KVPair{Key: key, Value: value}
+	}
+	m.notifyTrees(key)
+
+	return nil
+}
+
+func (m *kvClientMock) Get(key string, _ *store.ReadOptions) (*store.KVPair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	value, ok := m.pairs[key]
+	if !ok {
+		return nil, store.ErrKeyNotFound
+	}
+
+	return &store.KVPair{Key: key, Value: value}, nil
+}
+
+func (m *kvClientMock) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.pairs[key]; !ok {
+		return store.ErrKeyNotFound
+	}
+
+	delete(m.pairs, key)
+	delete(m.ttls, key)
+	m.notifyTrees(key)
+
+	return nil
+}
+
+func (m *kvClientMock) Exists(key string, _ *store.ReadOptions) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.pairs[key]
+	return ok, nil
+}
+
+func (m *kvClientMock) Watch(key string, stopCh <-chan struct{}, _ *store.ReadOptions) (<-chan *store.KVPair, error) {
+	// Buffered so that Put does not block on a watcher which is not read.
+	events := make(chan *store.KVPair, 10)
+
+	m.mu.Lock()
+	m.watchers[key] = append(m.watchers[key], events)
+	m.mu.Unlock()
+
+	watchCh := make(chan *store.KVPair)
+	go func() {
+		defer close(watchCh)
+
+		for {
+			select {
+			case <-stopCh:
+				return
+			case pair := <-events:
+				select {
+				case watchCh <- pair:
+				case <-stopCh:
+					return
+				}
+			}
+		}
+	}()
+
+	return watchCh, nil
+}
+
+func (m *kvClientMock) WatchTree(directory string, stopCh <-chan struct{}, _ *store.ReadOptions) (<-chan []*store.KVPair, error) {
+	// Buffered so that Put does not block on a watcher which is not read.
+	events := make(chan []*store.KVPair, 10)
+
+	m.mu.Lock()
+	m.treeWatchers[directory] = append(m.treeWatchers[directory], events)
+	m.mu.Unlock()
+
+	watchCh := make(chan []*store.KVPair)
+	go func() {
+		defer close(watchCh)
+
+		for {
+			select {
+			case <-stopCh:
+				return
+			case pairs := <-events:
+				select {
+				case watchCh <- pairs:
+				case <-stopCh:
+					return
+				}
+			}
+		}
+	}()
+
+	return watchCh, nil
+}
+
+func (m *kvClientMock) NewLock(key string, options *store.LockOptions) (store.Locker, error) {
+	if options != nil && options.RenewLock != nil {
+		m.mu.Lock()
+		m.renewChans = append(m.renewChans, options.RenewLock)
+		m.mu.Unlock()
+	}
+
+	return &lockMock{client: m, key: key}, nil
+}
+
+func (m *kvClientMock) List(directory string, _ *store.ReadOptions) ([]*store.KVPair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pairs := m.list(directory)
+	if len(pairs) == 0 {
+		return nil, store.ErrKeyNotFound
+	}
+	return pairs, nil
+}
+
+func (m *kvClientMock) DeleteTree(string) error {
+	return errors.New("method DeleteTree not supported")
+}
+
+func (m *kvClientMock) AtomicPut(string, []byte, *store.KVPair, *store.WriteOptions) (bool, *store.KVPair, error) {
+	return false, nil, errors.New("method AtomicPut not supported")
+}
+
+func (m *kvClientMock) AtomicDelete(string, *store.KVPair) (bool, error) {
+	return false, errors.New("method AtomicDelete not supported")
+}
+
+func (m *kvClientMock) Close() {}
+
+type lockMock struct {
+	client *kvClientMock
+	key    string
+}
+
+func (l *lockMock) Lock(stopChan chan struct{}) (<-chan struct{}, error) {
+	for {
+		l.client.mu.Lock()
+		held, ok := l.client.locks[l.key]
+		if !ok {
+			l.client.locks[l.key] = make(chan struct{})
+			l.client.mu.Unlock()
+			return make(chan struct{}), nil
+		}
+		l.client.mu.Unlock()
+
+		select {
+		case <-held:
+		case <-stopChan:
+			return nil, store.ErrCannotLock
+		}
+	}
+}
+
+func (l *lockMock) Unlock() error {
+	l.client.mu.Lock()
+	defer l.client.mu.Unlock()
+
+	held, ok := l.client.locks[l.key]
+	if !ok {
+		return errors.New("lock not held")
+	}
+
+	delete(l.client.locks, l.key)
+	close(held)
+	return nil
+}
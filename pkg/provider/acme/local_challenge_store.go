@@ -0,0 +1,93 @@
+package acme
+
+import "sync"
+
+var _ ChallengeStore = (*LocalChallengeStore)(nil)
+
+// LocalChallengeStore is an in-memory ChallengeStore, only suitable for a single Traefik instance.
+type LocalChallengeStore struct {
+	httpChallenges map[string]map[string][]byte
+	httpLock       sync.RWMutex
+
+	tlsChallenges map[string]*Certificate
+	tlsLock       sync.RWMutex
+}
+
+// NewLocalChallengeStore creates a new LocalChallengeStore.
+func NewLocalChallengeStore() *LocalChallengeStore {
+	return &LocalChallengeStore{
+		httpChallenges: make(map[string]map[string][]byte),
+		tlsChallenges:  make(map[string]*Certificate),
+	}
+}
+
+// GetHTTPChallengeToken returns the key authorization of the HTTP challenge for the token and domain.
+func (s *LocalChallengeStore) GetHTTPChallengeToken(token, domain string) ([]byte, error) {
+	s.httpLock.RLock()
+	defer s.httpLock.RUnlock()
+
+	return s.httpChallenges[token][domain], nil
+}
+
+// SetHTTPChallengeToken stores the key authorization of the HTTP challenge for the token and domain.
+func (s *LocalChallengeStore) SetHTTPChallengeToken(token, domain string, keyAuth []byte) error {
+	s.httpLock.Lock()
+	defer s.httpLock.Unlock()
+
+	if _, ok := s.httpChallenges[token]; !ok {
+		s.httpChallenges[token] = map[string][]byte{}
+	}
+
+	s.httpChallenges[token][domain] = keyAuth
+
+	return nil
+}
+
+// RemoveHTTPChallengeToken removes the HTTP challenge for the token and domain.
+func (s *LocalChallengeStore) RemoveHTTPChallengeToken(token, domain string) error {
+	s.httpLock.Lock()
+	defer s.httpLock.Unlock()
+
+	if _, ok := s.httpChallenges[token]; ok {
+		delete(s.httpChallenges[token], domain)
+
+		if len(s.httpChallenges[token]) == 0 {
+			delete(s.httpChallenges, token)
+		}
+	}
+
+	return nil
+}
+
+// GetTLSChallenges returns the certificates of the TLS challenges.
+func (s *LocalChallengeStore) GetTLSChallenges() ([]*Certificate, error) {
+	s.tlsLock.RLock()
+	defer s.tlsLock.RUnlock()
+
+	var certs []*Certificate
+	for _, cert := range s.tlsChallenges {
+		certs = append(certs, cert)
+	}
+
+	return certs, nil
+}
+
+// AddTLSChallenge stores the certificate of the TLS challenge for the key authorization.
+func (s *LocalChallengeStore) AddTLSChallenge(keyAuth string, cert *Certificate) error {
+	s.tlsLock.Lock()
+	defer s.tlsLock.Unlock()
+
+	s.tlsChallenges[keyAuth] = cert
+
+	return nil
+}
+
+// RemoveTLSChallenge removes the TLS challenge for the key authorization.
+func (s *LocalChallengeStore) RemoveTLSChallenge(keyAuth string) error {
+	s.tlsLock.Lock()
+	defer s.tlsLock.Unlock()
+
+	delete(s.tlsChallenges, keyAuth)
+
+	return nil
+}
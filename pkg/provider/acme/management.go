@@ -0,0 +1,226 @@
+package acme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// ErrCertificateNotFound is returned when the resolver has no certificate for the domain.
+var ErrCertificateNotFound = errors.New("certificate not found")
+
+// ErrRenewalInProgress is returned when a renewal of the certificate is already in progress.
+var ErrRenewalInProgress = errors.New("the certificate is already being renewed")
+
+// CertificateInfo holds the information about a certificate of a resolver.
+type CertificateInfo struct {
+	Domain             types.Domain `json:"domain"`
+	Store              string       `json:"store,omitempty"`
+	Issuer             string       `json:"issuer,omitempty"`
+	NotAfter           *time.Time   `json:"notAfter,omitempty"`
+	Expired            bool         `json:"expired"`
+	Renewing           bool         `json:"renewing,omitempty"`
+	LastRenewalAttempt *time.Time   `json:"lastRenewalAttempt,omitempty"`
+	LastRenewalError   string       `json:"lastRenewalError,omitempty"`
+	Error              string       `json:"error,omitempty"`
+}
+
+// renewalStatus is the status of the renewals of a certificate by this instance.
+type renewalStatus struct {
+	inProgress  bool
+	lastAttempt time.Time
+	lastErr     error
+}
+
+// Certificates returns the information about the stored certificates.
+func (p *Provider) Certificates() ([]CertificateInfo, error) {
+	certificates, err := p.Store.GetCertificates(p.ResolverName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get ACME certificates: %w", err)
+	}
+
+	ctx := p.logContext()
+
+	p.renewalsMutex.Lock()
+	defer p.renewalsMutex.Unlock()
+
+	infos := make([]CertificateInfo, 0, len(certificates))
+	for _, cert := range certificates {
+		info := CertificateInfo{
+			Domain: cert.Domain,
+			Store:  cert.Store,
+		}
+
+		crt, err := getX509Certificate(ctx, &cert.Certificate)
+		if err != nil {
+			info.Error = err.Error()
+		} else if crt != nil {
+			notAfter := crt.NotAfter
+			info.Issuer = crt.Issuer.String()
+			info.NotAfter = &notAfter
+			info.Expired = time.Now().After(notAfter)
+		}
+
+		if status, ok := p.renewals[renewalKey(cert.Domain)]; ok {
+			info.Renewing = status.inProgress
+			if !status.lastAttempt.IsZero() {
+				lastAttempt := status.lastAttempt
+				info.LastRenewalAttempt = &lastAttempt
+			}
+			if status.lastErr != nil {
+				info.LastRenewalError = status.lastErr.Error()
+			}
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// RenewCertificate starts renewing the certificate of the domain, whatever its expiration date.
+// It returns ErrRenewalInProgress if the certificate is already being renewed by this instance.
+func (p *Provider) RenewCertificate(domain string) error {
+	cert, err := p.findCertificate(domain)
+	if err != nil {
+		return err
+	}
+
+	if p.pool == nil {
+		return errors.New("the resolver is not started")
+	}
+
+	if !p.startRenewal(cert.Domain) {
+		return ErrRenewalInProgress
+	}
+
+	p.pool.GoCtx(func(ctxPool context.Context) {
+		ctx := log.With(ctxPool, log.Str(log.ProviderName, p.ResolverName+".acme"))
+
+		renewPeriod, _ := getCertificateRenewDurations(p.CertificatesDuration)
+		if err := p.renewStartedCertificate(ctx, cert, renewPeriod, true); err != nil {
+			log.FromContext(ctx).Errorf("Error renewing certificate from LE: %v, %v", cert.Domain, err)
+		}
+	})
+
+	return nil
+}
+
+// RevokeCertificate revokes the certificate of the domain, and removes it from the store.
+func (p *Provider) RevokeCertificate(domain string) error {
+	cert, err := p.findCertificate(domain)
+	if err != nil {
+		return err
+	}
+
+	client, err := p.getClient()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Certificate.Revoke(cert.Certificate.Certificate); err != nil {
+		return fmt.Errorf("unable to revoke the certificate: %w", err)
+	}
+
+	log.FromContext(p.logContext()).Infof("Certificate revoked for domains %v", cert.Domain.ToStrArray())
+
+	return p.removeCertificateForDomain(cert.Domain)
+}
+
+// DeleteCertificate removes the certificate of the domain from the store, without revoking it.
+func (p *Provider) DeleteCertificate(domain string) error {
+	cert, err := p.findCertificate(domain)
+	if err != nil {
+		return err
+	}
+
+	return p.removeCertificateForDomain(cert.Domain)
+}
+
+// findCertificate returns the stored certificate whose main domain is the given domain.
+func (p *Provider) findCertificate(domain string) (*CertAndStore, error) {
+	certificates, err := p.Store.GetCertificates(p.ResolverName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get ACME certificates: %w", err)
+	}
+
+	for _, cert := range certificates {
+		if strings.EqualFold(cert.Domain.Main, domain) {
+			return cert, nil
+		}
+	}
+
+	return nil, ErrCertificateNotFound
+}
+
+// removeCertificateForDomain removes the certificate from the store, and returns once it has been saved.
+func (p *Provider) removeCertificateForDomain(domain types.Domain) error {
+	if p.certsChan == nil {
+		return errors.New("the resolver is not started")
+	}
+
+	update := &certificateUpdate{
+		CertAndStore: &CertAndStore{Certificate: Certificate{Domain: domain}},
+		remove:       true,
+		saved:        make(chan struct{}),
+	}
+	p.certsChan <- update
+	<-update.saved
+
+	return update.err
+}
+
+// startRenewal marks the certificate of the domain as being renewed,
+// and returns false if it is already being renewed.
+func (p *Provider) startRenewal(domain types.Domain) bool {
+	p.renewalsMutex.Lock()
+	defer p.renewalsMutex.Unlock()
+
+	if p.renewals == nil {
+		p.renewals = make(map[string]*renewalStatus)
+	}
+
+	key := renewalKey(domain)
+
+	status, ok := p.renewals[key]
+	if !ok {
+		status = &renewalStatus{}
+		p.renewals[key] = status
+	}
+
+	if status.inProgress {
+		return false
+	}
+
+	status.inProgress = true
+	status.lastAttempt = time.Now()
+
+	return true
+}
+
+// endRenewal records the result of the renewal of the certificate of the domain.
+func (p *Provider) endRenewal(domain types.Domain, err error) {
+	p.renewalsMutex.Lock()
+	defer p.renewalsMutex.Unlock()
+
+	status, ok := p.renewals[renewalKey(domain)]
+	if !ok {
+		return
+	}
+
+	status.inProgress = false
+	status.lastErr = err
+}
+
+func (p *Provider) logContext() context.Context {
+	return log.With(context.Background(), log.Str(log.ProviderName, p.ResolverName+".acme"))
+}
+
+func renewalKey(domain types.Domain) string {
+	return strings.Join(domain.ToStrArray(), ",")
+}
@@ -0,0 +1,137 @@
+package acme
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/tls/generate"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestProvider_Certificates(t *testing.T) {
+	notAfter := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	certPEM, keyPEM, err := generate.KeyPair("foo.com", notAfter)
+	require.NoError(t, err)
+
+	expiredPEM, expiredKeyPEM, err := generate.KeyPair("expired.com", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+
+	p := &Provider{
+		ResolverName: "test",
+		Store:        newKVStore(newKVClientMock(), "traefik", time.Second),
+	}
+
+	err = p.Store.SaveCertificates("test", []*CertAndStore{
+		{
+			Certificate: Certificate{Domain: types.Domain{Main: "foo.com", SANs: []string{"www.foo.com"}}, Certificate: certPEM, Key: keyPEM},
+			Store:       "default",
+		},
+		{
+			Certificate: Certificate{Domain: types.Domain{Main: "expired.com"}, Certificate: expiredPEM, Key: expiredKeyPEM},
+			Store:       "default",
+		},
+		{
+			Certificate: Certificate{Domain: types.Domain{Main: "broken.com"}, Certificate: []byte("broken"), Key: []byte("key")},
+			Store:       "default",
+		},
+	})
+	require.NoError(t, err)
+
+	require.True(t, p.startRenewal(types.Domain{Main: "foo.com", SANs: []string{"www.foo.com"}}))
+	p.endRenewal(types.Domain{Main: "foo.com", SANs: []string{"www.foo.com"}}, errors.New("rate limited"))
+
+	require.True(t, p.startRenewal(types.Domain{Main: "expired.com"}))
+	assert.False(t, p.startRenewal(types.Domain{Main: "expired.com"}))
+
+	infos, err := p.Certificates()
+	require.NoError(t, err)
+	require.Len(t, infos, 3)
+
+	assert.Equal(t, "foo.com", infos[0].Domain.Main)
+	assert.Equal(t, "CN=TRAEFIK DEFAULT CERT", infos[0].Issuer)
+	require.NotNil(t, infos[0].NotAfter)
+	assert.True(t, notAfter.Equal(*infos[0].NotAfter))
+	assert.False(t, infos[0].Expired)
+	assert.False(t, infos[0].Renewing)
+	assert.NotNil(t, infos[0].LastRenewalAttempt)
+	assert.Equal(t, "rate limited", infos[0].LastRenewalError)
+
+	assert.Equal(t, "expired.com", infos[1].Domain.Main)
+	assert.True(t, infos[1].Expired)
+	assert.True(t, infos[1].Renewing)
+	assert.Empty(t, infos[1].LastRenewalError)
+
+	assert.Equal(t, "broken.com", infos[2].Domain.Main)
+	assert.Nil(t, infos[2].NotAfter)
+	assert.NotEmpty(t, infos[2].Error)
+	assert.Nil(t, infos[2].LastRenewalAttempt)
+}
+
+func TestProvider_DeleteCertificate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	fooCert := &CertAndStore{
+		Certificate: Certificate{Domain: types.Domain{Main: "foo.com"}, Certificate: []byte("foo"), Key: []byte("key")},
+		Store:       "default",
+	}
+	barCert := &CertAndStore{
+		Certificate: Certificate{Domain: types.Domain{Main: "bar.com"}, Certificate: []byte("bar"), Key: []byte("key")},
+		Store:       "default",
+	}
+
+	configurationChan := make(chan dynamic.Message, 10)
+	p := &Provider{
+		ResolverName:      "test",
+		Store:             newKVStore(newKVClientMock(), "traefik", time.Second),
+		configurationChan: configurationChan,
+		pool:              safe.NewPool(ctx),
+		certificates:      []*CertAndStore{fooCert, barCert},
+	}
+
+	err := p.Store.SaveCertificates("test", p.certificates)
+	require.NoError(t, err)
+
+	p.watchCertificate(ctx)
+
+	err = p.DeleteCertificate("unknown.com")
+	assert.ErrorIs(t, err, ErrCertificateNotFound)
+
+	err = p.DeleteCertificate("foo.com")
+	require.NoError(t, err)
+
+	stored, err := p.Store.GetCertificates("test")
+	require.NoError(t, err)
+	assert.Equal(t, []*CertAndStore{barCert}, stored)
+
+	msg := <-configurationChan
+	assert.Len(t, msg.Configuration.TLS.Certificates, 1)
+}
+
+func TestProvider_RenewCertificate_inProgress(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	p := &Provider{
+		ResolverName: "test",
+		Store:        newKVStore(newKVClientMock(), "traefik", time.Second),
+		pool:         safe.NewPool(ctx),
+	}
+
+	err := p.Store.SaveCertificates("test", []*CertAndStore{{
+		Certificate: Certificate{Domain: types.Domain{Main: "foo.com"}, Certificate: []byte("foo"), Key: []byte("key")},
+		Store:       "default",
+	}})
+	require.NoError(t, err)
+
+	require.True(t, p.startRenewal(types.Domain{Main: "foo.com"}))
+
+	err = p.RenewCertificate("foo.com")
+	assert.ErrorIs(t, err, ErrRenewalInProgress)
+}
@@ -0,0 +1,255 @@
+package acme
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/log"
+	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+	"github.com/traefik/traefik/v2/pkg/types"
+	"golang.org/x/time/rate"
+)
+
+var _ traefiktls.OnDemandResolver = (*Provider)(nil)
+
+const (
+	// askTimeout is the timeout of the requests to the ask endpoint.
+	askTimeout = 5 * time.Second
+
+	// defaultOnDemandTimeout is the default maximum duration a TLS handshake waits for a certificate.
+	defaultOnDemandTimeout = 5 * time.Second
+	// maxOnDemandTimeout bounds the duration a TLS handshake waits for a certificate,
+	// as the handshake blocks the connection, and the client gives up anyway.
+	maxOnDemandTimeout = 15 * time.Second
+)
+
+// OnDemand holds the configuration of the certificates obtained during the TLS handshakes.
+type OnDemand struct {
+	Ask              string             `description:"URL called with the domain query parameter, which must answer with a 2XX status code to allow obtaining a certificate for the domain." json:"ask,omitempty" toml:"ask,omitempty" yaml:"ask,omitempty"`
+	DomainRegex      string             `description:"Regular expression the domains must match to allow obtaining a certificate." json:"domainRegex,omitempty" toml:"domainRegex,omitempty" yaml:"domainRegex,omitempty" export:"true"`
+	RateLimit        *OnDemandRateLimit `description:"Limits the rate at which certificates are obtained on demand." json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty" export:"true"`
+	NegativeCacheTTL ptypes.Duration    `description:"Duration during which a domain is not retried after being refused or failing." json:"negativeCacheTTL,omitempty" toml:"negativeCacheTTL,omitempty" yaml:"negativeCacheTTL,omitempty" export:"true"`
+	Timeout          ptypes.Duration    `description:"Maximum duration a TLS handshake waits for the certificate, before the default certificate is served (at most 15s)." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (o *OnDemand) SetDefaults() {
+	o.RateLimit = &OnDemandRateLimit{}
+	o.RateLimit.SetDefaults()
+	o.NegativeCacheTTL = ptypes.Duration(10 * time.Minute)
+	o.Timeout = ptypes.Duration(defaultOnDemandTimeout)
+}
+
+// OnDemandRateLimit holds the rate limit of the certificates obtained on demand.
+type OnDemandRateLimit struct {
+	Average int64           `description:"Maximum number of certificates obtained on average per period." json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty" export:"true"`
+	Period  ptypes.Duration `description:"Period of the rate limit." json:"period,omitempty" toml:"period,omitempty" yaml:"period,omitempty" export:"true"`
+	Burst   int64           `description:"Maximum number of certificates obtained in a row (at least 1)." json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (r *OnDemandRateLimit) SetDefaults() {
+	r.Average = 60
+	r.Period = ptypes.Duration(time.Hour)
+	r.Burst = 10
+}
+
+// onDemand obtains the certificates during the TLS handshakes.
+type onDemand struct {
+	config      *OnDemand
+	domainRegex *regexp.Regexp
+	askClient   *http.Client
+	limiter     *rate.Limiter
+	refused     *cache.Cache
+	timeout     time.Duration
+
+	callsMu sync.Mutex
+	calls   map[string]*onDemandCall
+}
+
+// onDemandCall is an in-flight attempt to obtain a certificate, shared by the concurrent handshakes for the domain.
+type onDemandCall struct {
+	done chan struct{}
+	cert *tls.Certificate
+	err  error
+}
+
+func newOnDemand(config *OnDemand) (*onDemand, error) {
+	if config.Ask == "" && config.DomainRegex == "" {
+		return nil, errors.New("an ask endpoint or a domain regex is required to obtain certificates on demand")
+	}
+
+	o := &onDemand{
+		config:    config,
+		askClient: &http.Client{Timeout: askTimeout},
+		limiter:   rate.NewLimiter(rate.Inf, 0),
+		refused:   cache.New(time.Duration(config.NegativeCacheTTL), time.Duration(config.NegativeCacheTTL)),
+		calls:     map[string]*onDemandCall{},
+		timeout:   time.Duration(config.Timeout),
+	}
+
+	switch {
+	case o.timeout <= 0:
+		o.timeout = defaultOnDemandTimeout
+	case o.timeout > maxOnDemandTimeout:
+		log.WithoutContext().Warnf("The on-demand timeout %s is greater than the maximum %s, using the maximum", o.timeout, maxOnDemandTimeout)
+		o.timeout = maxOnDemandTimeout
+	}
+
+	if config.DomainRegex != "" {
+		var err error
+		o.domainRegex, err = regexp.Compile(config.DomainRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid on-demand domain regex: %w", err)
+		}
+	}
+
+	if config.Ask != "" {
+		if _, err := url.Parse(config.Ask); err != nil {
+			return nil, fmt.Errorf("invalid on-demand ask URL: %w", err)
+		}
+	}
+
+	if rl := config.RateLimit; rl != nil && rl.Average > 0 && rl.Period > 0 {
+		// A burst lower than 1 would refuse every certificate.
+		burst := int(rl.Burst)
+		if burst < 1 {
+			burst = 1
+		}
+
+		o.limiter = rate.NewLimiter(rate.Limit(float64(rl.Average)/time.Duration(rl.Period).Seconds()), burst)
+	}
+
+	return o, nil
+}
+
+// GetCertificate implements the tls.OnDemandResolver interface.
+// Concurrent handshakes for a domain share the same attempt, which goes on after the handshakes time out.
+func (p *Provider) GetCertificate(serverName string) (*tls.Certificate, error) {
+	if p.onDemand == nil {
+		return nil, nil
+	}
+
+	domain := types.CanonicalDomain(serverName)
+	if net.ParseIP(domain) != nil {
+		return nil, nil
+	}
+
+	if _, refused := p.onDemand.refused.Get(domain); refused {
+		return nil, fmt.Errorf("domain %q recently refused", domain)
+	}
+
+	o := p.onDemand
+
+	o.callsMu.Lock()
+	call, ok := o.calls[domain]
+	if !ok {
+		call = &onDemandCall{done: make(chan struct{})}
+		o.calls[domain] = call
+
+		go func() {
+			call.cert, call.err = p.obtainOnDemand(domain)
+
+			o.callsMu.Lock()
+			delete(o.calls, domain)
+			o.callsMu.Unlock()
+
+			close(call.done)
+		}()
+	}
+	o.callsMu.Unlock()
+
+	timer := time.NewTimer(o.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-call.done:
+		return call.cert, call.err
+	case <-timer.C:
+		return nil, fmt.Errorf("timeout while obtaining a certificate for %q", domain)
+	}
+}
+
+func (p *Provider) obtainOnDemand(domain string) (*tls.Certificate, error) {
+	ctx := log.With(context.Background(), log.Str(log.ProviderName, p.ResolverName+".acme"), log.Str("domain", domain))
+	logger := log.FromContext(ctx)
+
+	if err := p.onDemand.allow(ctx, domain); err != nil {
+		p.onDemand.refused.SetDefault(domain, struct{}{})
+		return nil, err
+	}
+
+	if !p.onDemand.limiter.Allow() {
+		return nil, fmt.Errorf("rate limit reached while obtaining a certificate for %q", domain)
+	}
+
+	logger.Debug("Obtaining a certificate on demand")
+
+	cert, err := p.resolveCertificate(ctx, types.Domain{Main: domain}, traefiktls.DefaultTLSStoreName)
+	if err != nil {
+		p.onDemand.refused.SetDefault(domain, struct{}{})
+		return nil, err
+	}
+
+	if cert == nil {
+		// The certificate is already being obtained, or is provided.
+		return nil, nil
+	}
+
+	certificate, err := tls.X509KeyPair(cert.Certificate, cert.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &certificate, nil
+}
+
+// allow checks whether a certificate can be obtained for the domain.
+func (o *onDemand) allow(ctx context.Context, domain string) error {
+	if o.domainRegex != nil && !o.domainRegex.MatchString(domain) {
+		return fmt.Errorf("domain %q does not match the on-demand domain regex", domain)
+	}
+
+	if o.config.Ask == "" {
+		return nil
+	}
+
+	askURL, err := url.Parse(o.config.Ask)
+	if err != nil {
+		return err
+	}
+
+	query := askURL.Query()
+	query.Set("domain", domain)
+	askURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, askURL.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := o.askClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to ask whether domain %q is allowed: %w", domain, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("domain %q refused by the ask endpoint with status code %d", domain, resp.StatusCode)
+	}
+
+	return nil
+}
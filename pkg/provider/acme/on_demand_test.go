@@ -0,0 +1,219 @@
+package acme
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+)
+
+func TestNewOnDemand_errors(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config *OnDemand
+	}{
+		{
+			desc:   "no ask endpoint nor domain regex",
+			config: &OnDemand{},
+		},
+		{
+			desc:   "invalid domain regex",
+			config: &OnDemand{DomainRegex: "("},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newOnDemand(test.config)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestOnDemand_allow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("domain") != "allowed.com" {
+			rw.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	testCases := []struct {
+		desc          string
+		ask           string
+		domainRegex   string
+		domain        string
+		expectedError bool
+	}{
+		{
+			desc:        "domain matching the regex",
+			domainRegex: `^[a-z]+\.customers\.com$`,
+			domain:      "foo.customers.com",
+		},
+		{
+			desc:          "domain not matching the regex",
+			domainRegex:   `^[a-z]+\.customers\.com$`,
+			domain:        "foo.com",
+			expectedError: true,
+		},
+		{
+			desc:   "domain allowed by the ask endpoint",
+			ask:    server.URL,
+			domain: "allowed.com",
+		},
+		{
+			desc:          "domain refused by the ask endpoint",
+			ask:           server.URL,
+			domain:        "refused.com",
+			expectedError: true,
+		},
+		{
+			desc:          "domain matching the regex, refused by the ask endpoint",
+			ask:           server.URL,
+			domainRegex:   `\.com$`,
+			domain:        "refused.com",
+			expectedError: true,
+		},
+		{
+			desc:          "unreachable ask endpoint",
+			ask:           "http://127.0.0.1:1",
+			domain:        "allowed.com",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := &OnDemand{Ask: test.ask, DomainRegex: test.domainRegex}
+			config.SetDefaults()
+
+			o, err := newOnDemand(config)
+			require.NoError(t, err)
+
+			err = o.allow(context.Background(), test.domain)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProvider_GetCertificate_negativeCache(t *testing.T) {
+	var asked int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&asked, 1)
+		rw.WriteHeader(http.StatusForbidden)
+	}))
+	t.Cleanup(server.Close)
+
+	config := &OnDemand{Ask: server.URL}
+	config.SetDefaults()
+
+	o, err := newOnDemand(config)
+	require.NoError(t, err)
+
+	p := &Provider{onDemand: o}
+
+	for i := 0; i < 3; i++ {
+		cert, err := p.GetCertificate("Refused.com")
+		assert.Error(t, err)
+		assert.Nil(t, cert)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&asked))
+
+	// IP addresses are ignored.
+	cert, err := p.GetCertificate("127.0.0.1")
+	assert.NoError(t, err)
+	assert.Nil(t, cert)
+
+	// Without on-demand configuration, no certificate is obtained.
+	cert, err = (&Provider{}).GetCertificate("foo.com")
+	assert.NoError(t, err)
+	assert.Nil(t, cert)
+}
+
+func TestProvider_GetCertificate_rateLimit(t *testing.T) {
+	config := &OnDemand{DomainRegex: `\.com$`}
+	config.SetDefaults()
+	config.RateLimit = &OnDemandRateLimit{Average: 1, Period: ptypes.Duration(time.Hour), Burst: 1}
+
+	o, err := newOnDemand(config)
+	require.NoError(t, err)
+
+	// Consumes the only token.
+	require.True(t, o.limiter.Allow())
+
+	p := &Provider{onDemand: o}
+
+	cert, err := p.GetCertificate("foo.com")
+	assert.Error(t, err)
+	assert.Nil(t, cert)
+
+	// Rate limited domains are not in the negative cache.
+	_, refused := o.refused.Get("foo.com")
+	assert.False(t, refused)
+}
+
+func TestNewOnDemand(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		timeout         time.Duration
+		burst           int64
+		expectedTimeout time.Duration
+		expectedBurst   int
+	}{
+		{
+			desc:            "Default values",
+			timeout:         defaultOnDemandTimeout,
+			burst:           10,
+			expectedTimeout: defaultOnDemandTimeout,
+			expectedBurst:   10,
+		},
+		{
+			desc:            "Zero values",
+			expectedTimeout: defaultOnDemandTimeout,
+			expectedBurst:   1,
+		},
+		{
+			desc:            "Timeout greater than the maximum",
+			timeout:         time.Minute,
+			burst:           1,
+			expectedTimeout: maxOnDemandTimeout,
+			expectedBurst:   1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := &OnDemand{DomainRegex: `\.com$`}
+			config.SetDefaults()
+			config.Timeout = ptypes.Duration(test.timeout)
+			config.RateLimit.Burst = test.burst
+
+			o, err := newOnDemand(config)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedTimeout, o.timeout)
+			assert.Equal(t, test.expectedBurst, o.limiter.Burst())
+			assert.True(t, o.limiter.Allow())
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/go-acme/lego/v4/certificate"
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/dns01"
@@ -20,6 +21,7 @@ import (
 	"github.com/go-acme/lego/v4/registration"
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/job"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/rules"
 	"github.com/traefik/traefik/v2/pkg/safe"
@@ -41,6 +43,9 @@ type Configuration struct {
 	EAB                  *EAB   `description:"External Account Binding to use." json:"eab,omitempty" toml:"eab,omitempty" yaml:"eab,omitempty"`
 	CertificatesDuration int    `description:"Certificates' duration in hours." json:"certificatesDuration,omitempty" toml:"certificatesDuration,omitempty" yaml:"certificatesDuration,omitempty" export:"true"`
 
+	KVStorage *KVStorage `description:"Store the ACME data in a KV store shared by several Traefik instances, instead of the storage file." json:"kvStorage,omitempty" toml:"kvStorage,omitempty" yaml:"kvStorage,omitempty" export:"true"`
+	OnDemand  *OnDemand  `description:"Obtain certificates during the TLS handshakes, for the domains with no certificate." json:"onDemand,omitempty" toml:"onDemand,omitempty" yaml:"onDemand,omitempty" export:"true"`
+
 	DNSChallenge  *DNSChallenge  `description:"Activate DNS-01 Challenge." json:"dnsChallenge,omitempty" toml:"dnsChallenge,omitempty" yaml:"dnsChallenge,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	HTTPChallenge *HTTPChallenge `description:"Activate HTTP-01 Challenge." json:"httpChallenge,omitempty" toml:"httpChallenge,omitempty" yaml:"httpChallenge,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	TLSChallenge  *TLSChallenge  `description:"Activate TLS-ALPN-01 Challenge." json:"tlsChallenge,omitempty" toml:"tlsChallenge,omitempty" yaml:"tlsChallenge,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
@@ -99,9 +104,10 @@ type Provider struct {
 	HTTPChallengeProvider challenge.Provider
 
 	certificates           []*CertAndStore
+	certificatesMutex      sync.RWMutex
 	account                *Account
 	client                 *lego.Client
-	certsChan              chan *CertAndStore
+	certsChan              chan *certificateUpdate
 	configurationChan      chan<- dynamic.Message
 	tlsManager             *traefiktls.Manager
 	clientMutex            sync.Mutex
@@ -109,8 +115,25 @@ type Provider struct {
 	pool                   *safe.Pool
 	resolvingDomains       map[string]struct{}
 	resolvingDomainsMutex  sync.RWMutex
+	onDemand               *onDemand
+	renewals               map[string]*renewalStatus
+	renewalsMutex          sync.Mutex
+}
+
+// certificateUpdate is a certificate to store, or to remove, with a channel closed once it has been saved.
+type certificateUpdate struct {
+	*CertAndStore
+	remove bool
+	saved  chan struct{}
+	err    error
 }
 
+// Lock names of the resources shared between several Traefik instances.
+const (
+	accountLockName      = "account"
+	certificatesLockName = "certificates"
+)
+
 // SetTLSManager sets the tls manager to use.
 func (p *Provider) SetTLSManager(tlsManager *traefiktls.Manager) {
 	p.tlsManager = tlsManager
@@ -159,6 +182,13 @@ func (p *Provider) Init() error {
 	// Init the currently resolved domain map
 	p.resolvingDomains = make(map[string]struct{})
 
+	if p.OnDemand != nil {
+		p.onDemand, err = newOnDemand(p.OnDemand)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -228,6 +258,17 @@ func (p *Provider) getClient() (*lego.Client, error) {
 		return p.client, nil
 	}
 
+	unlock, err := p.lock(ctx, accountLockName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to lock the ACME account: %w", err)
+	}
+	defer unlock()
+
+	err = p.reloadSharedAccount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get ACME account: %w", err)
+	}
+
 	account, err := p.initAccount(ctx)
 	if err != nil {
 		return nil, err
@@ -325,6 +366,24 @@ func (p *Provider) getClient() (*lego.Client, error) {
 	return p.client, nil
 }
 
+// reloadSharedAccount uses the account registered by another Traefik instance sharing the store, if any.
+func (p *Provider) reloadSharedAccount(ctx context.Context) error {
+	if _, ok := p.Store.(Locker); !ok {
+		return nil
+	}
+
+	account, err := p.Store.GetAccount(p.ResolverName)
+	if err != nil {
+		return err
+	}
+
+	if account != nil && account.Email == p.Email && account.Registration != nil && isAccountMatchingCaServer(ctx, account.Registration.URI, p.CAServer) {
+		p.account = account
+	}
+
+	return nil
+}
+
 func (p *Provider) initAccount(ctx context.Context) (*Account, error) {
 	if p.account == nil || len(p.account.Email) == 0 {
 		var err error
@@ -477,6 +536,24 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 	defer p.removeResolvingDomains(uncheckedDomains)
 
 	logger := log.FromContext(ctx)
+
+	if len(uncheckedDomains) > 1 {
+		domain = types.Domain{Main: uncheckedDomains[0], SANs: uncheckedDomains[1:]}
+	} else {
+		domain = types.Domain{Main: uncheckedDomains[0]}
+	}
+
+	unlock, err := p.lock(ctx, domainsLockName(domain))
+	if err != nil {
+		return nil, fmt.Errorf("unable to lock the domains %v: %w", uncheckedDomains, err)
+	}
+	defer unlock()
+
+	if p.isStoredBySharedStore(ctx, domain, 0) {
+		logger.Debugf("Certificates for domains %+v already obtained by another instance", uncheckedDomains)
+		return nil, nil
+	}
+
 	logger.Debugf("Loading ACME certificates %+v...", uncheckedDomains)
 
 	client, err := p.getClient()
@@ -504,11 +581,6 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 
 	logger.Debugf("Certificates obtained for domains %+v", uncheckedDomains)
 
-	if len(uncheckedDomains) > 1 {
-		domain = types.Domain{Main: uncheckedDomains[0], SANs: uncheckedDomains[1:]}
-	} else {
-		domain = types.Domain{Main: uncheckedDomains[0]}
-	}
 	p.addCertificateForDomain(domain, cert.Certificate, cert.PrivateKey, tlsStore)
 
 	return cert, nil
@@ -523,8 +595,54 @@ func (p *Provider) removeResolvingDomains(resolvingDomains []string) {
 	}
 }
 
+// addCertificateForDomain stores the certificate, and returns once it has been saved.
 func (p *Provider) addCertificateForDomain(domain types.Domain, certificate, key []byte, tlsStore string) {
-	p.certsChan <- &CertAndStore{Certificate: Certificate{Certificate: certificate, Key: key, Domain: domain}, Store: tlsStore}
+	saved := make(chan struct{})
+	p.certsChan <- &certificateUpdate{
+		CertAndStore: &CertAndStore{Certificate: Certificate{Certificate: certificate, Key: key, Domain: domain}, Store: tlsStore},
+		saved:        saved,
+	}
+	<-saved
+}
+
+// lock acquires the named lock when the store is shared between several Traefik instances.
+func (p *Provider) lock(ctx context.Context, name string) (func(), error) {
+	locker, ok := p.Store.(Locker)
+	if !ok {
+		return func() {}, nil
+	}
+
+	return locker.Lock(ctx, p.ResolverName, name)
+}
+
+// isStoredBySharedStore checks whether another Traefik instance sharing the store
+// has already stored a certificate for the domain, valid for more than the given period.
+func (p *Provider) isStoredBySharedStore(ctx context.Context, domain types.Domain, period time.Duration) bool {
+	if _, ok := p.Store.(Locker); !ok {
+		return false
+	}
+
+	certificates, err := p.Store.GetCertificates(p.ResolverName)
+	if err != nil {
+		log.FromContext(ctx).Errorf("Unable to get ACME certificates: %v", err)
+		return false
+	}
+
+	for _, cert := range certificates {
+		if !reflect.DeepEqual(cert.Domain, domain) {
+			continue
+		}
+
+		crt, err := getX509Certificate(ctx, &cert.Certificate)
+		return err == nil && crt != nil && crt.NotAfter.After(time.Now().Add(period))
+	}
+
+	return false
+}
+
+// domainsLockName returns the name of the lock held while obtaining or renewing the certificate of the domain.
+func domainsLockName(domain types.Domain) string {
+	return "domains/" + strings.Join(domain.ToStrArray(), ",")
 }
 
 // getCertificateRenewDurations returns renew durations calculated from the given certificatesDuration in hours.
@@ -603,28 +721,31 @@ func deleteUnnecessaryDomains(ctx context.Context, domains []types.Domain) []typ
 }
 
 func (p *Provider) watchCertificate(ctx context.Context) {
-	p.certsChan = make(chan *CertAndStore)
+	p.certsChan = make(chan *certificateUpdate)
+
+	storedCertsChan := p.watchStoredCertificates(ctx)
 
 	p.pool.GoCtx(func(ctxPool context.Context) {
 		for {
 			select {
 			case cert := <-p.certsChan:
-				certUpdated := false
-				for _, domainsCertificate := range p.certificates {
-					if reflect.DeepEqual(cert.Domain, domainsCertificate.Certificate.Domain) {
-						domainsCertificate.Certificate = cert.Certificate
-						certUpdated = true
-						break
-					}
+				if cert.remove {
+					cert.err = p.removeCertificate(ctx, cert.Domain)
+				} else {
+					cert.err = p.storeCertificate(ctx, cert.CertAndStore)
 				}
-				if !certUpdated {
-					p.certificates = append(p.certificates, cert)
+				if cert.err != nil {
+					log.FromContext(ctx).Error(cert.err)
 				}
-
-				err := p.saveCertificates()
-				if err != nil {
-					log.FromContext(ctx).Error(err)
+				close(cert.saved)
+			case certificates := <-storedCertsChan:
+				if reflect.DeepEqual(certificates, p.getCertificates()) {
+					continue
 				}
+
+				log.FromContext(ctx).Debug("Certificates updated by another instance")
+				p.setCertificates(certificates)
+				p.refreshCertificates()
 			case <-ctxPool.Done():
 				return
 			}
@@ -632,8 +753,147 @@ func (p *Provider) watchCertificate(ctx context.Context) {
 	})
 }
 
+func (p *Provider) storeCertificate(ctx context.Context, cert *CertAndStore) error {
+	unlock, err := p.lock(ctx, certificatesLockName)
+	if err != nil {
+		return fmt.Errorf("unable to lock the ACME certificates: %w", err)
+	}
+	defer unlock()
+
+	if err := p.reloadSharedCertificates(); err != nil {
+		return err
+	}
+
+	// The certificates are copied, as they are read concurrently by the renewals and the resolutions.
+	certificates := p.getCertificates()
+
+	certUpdated := false
+	for i, domainsCertificate := range certificates {
+		if reflect.DeepEqual(cert.Domain, domainsCertificate.Certificate.Domain) {
+			certificates[i] = &CertAndStore{Certificate: cert.Certificate, Store: domainsCertificate.Store}
+			certUpdated = true
+			break
+		}
+	}
+	if !certUpdated {
+		certificates = append(certificates, cert)
+	}
+
+	p.setCertificates(certificates)
+
+	return p.saveCertificates()
+}
+
+func (p *Provider) removeCertificate(ctx context.Context, domain types.Domain) error {
+	unlock, err := p.lock(ctx, certificatesLockName)
+	if err != nil {
+		return fmt.Errorf("unable to lock the ACME certificates: %w", err)
+	}
+	defer unlock()
+
+	if err := p.reloadSharedCertificates(); err != nil {
+		return err
+	}
+
+	var certificates []*CertAndStore
+	for _, cert := range p.getCertificates() {
+		if !reflect.DeepEqual(cert.Domain, domain) {
+			certificates = append(certificates, cert)
+		}
+	}
+	p.setCertificates(certificates)
+
+	return p.saveCertificates()
+}
+
+// reloadSharedCertificates starts from the certificates stored by the other instances sharing the store.
+func (p *Provider) reloadSharedCertificates() error {
+	if _, ok := p.Store.(Locker); !ok {
+		return nil
+	}
+
+	certificates, err := p.Store.GetCertificates(p.ResolverName)
+	if err != nil {
+		return fmt.Errorf("unable to get ACME certificates: %w", err)
+	}
+	p.setCertificates(certificates)
+
+	return nil
+}
+
+// getCertificates returns a copy of the certificates.
+func (p *Provider) getCertificates() []*CertAndStore {
+	p.certificatesMutex.RLock()
+	defer p.certificatesMutex.RUnlock()
+
+	if p.certificates == nil {
+		return nil
+	}
+
+	certificates := make([]*CertAndStore, len(p.certificates))
+	copy(certificates, p.certificates)
+
+	return certificates
+}
+
+func (p *Provider) setCertificates(certificates []*CertAndStore) {
+	p.certificatesMutex.Lock()
+	defer p.certificatesMutex.Unlock()
+
+	p.certificates = certificates
+}
+
+// watchStoredCertificates watches the certificates stored by the other Traefik instances sharing the store.
+// The returned channel is nil when the store is not shared.
+func (p *Provider) watchStoredCertificates(ctx context.Context) <-chan []*CertAndStore {
+	watcher, ok := p.Store.(Watcher)
+	if !ok {
+		return nil
+	}
+
+	storedCertsChan := make(chan []*CertAndStore)
+
+	p.pool.GoCtx(func(ctxPool context.Context) {
+		operation := func() error {
+			certificatesChan, err := watcher.WatchCertificates(ctxPool, p.ResolverName)
+			if err != nil {
+				return fmt.Errorf("failed to watch ACME certificates: %w", err)
+			}
+
+			for {
+				select {
+				case <-ctxPool.Done():
+					return nil
+				case certificates, ok := <-certificatesChan:
+					if !ok {
+						return errors.New("the ACME certificates watch channel is closed")
+					}
+
+					select {
+					case storedCertsChan <- certificates:
+					case <-ctxPool.Done():
+						return nil
+					}
+				}
+			}
+		}
+
+		notify := func(err error, time time.Duration) {
+			log.FromContext(ctx).Errorf("Error while watching ACME certificates: %v, retrying in %s", err, time)
+		}
+
+		err := backoff.RetryNotify(safe.OperationWithRecover(operation),
+			backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxPool), notify)
+		if err != nil {
+			log.FromContext(ctx).Errorf("Cannot watch ACME certificates: %v", err)
+		}
+	})
+
+	return storedCertsChan
+}
+
 func (p *Provider) saveCertificates() error {
-	err := p.Store.SaveCertificates(p.ResolverName, p.certificates)
+	err := p.Store.SaveCertificates(p.ResolverName, p.getCertificates())
 
 	p.refreshCertificates()
 
@@ -653,7 +913,7 @@ func (p *Provider) refreshCertificates() {
 		},
 	}
 
-	for _, cert := range p.certificates {
+	for _, cert := range p.getCertificates() {
 		certConf := &traefiktls.CertAndStores{
 			Certificate: traefiktls.Certificate{
 				CertFile: traefiktls.FileOrContent(cert.Certificate.Certificate),
@@ -671,36 +931,71 @@ func (p *Provider) renewCertificates(ctx context.Context, renewPeriod time.Durat
 	logger := log.FromContext(ctx)
 
 	logger.Info("Testing certificate renew...")
-	for _, cert := range p.certificates {
+	for _, cert := range p.getCertificates() {
 		crt, err := getX509Certificate(ctx, &cert.Certificate)
 		// If there's an error, we assume the cert is broken, and needs update
 		if err != nil || crt == nil || crt.NotAfter.Before(time.Now().Add(renewPeriod)) {
-			client, err := p.getClient()
-			if err != nil {
-				logger.Infof("Error renewing certificate from LE : %+v, %v", cert.Domain, err)
-				continue
+			err := p.renewCertificate(ctx, cert, renewPeriod, false)
+			switch {
+			case errors.Is(err, ErrRenewalInProgress):
+				logger.Debugf("Certificate for domains %v is already being renewed", cert.Domain.ToStrArray())
+			case err != nil:
+				logger.Errorf("Error renewing certificate from LE: %v, %v", cert.Domain, err)
 			}
+		}
+	}
+}
 
-			logger.Infof("Renewing certificate from LE : %+v", cert.Domain)
+// renewCertificate renews the certificate, unless another instance sharing the store
+// has already renewed it and the renewal is not forced.
+func (p *Provider) renewCertificate(ctx context.Context, cert *CertAndStore, renewPeriod time.Duration, force bool) error {
+	if !p.startRenewal(cert.Domain) {
+		return ErrRenewalInProgress
+	}
 
-			renewedCert, err := client.Certificate.Renew(certificate.Resource{
-				Domain:      cert.Domain.Main,
-				PrivateKey:  cert.Key,
-				Certificate: cert.Certificate.Certificate,
-			}, true, oscpMustStaple, p.PreferredChain)
-			if err != nil {
-				logger.Errorf("Error renewing certificate from LE: %v, %v", cert.Domain, err)
-				continue
-			}
+	return p.renewStartedCertificate(ctx, cert, renewPeriod, force)
+}
 
-			if len(renewedCert.Certificate) == 0 || len(renewedCert.PrivateKey) == 0 {
-				logger.Errorf("domains %v renew certificate with no value: %v", cert.Domain.ToStrArray(), cert)
-				continue
-			}
+// renewStartedCertificate renews the certificate whose renewal has been started with startRenewal, and ends the renewal.
+func (p *Provider) renewStartedCertificate(ctx context.Context, cert *CertAndStore, renewPeriod time.Duration, force bool) (err error) {
+	logger := log.FromContext(ctx)
 
-			p.addCertificateForDomain(cert.Domain, renewedCert.Certificate, renewedCert.PrivateKey, cert.Store)
-		}
+	defer func() { p.endRenewal(cert.Domain, err) }()
+
+	unlock, err := p.lock(ctx, domainsLockName(cert.Domain))
+	if err != nil {
+		return fmt.Errorf("unable to lock the domains: %w", err)
+	}
+	defer unlock()
+
+	if !force && p.isStoredBySharedStore(ctx, cert.Domain, renewPeriod) {
+		logger.Debugf("Certificate for domains %v already renewed by another instance", cert.Domain.ToStrArray())
+		return nil
+	}
+
+	client, err := p.getClient()
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Renewing certificate from LE : %+v", cert.Domain)
+
+	renewedCert, err := client.Certificate.Renew(certificate.Resource{
+		Domain:      cert.Domain.Main,
+		PrivateKey:  cert.Key,
+		Certificate: cert.Certificate.Certificate,
+	}, true, oscpMustStaple, p.PreferredChain)
+	if err != nil {
+		return err
 	}
+
+	if len(renewedCert.Certificate) == 0 || len(renewedCert.PrivateKey) == 0 {
+		return errors.New("renewed certificate with no value")
+	}
+
+	p.addCertificateForDomain(cert.Domain, renewedCert.Certificate, renewedCert.PrivateKey, cert.Store)
+
+	return nil
 }
 
 // Get provided certificate which check a domains list (Main and SANs)
@@ -714,7 +1009,7 @@ func (p *Provider) getUncheckedDomains(ctx context.Context, domainsToCheck []str
 	allDomains := p.tlsManager.GetStore(tlsStore).GetAllDomains()
 
 	// Get ACME certificates
-	for _, cert := range p.certificates {
+	for _, cert := range p.getCertificates() {
 		allDomains = append(allDomains, strings.Join(cert.Domain.ToStrArray(), ","))
 	}
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/types"
 )
@@ -643,3 +644,74 @@ func Test_getCertificateRenewDurations(t *testing.T) {
 		})
 	}
 }
+
+func TestStoreCertificate_sharedStore(t *testing.T) {
+	client := newKVClientMock()
+
+	fooCert := &CertAndStore{
+		Certificate: Certificate{Domain: types.Domain{Main: "foo.com"}, Certificate: []byte("foo"), Key: []byte("key")},
+		Store:       "default",
+	}
+	barCert := &CertAndStore{
+		Certificate: Certificate{Domain: types.Domain{Main: "bar.com"}, Certificate: []byte("bar"), Key: []byte("key")},
+		Store:       "default",
+	}
+
+	// Stored by another instance.
+	err := newKVStore(client, "traefik", time.Second).SaveCertificates("test", []*CertAndStore{fooCert})
+	assert.NoError(t, err)
+
+	configurationChan := make(chan dynamic.Message, 1)
+	p := &Provider{
+		ResolverName:      "test",
+		Store:             newKVStore(client, "traefik", time.Second),
+		configurationChan: configurationChan,
+	}
+
+	err = p.storeCertificate(context.Background(), barCert)
+	assert.NoError(t, err)
+
+	expected := []*CertAndStore{fooCert, barCert}
+	assert.Equal(t, expected, p.certificates)
+
+	stored, err := p.Store.GetCertificates("test")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, stored)
+
+	msg := <-configurationChan
+	assert.Len(t, msg.Configuration.TLS.Certificates, 2)
+}
+
+func TestStoreCertificate_concurrentReads(t *testing.T) {
+	configurationChan := make(chan dynamic.Message)
+	p := &Provider{
+		ResolverName:      "test",
+		Store:             newKVStore(newKVClientMock(), "traefik", time.Second),
+		configurationChan: configurationChan,
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 100; i++ {
+			cert := &CertAndStore{
+				Certificate: Certificate{Domain: types.Domain{Main: "foo.com"}, Certificate: []byte{byte(i)}, Key: []byte("key")},
+				Store:       "default",
+			}
+			assert.NoError(t, p.storeCertificate(context.Background(), cert))
+		}
+	}()
+
+	for {
+		select {
+		case <-configurationChan:
+			for _, cert := range p.getCertificates() {
+				assert.Len(t, cert.Certificate.Certificate, 1)
+			}
+		case <-done:
+			assert.Len(t, p.getCertificates(), 1)
+			return
+		}
+	}
+}
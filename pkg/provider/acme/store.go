@@ -1,5 +1,7 @@
 package acme
 
+import "context"
+
 // StoredData represents the data managed by Store.
 type StoredData struct {
 	Account      *Account
@@ -13,3 +15,36 @@ type Store interface {
 	GetCertificates(string) ([]*CertAndStore, error)
 	SaveCertificates(string, []*CertAndStore) error
 }
+
+// Locker is implemented by the stores shared between several Traefik instances,
+// so that only one of them registers the account, or orders and renews a given certificate.
+type Locker interface {
+	// Lock blocks until the named lock of the resolver is acquired, or the context is done.
+	Lock(ctx context.Context, resolverName, name string) (unlock func(), err error)
+}
+
+// Watcher is implemented by the stores shared between several Traefik instances,
+// so that all of them pick up the certificates obtained by any of them.
+type Watcher interface {
+	// WatchCertificates sends the certificates of the resolver each time they change, until the context is done.
+	WatchCertificates(ctx context.Context, resolverName string) (<-chan []*CertAndStore, error)
+}
+
+// ChallengeStore stores the challenges presented to the CA,
+// so that they can be answered by any Traefik instance sharing the store.
+type ChallengeStore interface {
+	GetHTTPChallengeToken(token, domain string) ([]byte, error)
+	SetHTTPChallengeToken(token, domain string, keyAuth []byte) error
+	RemoveHTTPChallengeToken(token, domain string) error
+
+	GetTLSChallenges() ([]*Certificate, error)
+	AddTLSChallenge(keyAuth string, cert *Certificate) error
+	RemoveTLSChallenge(keyAuth string) error
+}
+
+// ChallengeWatcher is implemented by the challenge stores shared between several Traefik instances,
+// so that all of them serve the TLS-ALPN-01 challenges presented by any of them.
+type ChallengeWatcher interface {
+	// WatchTLSChallenges sends the TLS challenges each time they change, until the context is done.
+	WatchTLSChallenges(ctx context.Context) (<-chan []*Certificate, error)
+}
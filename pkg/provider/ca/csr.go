@@ -0,0 +1,88 @@
+package ca
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrCSRSigningDisabled is returned when no domain is allowed in the certificate signing requests.
+	ErrCSRSigningDisabled = errors.New("certificate signing requests are not allowed")
+	// ErrInvalidCSR is returned when a certificate signing request is invalid, or requests a domain not allowed.
+	ErrInvalidCSR = errors.New("invalid certificate signing request")
+)
+
+// SignCSR issues a certificate for the PEM encoded certificate signing request,
+// and returns it in PEM format followed by the chain of the CA.
+// The request must only contain domains allowed by the CSRDomains option.
+func (p *Provider) SignCSR(csrPEM []byte) ([]byte, error) {
+	if len(p.config.CSRDomains) == 0 {
+		return nil, ErrCSRSigningDisabled
+	}
+
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, fmt.Errorf("%w: no PEM encoded certificate request", ErrInvalidCSR)
+	}
+
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSR, err)
+	}
+
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSR, err)
+	}
+
+	if len(csr.IPAddresses) > 0 || len(csr.URIs) > 0 || len(csr.EmailAddresses) > 0 {
+		return nil, fmt.Errorf("%w: only DNS names are allowed", ErrInvalidCSR)
+	}
+
+	domains := csr.DNSNames
+	if len(domains) == 0 && csr.Subject.CommonName != "" {
+		domains = []string{csr.Subject.CommonName}
+	}
+
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("%w: no domain", ErrInvalidCSR)
+	}
+
+	for _, domain := range domains {
+		if !p.isCSRDomainAllowed(domain) {
+			return nil, fmt.Errorf("%w: domain %q not allowed", ErrInvalidCSR, domain)
+		}
+	}
+
+	certPEM, _, err := p.sign(domains, csr.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return certPEM, nil
+}
+
+// isCSRDomainAllowed checks whether the domain matches one of the allowed domains.
+// A wildcard allowed domain (e.g. *.internal) matches all its subdomains, at any depth.
+func (p *Provider) isCSRDomainAllowed(domain string) bool {
+	domain = strings.ToLower(domain)
+
+	for _, allowed := range p.config.CSRDomains {
+		allowed = strings.ToLower(allowed)
+
+		if strings.HasPrefix(allowed, "*.") {
+			if strings.HasSuffix(domain, allowed[1:]) && !strings.HasPrefix(domain, "*.") {
+				return true
+			}
+			continue
+		}
+
+		if domain == allowed {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,115 @@
+package ca
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createCSR(t *testing.T, template *x509.CertificateRequest) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+}
+
+func TestProvider_SignCSR(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		csrDomains  []string
+		csr         *x509.CertificateRequest
+		rawCSR      []byte
+		expected    string
+		expectedErr error
+	}{
+		{
+			desc:       "allowed domain",
+			csrDomains: []string{"svc.internal"},
+			csr:        &x509.CertificateRequest{DNSNames: []string{"svc.internal"}},
+			expected:   "svc.internal",
+		},
+		{
+			desc:       "allowed subdomain",
+			csrDomains: []string{"*.internal"},
+			csr:        &x509.CertificateRequest{DNSNames: []string{"a.svc.internal", "b.internal"}},
+			expected:   "a.svc.internal",
+		},
+		{
+			desc:       "common name without SAN",
+			csrDomains: []string{"*.internal"},
+			csr:        &x509.CertificateRequest{Subject: pkix.Name{CommonName: "svc.internal"}},
+			expected:   "svc.internal",
+		},
+		{
+			desc:        "signing disabled",
+			csr:         &x509.CertificateRequest{DNSNames: []string{"svc.internal"}},
+			expectedErr: ErrCSRSigningDisabled,
+		},
+		{
+			desc:        "domain not allowed",
+			csrDomains:  []string{"*.internal"},
+			csr:         &x509.CertificateRequest{DNSNames: []string{"svc.internal", "example.com"}},
+			expectedErr: ErrInvalidCSR,
+		},
+		{
+			desc:        "wildcard domain not allowed",
+			csrDomains:  []string{"*.internal"},
+			csr:         &x509.CertificateRequest{DNSNames: []string{"*.svc.internal"}},
+			expectedErr: ErrInvalidCSR,
+		},
+		{
+			desc:        "IP address not allowed",
+			csrDomains:  []string{"*.internal"},
+			csr:         &x509.CertificateRequest{DNSNames: []string{"svc.internal"}, IPAddresses: []net.IP{net.ParseIP("10.0.0.1")}},
+			expectedErr: ErrInvalidCSR,
+		},
+		{
+			desc:        "no domain",
+			csrDomains:  []string{"*.internal"},
+			csr:         &x509.CertificateRequest{},
+			expectedErr: ErrInvalidCSR,
+		},
+		{
+			desc:        "not a CSR",
+			csrDomains:  []string{"*.internal"},
+			rawCSR:      []byte("foo"),
+			expectedErr: ErrInvalidCSR,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newTestProvider(t, test.csrDomains...)
+
+			csr := test.rawCSR
+			if test.csr != nil {
+				csr = createCSR(t, test.csr)
+			}
+
+			certPEM, err := p.SignCSR(csr)
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			verify(t, p, certPEM, test.expected)
+		})
+	}
+}
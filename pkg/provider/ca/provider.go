@@ -0,0 +1,416 @@
+package ca
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/rules"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// renewInterval is the interval between two checks of the expiration of the issued certificates.
+const renewInterval = time.Minute
+
+// clockSkew is the duration by which the validity of the issued certificates starts before their issuance.
+const clockSkew = time.Minute
+
+var keyTypes = map[string]certcrypto.KeyType{
+	"EC256":   certcrypto.EC256,
+	"EC384":   certcrypto.EC384,
+	"RSA2048": certcrypto.RSA2048,
+	"RSA4096": certcrypto.RSA4096,
+}
+
+// Configuration holds the configuration of a certificate resolver issuing certificates signed by an internal CA.
+type Configuration struct {
+	CertFile    traefiktls.FileOrContent `description:"Certificate of the CA (root or intermediate), followed by its chain, in PEM format." json:"certFile,omitempty" toml:"certFile,omitempty" yaml:"certFile,omitempty" export:"true"`
+	KeyFile     traefiktls.FileOrContent `description:"Private key of the CA, in PEM format." json:"keyFile,omitempty" toml:"keyFile,omitempty" yaml:"keyFile,omitempty"`
+	Duration    ptypes.Duration          `description:"Validity duration of the issued certificates." json:"duration,omitempty" toml:"duration,omitempty" yaml:"duration,omitempty" export:"true"`
+	RenewBefore ptypes.Duration          `description:"Duration before their expiration at which the issued certificates are renewed." json:"renewBefore,omitempty" toml:"renewBefore,omitempty" yaml:"renewBefore,omitempty" export:"true"`
+	KeyType     string                   `description:"KeyType used for generating certificate private key. Allow value 'EC256', 'EC384', 'RSA2048', 'RSA4096'." json:"keyType,omitempty" toml:"keyType,omitempty" yaml:"keyType,omitempty" export:"true"`
+	CSRDomains  []string                 `description:"Domains allowed in the certificate signing requests signed through the API, wildcards allowed (e.g. *.internal). Signing is disabled when empty." json:"csrDomains,omitempty" toml:"csrDomains,omitempty" yaml:"csrDomains,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *Configuration) SetDefaults() {
+	c.Duration = ptypes.Duration(24 * time.Hour)
+	c.RenewBefore = ptypes.Duration(8 * time.Hour)
+	c.KeyType = "EC256"
+}
+
+// Provider issues certificates signed by an internal CA,
+// for the domains of the routers using it as certificate resolver.
+type Provider struct {
+	ResolverName string
+
+	config *Configuration
+	ca     *x509.Certificate
+	signer crypto.Signer
+	chain  [][]byte
+
+	configurationChan      chan<- dynamic.Message
+	configFromListenerChan chan dynamic.Configuration
+
+	// certificates are the issued certificates by domains, only accessed by the Provide routine.
+	certificates map[string]*issuedCertificate
+}
+
+// issuedCertificate is a certificate issued for the domain of a router.
+type issuedCertificate struct {
+	domain      types.Domain
+	certificate []byte
+	key         []byte
+	notAfter    time.Time
+
+	// renewalSkipped is set once the renewal has been skipped because of the expiration of the CA certificate,
+	// so that it is only logged once.
+	renewalSkipped bool
+}
+
+// NewProvider creates a new CA certificate resolver.
+func NewProvider(resolverName string, config *Configuration) *Provider {
+	return &Provider{
+		ResolverName:           resolverName,
+		config:                 config,
+		configFromListenerChan: make(chan dynamic.Configuration),
+		certificates:           make(map[string]*issuedCertificate),
+	}
+}
+
+// Init loads the CA certificate and key.
+func (p *Provider) Init() error {
+	if p.config.Duration <= 0 {
+		return errors.New("the duration of the certificates must be positive")
+	}
+
+	if p.config.RenewBefore <= 0 || p.config.RenewBefore >= p.config.Duration {
+		return errors.New("the renewal of the certificates must happen before their expiration, and after their issuance")
+	}
+
+	if _, ok := keyTypes[p.config.KeyType]; !ok {
+		return fmt.Errorf("unsupported key type: %s", p.config.KeyType)
+	}
+
+	certPEM, err := p.config.CertFile.Read()
+	if err != nil {
+		return fmt.Errorf("unable to read the CA certificate: %w", err)
+	}
+
+	keyPEM, err := p.config.KeyFile.Read()
+	if err != nil {
+		return fmt.Errorf("unable to read the CA key: %w", err)
+	}
+
+	keyPair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("invalid CA certificate and key: %w", err)
+	}
+
+	p.ca, err = x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("invalid CA certificate: %w", err)
+	}
+
+	if !p.ca.IsCA || (p.ca.KeyUsage != 0 && p.ca.KeyUsage&x509.KeyUsageCertSign == 0) {
+		return errors.New("the CA certificate is not allowed to sign certificates")
+	}
+
+	signer, ok := keyPair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return errors.New("unsupported CA key")
+	}
+
+	p.signer = signer
+	p.chain = keyPair.Certificate
+
+	return nil
+}
+
+// ListenConfiguration sets a new Configuration into the configFromListenerChan.
+func (p *Provider) ListenConfiguration(config dynamic.Configuration) {
+	p.configFromListenerChan <- config
+}
+
+// Provide issues the certificates of the router domains, and renews them before their expiration.
+func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+	ctx := log.With(context.Background(), log.Str(log.ProviderName, p.ResolverName+".ca"))
+
+	p.configurationChan = configurationChan
+
+	pool.GoCtx(func(ctxPool context.Context) {
+		ticker := time.NewTicker(renewInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case config := <-p.configFromListenerChan:
+				if p.updateCertificates(ctx, getDomains(ctx, p.ResolverName, config)) {
+					p.refreshCertificates()
+				}
+			case <-ticker.C:
+				if p.renewCertificates(ctx) {
+					p.refreshCertificates()
+				}
+			case <-ctxPool.Done():
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// updateCertificates issues the certificates of the new domains, and forgets the ones of the domains no longer used.
+// It returns whether the certificates have changed.
+func (p *Provider) updateCertificates(ctx context.Context, domains []types.Domain) bool {
+	updated := false
+
+	current := make(map[string]struct{})
+	for _, domain := range domains {
+		key := strings.Join(domain.ToStrArray(), ",")
+		current[key] = struct{}{}
+
+		if _, ok := p.certificates[key]; ok {
+			continue
+		}
+
+		cert, err := p.issue(domain)
+		if err != nil {
+			log.FromContext(ctx).Errorf("Unable to issue a certificate for domains %q: %v", key, err)
+			continue
+		}
+
+		p.certificates[key] = cert
+		updated = true
+	}
+
+	for key := range p.certificates {
+		if _, ok := current[key]; !ok {
+			delete(p.certificates, key)
+			updated = true
+		}
+	}
+
+	return updated
+}
+
+// renewCertificates renews the certificates expiring in less than the renewal duration.
+// It returns whether certificates have been renewed.
+func (p *Provider) renewCertificates(ctx context.Context) bool {
+	renewed := false
+
+	for key, cert := range p.certificates {
+		if time.Until(cert.notAfter) > time.Duration(p.config.RenewBefore) {
+			continue
+		}
+
+		// The certificates cannot outlive the CA certificate,
+		// so renewing a certificate expiring with it would only issue the same one again, on each check.
+		if !p.ca.NotAfter.After(cert.notAfter) {
+			if !cert.renewalSkipped {
+				log.FromContext(ctx).Warnf("Unable to renew the certificate for domains %q: it expires with the CA certificate, on %s", key, p.ca.NotAfter)
+				cert.renewalSkipped = true
+			}
+			continue
+		}
+
+		newCert, err := p.issue(cert.domain)
+		if err != nil {
+			log.FromContext(ctx).Errorf("Unable to renew the certificate for domains %q: %v", key, err)
+			continue
+		}
+
+		log.FromContext(ctx).Debugf("Certificate renewed for domains %q", key)
+
+		p.certificates[key] = newCert
+		renewed = true
+	}
+
+	return renewed
+}
+
+func (p *Provider) refreshCertificates() {
+	conf := dynamic.Message{
+		ProviderName: p.ResolverName + ".ca",
+		Configuration: &dynamic.Configuration{
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers:     map[string]*dynamic.Router{},
+				Middlewares: map[string]*dynamic.Middleware{},
+				Services:    map[string]*dynamic.Service{},
+			},
+			TLS: &dynamic.TLSConfiguration{},
+		},
+	}
+
+	keys := make([]string, 0, len(p.certificates))
+	for key := range p.certificates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		cert := p.certificates[key]
+		conf.Configuration.TLS.Certificates = append(conf.Configuration.TLS.Certificates, &traefiktls.CertAndStores{
+			Certificate: traefiktls.Certificate{
+				CertFile: traefiktls.FileOrContent(cert.certificate),
+				KeyFile:  traefiktls.FileOrContent(cert.key),
+			},
+			Stores: []string{traefiktls.DefaultTLSStoreName},
+		})
+	}
+
+	p.configurationChan <- conf
+}
+
+// issue generates a key, and issues a certificate for the domain.
+func (p *Provider) issue(domain types.Domain) (*issuedCertificate, error) {
+	privateKey, err := certcrypto.GeneratePrivateKey(keyTypes[p.config.KeyType])
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key")
+	}
+
+	certPEM, notAfter, err := p.sign(domain.ToStrArray(), signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	return &issuedCertificate{
+		domain:      domain,
+		certificate: certPEM,
+		key:         certcrypto.PEMEncode(privateKey),
+		notAfter:    notAfter,
+	}, nil
+}
+
+// sign issues a certificate for the domains and public key,
+// and returns it in PEM format followed by the chain of the CA.
+func (p *Provider) sign(domains []string, publicKey crypto.PublicKey) ([]byte, time.Time, error) {
+	if len(domains) == 0 {
+		return nil, time.Time{}, errors.New("no domain")
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	now := time.Now()
+
+	notAfter := now.Add(time.Duration(p.config.Duration))
+	if notAfter.After(p.ca.NotAfter) {
+		notAfter = p.ca.NotAfter
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: domains[0]},
+		NotBefore:    now.Add(-clockSkew),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	for _, domain := range domains {
+		if ip := net.ParseIP(domain); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, domain)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, p.ca, publicKey, p.signer)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	for _, caDER := range p.chain {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})...)
+	}
+
+	return certPEM, notAfter, nil
+}
+
+// getDomains returns the domains of the routers using the resolver, without duplicates.
+func getDomains(ctx context.Context, resolverName string, config dynamic.Configuration) []types.Domain {
+	var domains []types.Domain
+
+	addDomains := func(tlsDomains []types.Domain, rule string, parse func(string) ([]string, error)) {
+		if len(tlsDomains) > 0 {
+			domains = append(domains, tlsDomains...)
+			return
+		}
+
+		ruleDomains, err := parse(rule)
+		if err != nil {
+			log.FromContext(ctx).Errorf("Error parsing domains in provider CA: %v", err)
+			return
+		}
+
+		for _, domain := range ruleDomains {
+			// HostSNI(`*`) matches all the domains.
+			if domain != "*" {
+				domains = append(domains, types.Domain{Main: domain})
+			}
+		}
+	}
+
+	if config.HTTP != nil {
+		for _, route := range config.HTTP.Routers {
+			if route.TLS != nil && route.TLS.CertResolver == resolverName {
+				addDomains(route.TLS.Domains, route.Rule, rules.ParseDomains)
+			}
+		}
+	}
+
+	if config.TCP != nil {
+		for _, route := range config.TCP.Routers {
+			if route.TLS != nil && route.TLS.CertResolver == resolverName {
+				addDomains(route.TLS.Domains, route.Rule, rules.ParseHostSNI)
+			}
+		}
+	}
+
+	var uniqDomains []types.Domain
+	for _, domain := range domains {
+		if len(domain.ToStrArray()) == 0 || containsDomain(uniqDomains, domain) {
+			continue
+		}
+		uniqDomains = append(uniqDomains, domain)
+	}
+
+	return uniqDomains
+}
+
+func containsDomain(domains []types.Domain, domain types.Domain) bool {
+	for _, d := range domains {
+		if reflect.DeepEqual(d, domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,291 @@
+package ca
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// generateCA generates a CA certificate and key, in PEM format.
+func generateCA(t *testing.T, isCA bool) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Internal CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+func newTestProvider(t *testing.T, csrDomains ...string) *Provider {
+	t.Helper()
+
+	certPEM, keyPEM := generateCA(t, true)
+
+	config := &Configuration{
+		CertFile:   traefiktls.FileOrContent(certPEM),
+		KeyFile:    traefiktls.FileOrContent(keyPEM),
+		CSRDomains: csrDomains,
+	}
+	config.SetDefaults()
+
+	p := NewProvider("internal", config)
+	require.NoError(t, p.Init())
+
+	return p
+}
+
+// verify checks that the PEM certificate chain is valid for the domain, and signed by the CA of the provider.
+func verify(t *testing.T, p *Provider, certPEM []byte, domain string) *x509.Certificate {
+	t.Helper()
+
+	block, rest := pem.Decode(certPEM)
+	require.NotNil(t, block)
+
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(rest))
+
+	_, err = leaf.Verify(x509.VerifyOptions{
+		DNSName:   domain,
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, p.ca.Subject, leaf.Issuer)
+
+	return leaf
+}
+
+func TestProvider_Init(t *testing.T) {
+	certPEM, keyPEM := generateCA(t, true)
+	notCACertPEM, notCAKeyPEM := generateCA(t, false)
+	_, otherKeyPEM := generateCA(t, true)
+
+	testCases := []struct {
+		desc        string
+		config      Configuration
+		expectedErr bool
+	}{
+		{
+			desc: "valid CA",
+			config: Configuration{
+				CertFile:    traefiktls.FileOrContent(certPEM),
+				KeyFile:     traefiktls.FileOrContent(keyPEM),
+				Duration:    ptypes.Duration(time.Hour),
+				RenewBefore: ptypes.Duration(time.Minute),
+				KeyType:     "RSA2048",
+			},
+		},
+		{
+			desc: "not a CA certificate",
+			config: Configuration{
+				CertFile:    traefiktls.FileOrContent(notCACertPEM),
+				KeyFile:     traefiktls.FileOrContent(notCAKeyPEM),
+				Duration:    ptypes.Duration(time.Hour),
+				RenewBefore: ptypes.Duration(time.Minute),
+				KeyType:     "EC256",
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "key not matching the certificate",
+			config: Configuration{
+				CertFile:    traefiktls.FileOrContent(certPEM),
+				KeyFile:     traefiktls.FileOrContent(otherKeyPEM),
+				Duration:    ptypes.Duration(time.Hour),
+				RenewBefore: ptypes.Duration(time.Minute),
+				KeyType:     "EC256",
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "renewal after expiration",
+			config: Configuration{
+				CertFile:    traefiktls.FileOrContent(certPEM),
+				KeyFile:     traefiktls.FileOrContent(keyPEM),
+				Duration:    ptypes.Duration(time.Hour),
+				RenewBefore: ptypes.Duration(2 * time.Hour),
+				KeyType:     "EC256",
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "unsupported key type",
+			config: Configuration{
+				CertFile:    traefiktls.FileOrContent(certPEM),
+				KeyFile:     traefiktls.FileOrContent(keyPEM),
+				Duration:    ptypes.Duration(time.Hour),
+				RenewBefore: ptypes.Duration(time.Minute),
+				KeyType:     "DSA",
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := NewProvider("internal", &test.config).Init()
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProvider_updateCertificates(t *testing.T) {
+	p := newTestProvider(t)
+
+	config := dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"foo": {
+					Rule: "Host(`foo.internal`)",
+					TLS:  &dynamic.RouterTLSConfig{CertResolver: "internal"},
+				},
+				"bar": {
+					Rule: "Host(`bar.internal`)",
+					TLS: &dynamic.RouterTLSConfig{
+						CertResolver: "internal",
+						Domains:      []types.Domain{{Main: "bar.internal", SANs: []string{"10.0.0.1"}}},
+					},
+				},
+				"other": {
+					Rule: "Host(`other.internal`)",
+					TLS:  &dynamic.RouterTLSConfig{CertResolver: "acme"},
+				},
+			},
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Routers: map[string]*dynamic.TCPRouter{
+				"db": {
+					Rule: "HostSNI(`db.internal`, `*`)",
+					TLS:  &dynamic.RouterTCPTLSConfig{CertResolver: "internal"},
+				},
+			},
+		},
+	}
+
+	ctx := context.Background()
+
+	assert.True(t, p.updateCertificates(ctx, getDomains(ctx, "internal", config)))
+	require.Len(t, p.certificates, 3)
+
+	verify(t, p, p.certificates["foo.internal"].certificate, "foo.internal")
+	verify(t, p, p.certificates["db.internal"].certificate, "db.internal")
+
+	leaf := verify(t, p, p.certificates["bar.internal,10.0.0.1"].certificate, "10.0.0.1")
+	assert.Equal(t, []string{"bar.internal"}, leaf.DNSNames)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), leaf.NotAfter, time.Minute)
+
+	_, err := tls.X509KeyPair(p.certificates["foo.internal"].certificate, p.certificates["foo.internal"].key)
+	require.NoError(t, err)
+
+	// Nothing changes when the configuration is the same.
+	assert.False(t, p.updateCertificates(ctx, getDomains(ctx, "internal", config)))
+
+	// The certificates of the domains no longer used are forgotten.
+	delete(config.HTTP.Routers, "foo")
+	assert.True(t, p.updateCertificates(ctx, getDomains(ctx, "internal", config)))
+	assert.Len(t, p.certificates, 2)
+	assert.NotContains(t, p.certificates, "foo.internal")
+}
+
+func TestProvider_renewCertificates(t *testing.T) {
+	p := newTestProvider(t)
+
+	ctx := context.Background()
+
+	require.True(t, p.updateCertificates(ctx, []types.Domain{{Main: "foo.internal"}, {Main: "bar.internal"}}))
+
+	assert.False(t, p.renewCertificates(ctx))
+
+	expiring := p.certificates["foo.internal"]
+	expiring.notAfter = time.Now().Add(time.Hour)
+
+	valid := p.certificates["bar.internal"]
+
+	assert.True(t, p.renewCertificates(ctx))
+
+	assert.NotEqual(t, expiring, p.certificates["foo.internal"])
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), p.certificates["foo.internal"].notAfter, time.Minute)
+	assert.Equal(t, valid, p.certificates["bar.internal"])
+
+	verify(t, p, p.certificates["foo.internal"].certificate, "foo.internal")
+}
+
+func TestProvider_renewCertificates_caExpiration(t *testing.T) {
+	p := newTestProvider(t)
+
+	ctx := context.Background()
+
+	require.True(t, p.updateCertificates(ctx, []types.Domain{{Main: "foo.internal"}}))
+
+	// The CA expires before the renewal, hence the certificate expires with it.
+	p.ca.NotAfter = time.Now().Add(time.Hour)
+	expiring := p.certificates["foo.internal"]
+	expiring.notAfter = p.ca.NotAfter
+
+	assert.False(t, p.renewCertificates(ctx))
+	assert.Same(t, expiring, p.certificates["foo.internal"])
+	assert.True(t, expiring.renewalSkipped)
+
+	assert.False(t, p.renewCertificates(ctx))
+	assert.Same(t, expiring, p.certificates["foo.internal"])
+}
+
+func TestProvider_refreshCertificates(t *testing.T) {
+	p := newTestProvider(t)
+
+	configurationChan := make(chan dynamic.Message, 1)
+	p.configurationChan = configurationChan
+
+	require.True(t, p.updateCertificates(context.Background(), []types.Domain{{Main: "foo.internal"}, {Main: "bar.internal"}}))
+
+	p.refreshCertificates()
+
+	msg := <-configurationChan
+	assert.Equal(t, "internal.ca", msg.ProviderName)
+	require.Len(t, msg.Configuration.TLS.Certificates, 2)
+	assert.Equal(t, traefiktls.FileOrContent(p.certificates["bar.internal"].certificate), msg.Configuration.TLS.Certificates[0].CertFile)
+	assert.Equal(t, []string{traefiktls.DefaultTLSStoreName}, msg.Configuration.TLS.Certificates[0].Stores)
+}
@@ -30,6 +30,12 @@ type Provider struct {
 	Watch                     bool   `description:"Watch provider." json:"watch,omitempty" toml:"watch,omitempty" yaml:"watch,omitempty" export:"true"`
 	Filename                  string `description:"Load dynamic configuration from a file." json:"filename,omitempty" toml:"filename,omitempty" yaml:"filename,omitempty" export:"true"`
 	DebugLogGeneratedTemplate bool   `description:"Enable debug logging of generated configuration template." json:"debugLogGeneratedTemplate,omitempty" toml:"debugLogGeneratedTemplate,omitempty" yaml:"debugLogGeneratedTemplate,omitempty" export:"true"`
+	CertificatesDirectory     string `description:"Load the TLS certificates of the default store from the PEM certificate and key pairs of a directory." json:"certificatesDirectory,omitempty" toml:"certificatesDirectory,omitempty" yaml:"certificatesDirectory,omitempty" export:"true"`
+
+	// certificateFiles holds the files referenced by path in the configuration (certificates, keys and CAs),
+	// which are watched along with the configuration.
+	certificateFiles   map[string]struct{}
+	watchedDirectories map[string]struct{}
 }
 
 // SetDefaults sets the default values.
@@ -77,15 +83,36 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 func (p *Provider) BuildConfiguration() (*dynamic.Configuration, error) {
 	ctx := log.With(context.Background(), log.Str(log.ProviderName, providerName))
 
-	if len(p.Directory) > 0 {
-		return p.loadFileConfigFromDirectory(ctx, p.Directory, nil)
+	p.certificateFiles = make(map[string]struct{})
+
+	var configuration *dynamic.Configuration
+	var err error
+
+	switch {
+	case len(p.Directory) > 0:
+		configuration, err = p.loadFileConfigFromDirectory(ctx, p.Directory, nil)
+	case len(p.Filename) > 0:
+		configuration, err = p.loadFileConfig(ctx, p.Filename, true)
+	default:
+		return nil, errors.New("error using file configuration provider, neither filename or directory defined")
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	if len(p.Filename) > 0 {
-		return p.loadFileConfig(ctx, p.Filename, true)
+	if len(p.CertificatesDirectory) > 0 {
+		certificates, err := loadCertificatesFromDirectory(ctx, p.CertificatesDirectory)
+		if err != nil {
+			return nil, err
+		}
+
+		if configuration.TLS == nil {
+			configuration.TLS = &dynamic.TLSConfiguration{}
+		}
+		configuration.TLS.Certificates = append(configuration.TLS.Certificates, certificates...)
 	}
 
-	return nil, errors.New("error using file configuration provider, neither filename or directory defined")
+	return configuration, nil
 }
 
 func (p *Provider) addWatcher(pool *safe.Pool, directory string, configurationChan chan<- dynamic.Message, callback func(chan<- dynamic.Message, fsnotify.Event)) error {
@@ -99,6 +126,9 @@ func (p *Provider) addWatcher(pool *safe.Pool, directory string, configurationCh
 		return fmt.Errorf("error adding file watcher: %w", err)
 	}
 
+	p.watchedDirectories = map[string]struct{}{filepath.Clean(directory): {}}
+	p.watchCertificateFiles(watcher)
+
 	// Process events
 	pool.GoCtx(func(ctx context.Context) {
 		defer watcher.Close()
@@ -110,11 +140,13 @@ func (p *Provider) addWatcher(pool *safe.Pool, directory string, configurationCh
 				if p.Directory == "" {
 					_, evtFileName := filepath.Split(evt.Name)
 					_, confFileName := filepath.Split(p.Filename)
-					if evtFileName == confFileName {
+					if evtFileName == confFileName || p.isCertificateEvent(evt) {
 						callback(configurationChan, evt)
+						p.watchCertificateFiles(watcher)
 					}
 				} else {
 					callback(configurationChan, evt)
+					p.watchCertificateFiles(watcher)
 				}
 			case err := <-watcher.Errors:
 				log.WithoutContext().WithField(log.ProviderName, providerName).Errorf("Watcher event error: %s", err)
@@ -124,6 +156,52 @@ func (p *Provider) addWatcher(pool *safe.Pool, directory string, configurationCh
 	return nil
 }
 
+// watchCertificateFiles watches the directories of the certificate files referenced by the configuration,
+// and the certificates directory.
+// The directories are watched rather than the files, to be notified when the files are replaced (e.g. atomic writes, or Kubernetes secret volumes).
+func (p *Provider) watchCertificateFiles(watcher *fsnotify.Watcher) {
+	logger := log.WithoutContext().WithField(log.ProviderName, providerName)
+
+	directories := make(map[string]struct{})
+	for filename := range p.certificateFiles {
+		directories[filepath.Dir(filename)] = struct{}{}
+	}
+
+	if len(p.CertificatesDirectory) > 0 {
+		directories[filepath.Clean(p.CertificatesDirectory)] = struct{}{}
+	}
+
+	for directory := range directories {
+		if _, ok := p.watchedDirectories[directory]; ok {
+			continue
+		}
+
+		if err := watcher.Add(directory); err != nil {
+			logger.Errorf("Unable to watch the certificates directory %s: %v", directory, err)
+			continue
+		}
+
+		p.watchedDirectories[directory] = struct{}{}
+	}
+}
+
+// isCertificateEvent checks whether the event is about a certificate file referenced by the configuration,
+// or a file of the certificates directory.
+func (p *Provider) isCertificateEvent(event fsnotify.Event) bool {
+	if _, ok := p.certificateFiles[filepath.Clean(event.Name)]; ok {
+		return true
+	}
+
+	if len(p.CertificatesDirectory) > 0 && filepath.Dir(event.Name) == filepath.Clean(p.CertificatesDirectory) {
+		return true
+	}
+
+	// Kubernetes secret volumes replace the files by swapping the ..data symbolic link.
+	_, filename := filepath.Split(event.Name)
+	_, ok := p.watchedDirectories[filepath.Dir(event.Name)]
+	return ok && strings.HasPrefix(filename, "..")
+}
+
 func (p *Provider) watcherCallback(configurationChan chan<- dynamic.Message, event fsnotify.Event) {
 	watchItem := p.Filename
 	if len(p.Directory) > 0 {
@@ -166,7 +244,7 @@ func (p *Provider) loadFileConfig(ctx context.Context, filename string, parseTem
 	}
 
 	if configuration.TLS != nil {
-		configuration.TLS.Certificates = flattenCertificates(ctx, configuration.TLS)
+		configuration.TLS.Certificates = p.flattenCertificates(ctx, configuration.TLS)
 
 		// TLS Options
 		if configuration.TLS.Options != nil {
@@ -174,7 +252,7 @@ func (p *Provider) loadFileConfig(ctx context.Context, filename string, parseTem
 				var caCerts []tls.FileOrContent
 
 				for _, caFile := range options.ClientAuth.CAFiles {
-					content, err := caFile.Read()
+					content, err := p.readFileOrContent(caFile)
 					if err != nil {
 						log.FromContext(ctx).Error(err)
 						continue
@@ -195,14 +273,14 @@ func (p *Provider) loadFileConfig(ctx context.Context, filename string, parseTem
 					continue
 				}
 
-				content, err := store.DefaultCertificate.CertFile.Read()
+				content, err := p.readFileOrContent(store.DefaultCertificate.CertFile)
 				if err != nil {
 					log.FromContext(ctx).Error(err)
 					continue
 				}
 				store.DefaultCertificate.CertFile = tls.FileOrContent(content)
 
-				content, err = store.DefaultCertificate.KeyFile.Read()
+				content, err = p.readFileOrContent(store.DefaultCertificate.KeyFile)
 				if err != nil {
 					log.FromContext(ctx).Error(err)
 					continue
@@ -219,14 +297,14 @@ func (p *Provider) loadFileConfig(ctx context.Context, filename string, parseTem
 		for name, st := range configuration.HTTP.ServersTransports {
 			var certificates []tls.Certificate
 			for _, cert := range st.Certificates {
-				content, err := cert.CertFile.Read()
+				content, err := p.readFileOrContent(cert.CertFile)
 				if err != nil {
 					log.FromContext(ctx).Error(err)
 					continue
 				}
 				cert.CertFile = tls.FileOrContent(content)
 
-				content, err = cert.KeyFile.Read()
+				content, err = p.readFileOrContent(cert.KeyFile)
 				if err != nil {
 					log.FromContext(ctx).Error(err)
 					continue
@@ -240,7 +318,7 @@ func (p *Provider) loadFileConfig(ctx context.Context, filename string, parseTem
 
 			var rootCAs []tls.FileOrContent
 			for _, rootCA := range st.RootCAs {
-				content, err := rootCA.Read()
+				content, err := p.readFileOrContent(rootCA)
 				if err != nil {
 					log.FromContext(ctx).Error(err)
 					continue
@@ -256,17 +334,17 @@ func (p *Provider) loadFileConfig(ctx context.Context, filename string, parseTem
 	return configuration, nil
 }
 
-func flattenCertificates(ctx context.Context, tlsConfig *dynamic.TLSConfiguration) []*tls.CertAndStores {
+func (p *Provider) flattenCertificates(ctx context.Context, tlsConfig *dynamic.TLSConfiguration) []*tls.CertAndStores {
 	var certs []*tls.CertAndStores
 	for _, cert := range tlsConfig.Certificates {
-		content, err := cert.Certificate.CertFile.Read()
+		content, err := p.readFileOrContent(cert.Certificate.CertFile)
 		if err != nil {
 			log.FromContext(ctx).Error(err)
 			continue
 		}
 		cert.Certificate.CertFile = tls.FileOrContent(string(content))
 
-		content, err = cert.Certificate.KeyFile.Read()
+		content, err = p.readFileOrContent(cert.Certificate.KeyFile)
 		if err != nil {
 			log.FromContext(ctx).Error(err)
 			continue
@@ -279,6 +357,74 @@ func flattenCertificates(ctx context.Context, tlsConfig *dynamic.TLSConfiguratio
 	return certs
 }
 
+// readFileOrContent reads the file or content, and records the file to watch it.
+func (p *Provider) readFileOrContent(fileOrContent tls.FileOrContent) ([]byte, error) {
+	if fileOrContent.IsPath() && p.certificateFiles != nil {
+		p.certificateFiles[filepath.Clean(fileOrContent.String())] = struct{}{}
+	}
+
+	return fileOrContent.Read()
+}
+
+// loadCertificatesFromDirectory loads the certificates of the PEM files of the directory.
+// A certificate file (.crt, .cert or .pem) is paired with the key file of the same name and the .key extension,
+// or holds its key when there is no key file.
+func loadCertificatesFromDirectory(ctx context.Context, directory string) ([]*tls.CertAndStores, error) {
+	fileList, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read certificates directory %s: %w", directory, err)
+	}
+
+	var certificates []*tls.CertAndStores
+	for _, item := range fileList {
+		// Skips the hidden files, and the data directories of the Kubernetes secret volumes.
+		if item.IsDir() || strings.HasPrefix(item.Name(), ".") {
+			continue
+		}
+
+		extension := strings.ToLower(filepath.Ext(item.Name()))
+		switch extension {
+		case ".crt", ".cert", ".pem":
+			// noop
+		default:
+			continue
+		}
+
+		logger := log.FromContext(log.With(ctx, log.Str("filename", item.Name())))
+
+		certFile := filepath.Join(directory, item.Name())
+		certContent, err := os.ReadFile(certFile)
+		if err != nil {
+			logger.Errorf("Unable to read certificate: %v", err)
+			continue
+		}
+
+		keyContent, err := os.ReadFile(strings.TrimSuffix(certFile, filepath.Ext(certFile)) + ".key")
+		switch {
+		case err == nil:
+		case errors.Is(err, os.ErrNotExist) && bytes.Contains(certContent, []byte("PRIVATE KEY-----")):
+			keyContent = certContent
+		case errors.Is(err, os.ErrNotExist):
+			if extension != ".pem" {
+				logger.Warn("No key found for the certificate, skipping")
+			}
+			continue
+		default:
+			logger.Errorf("Unable to read certificate key: %v", err)
+			continue
+		}
+
+		certificates = append(certificates, &tls.CertAndStores{
+			Certificate: tls.Certificate{
+				CertFile: tls.FileOrContent(certContent),
+				KeyFile:  tls.FileOrContent(keyContent),
+			},
+		})
+	}
+
+	return certificates, nil
+}
+
 func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory string, configuration *dynamic.Configuration) (*dynamic.Configuration, error) {
 	fileList, err := os.ReadDir(directory)
 	if err != nil {
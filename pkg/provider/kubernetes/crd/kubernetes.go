@@ -263,6 +263,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			CircuitBreaker:    middleware.Spec.CircuitBreaker,
 			Compress:          middleware.Spec.Compress,
 			PassTLSClientCert: middleware.Spec.PassTLSClientCert,
+			ClientCertAuth:    middleware.Spec.ClientCertAuth,
 			Retry:             retry,
 			ContentType:       middleware.Spec.ContentType,
 			Plugin:            plugin,
@@ -273,8 +274,9 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 		id := provider.Normalize(makeID(middlewareTCP.Namespace, middlewareTCP.Name))
 
 		conf.TCP.Middlewares[id] = &dynamic.TCPMiddleware{
-			IPWhiteList: middlewareTCP.Spec.IPWhiteList,
-			RateLimit:   middlewareTCP.Spec.RateLimit,
+			IPWhiteList:    middlewareTCP.Spec.IPWhiteList,
+			RateLimit:      middlewareTCP.Spec.RateLimit,
+			ClientCertAuth: middlewareTCP.Spec.ClientCertAuth,
 		}
 	}
 
@@ -367,6 +369,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			MaxIdleConnsPerHost: serversTransport.Spec.MaxIdleConnsPerHost,
 			ForwardingTimeouts:  forwardingTimeout,
 			PeerCertURI:         serversTransport.Spec.PeerCertURI,
+			Spiffe:              serversTransport.Spec.Spiffe,
 		}
 	}
 
@@ -43,6 +43,7 @@ type MiddlewareSpec struct {
 	CircuitBreaker    *dynamic.CircuitBreaker        `json:"circuitBreaker,omitempty"`
 	Compress          *dynamic.Compress              `json:"compress,omitempty"`
 	PassTLSClientCert *dynamic.PassTLSClientCert     `json:"passTLSClientCert,omitempty"`
+	ClientCertAuth    *dynamic.ClientCertAuth        `json:"clientCertAuth,omitempty"`
 	Retry             *Retry                         `json:"retry,omitempty"`
 	ContentType       *dynamic.ContentType           `json:"contentType,omitempty"`
 	Plugin            map[string]apiextensionv1.JSON `json:"plugin,omitempty"`
@@ -20,8 +20,9 @@ type MiddlewareTCP struct {
 
 // MiddlewareTCPSpec holds the MiddlewareTCP configuration.
 type MiddlewareTCPSpec struct {
-	IPWhiteList *dynamic.TCPIPWhiteList `json:"ipWhiteList,omitempty"`
-	RateLimit   *dynamic.TCPRateLimit   `json:"rateLimit,omitempty"`
+	IPWhiteList    *dynamic.TCPIPWhiteList    `json:"ipWhiteList,omitempty"`
+	RateLimit      *dynamic.TCPRateLimit      `json:"rateLimit,omitempty"`
+	ClientCertAuth *dynamic.TCPClientCertAuth `json:"clientCertAuth,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -727,6 +727,11 @@ func (in *MiddlewareSpec) DeepCopyInto(out *MiddlewareSpec) {
 		*out = new(dynamic.PassTLSClientCert)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ClientCertAuth != nil {
+		in, out := &in.ClientCertAuth, &out.ClientCertAuth
+		*out = new(dynamic.ClientCertAuth)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Retry != nil {
 		in, out := &in.Retry, &out.Retry
 		*out = new(Retry)
@@ -830,6 +835,11 @@ func (in *MiddlewareTCPSpec) DeepCopyInto(out *MiddlewareTCPSpec) {
 		*out = new(dynamic.TCPRateLimit)
 		**out = **in
 	}
+	if in.ClientCertAuth != nil {
+		in, out := &in.ClientCertAuth, &out.ClientCertAuth
+		*out = new(dynamic.TCPClientCertAuth)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1110,6 +1120,11 @@ func (in *ServersTransportSpec) DeepCopyInto(out *ServersTransportSpec) {
 		*out = new(ForwardingTimeouts)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Spiffe != nil {
+		in, out := &in.Spiffe, &out.Spiffe
+		*out = new(dynamic.Spiffe)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1183,7 +1198,7 @@ func (in *ServiceTCP) DeepCopyInto(out *ServiceTCP) {
 	if in.ProxyProtocol != nil {
 		in, out := &in.ProxyProtocol, &out.ProxyProtocol
 		*out = new(dynamic.ProxyProtocol)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -159,22 +159,32 @@ func (p *Provider) buildConfiguration() (*dynamic.Configuration, error) {
 }
 
 func (p *Provider) createKVClient(ctx context.Context) (store.Store, error) {
+	kvStore, err := CreateKVClient(ctx, p.storeType, p.Endpoints, p.Username, p.Password, p.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storeWrapper{Store: kvStore}, nil
+}
+
+// CreateKVClient creates a client for the given KV store backend.
+func CreateKVClient(ctx context.Context, storeType store.Backend, endpoints []string, username, password string, clientTLS *types.ClientTLS) (store.Store, error) {
 	storeConfig := &store.Config{
 		ConnectionTimeout: 3 * time.Second,
 		Bucket:            "traefik",
-		Username:          p.Username,
-		Password:          p.Password,
+		Username:          username,
+		Password:          password,
 	}
 
-	if p.TLS != nil {
+	if clientTLS != nil {
 		var err error
-		storeConfig.TLS, err = p.TLS.CreateTLSConfig(ctx)
+		storeConfig.TLS, err = clientTLS.CreateTLSConfig(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("unable to create client TLS configuration: %w", err)
 		}
 	}
 
-	switch p.storeType {
+	switch storeType {
 	case store.CONSUL:
 		consul.Register()
 	case store.ETCDV3:
@@ -185,10 +195,5 @@ func (p *Provider) createKVClient(ctx context.Context) (store.Store, error) {
 		redis.Register()
 	}
 
-	kvStore, err := valkeyrie.NewStore(p.storeType, p.Endpoints, storeConfig)
-	if err != nil {
-		return nil, err
-	}
-
-	return &storeWrapper{Store: kvStore}, nil
+	return valkeyrie.NewStore(storeType, endpoints, storeConfig)
 }
@@ -0,0 +1,178 @@
+package proxyprotocol
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/pires/go-proxyproto/tlvparse"
+)
+
+// Keys of the values derived from the TLVs of the cloud providers, and of the SSL TLV.
+const (
+	KeySSLVersion  = "ssl_version"
+	KeySSLClientCN = "ssl_cn"
+	KeyAWSVPCEID   = "aws_vpce_id"
+	KeyAzureLinkID = "azure_link_id"
+	KeyGCPPSCID    = "gcp_psc_id"
+)
+
+// typeNames are the names of the registered TLV types.
+var typeNames = map[proxyproto.PP2Type]string{
+	proxyproto.PP2_TYPE_ALPN:      "alpn",
+	proxyproto.PP2_TYPE_AUTHORITY: "authority",
+	proxyproto.PP2_TYPE_UNIQUE_ID: "unique_id",
+	proxyproto.PP2_TYPE_NETNS:     "netns",
+}
+
+// ParseType returns the TLV type of the given name (alpn, authority, unique_id or netns),
+// or number, in decimal or hexadecimal notation (e.g. 0xE0).
+func ParseType(name string) (proxyproto.PP2Type, error) {
+	for typ, typeName := range typeNames {
+		if strings.EqualFold(name, typeName) {
+			return typ, nil
+		}
+	}
+
+	typ, err := strconv.ParseUint(name, 0, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid TLV type %q", name)
+	}
+
+	return proxyproto.PP2Type(typ), nil
+}
+
+// ValidateKey returns an error if the given key is neither a TLV type nor the key of a value derived from the TLVs.
+func ValidateKey(key string) error {
+	switch strings.ToLower(key) {
+	case KeySSLVersion, KeySSLClientCN, KeyAWSVPCEID, KeyAzureLinkID, KeyGCPPSCID:
+		return nil
+	}
+
+	_, err := ParseType(key)
+	return err
+}
+
+// typeKey returns the key of the values of the given TLV type.
+func typeKey(typ proxyproto.PP2Type) string {
+	if name, ok := typeNames[typ]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%02X", byte(typ))
+}
+
+// Lookup returns the value of the TLV of the given key.
+// The key is either the name or the number of a TLV type, or the key of a value derived from the TLVs
+// (ssl_version, ssl_cn, aws_vpce_id, azure_link_id, or gcp_psc_id).
+func Lookup(tlvs []proxyproto.TLV, key string) (string, bool) {
+	if value, ok := derivedValues(tlvs)[strings.ToLower(key)]; ok {
+		return value, true
+	}
+
+	typ, err := ParseType(key)
+	if err != nil {
+		return "", false
+	}
+
+	for _, tlv := range tlvs {
+		if tlv.Type == typ {
+			return encodeValue(tlv.Value), true
+		}
+	}
+
+	return "", false
+}
+
+// Values returns the values of the given TLVs, by key.
+func Values(tlvs []proxyproto.TLV) map[string]string {
+	values := derivedValues(tlvs)
+
+	for _, tlv := range tlvs {
+		key := typeKey(tlv.Type)
+		if _, exists := values[key]; !exists {
+			values[key] = encodeValue(tlv.Value)
+		}
+	}
+
+	return values
+}
+
+func derivedValues(tlvs []proxyproto.TLV) map[string]string {
+	values := make(map[string]string)
+
+	if ssl, ok := tlvparse.FindSSL(tlvs); ok {
+		if version, ok := ssl.SSLVersion(); ok {
+			values[KeySSLVersion] = version
+		}
+		if cn, ok := ssl.ClientCN(); ok {
+			values[KeySSLClientCN] = cn
+		}
+	}
+
+	if vpceID := tlvparse.FindAWSVPCEndpointID(tlvs); vpceID != "" {
+		values[KeyAWSVPCEID] = vpceID
+	}
+
+	if linkID, ok := tlvparse.FindAzurePrivateEndpointLinkID(tlvs); ok {
+		values[KeyAzureLinkID] = strconv.FormatUint(uint64(linkID), 10)
+	}
+
+	if pscID, ok := tlvparse.ExtractPSCConnectionID(tlvs); ok {
+		values[KeyGCPPSCID] = strconv.FormatUint(pscID, 10)
+	}
+
+	return values
+}
+
+// encodeValue returns the given value as is if it is printable ASCII, and hexadecimal encoded otherwise.
+func encodeValue(value []byte) string {
+	for _, b := range value {
+		if b < 0x20 || b > 0x7E {
+			return hex.EncodeToString(value)
+		}
+	}
+	return string(value)
+}
+
+// Addr is the address of a client connected through the Proxy Protocol,
+// carrying the TLVs of its Proxy Protocol v2 header.
+// The TLVs are attached to the address of the client because it is the only connection information
+// kept by the connections wrapping the client connection, such as the TLS connections.
+type Addr struct {
+	net.Addr
+
+	TLVs []proxyproto.TLV
+}
+
+// GetTLVs returns the TLVs carried by the given client address, if any.
+func GetTLVs(addr net.Addr) []proxyproto.TLV {
+	if a, ok := addr.(*Addr); ok {
+		return a.TLVs
+	}
+	return nil
+}
+
+// UnwrapAddr returns the address wrapped by the given client address, if any.
+func UnwrapAddr(addr net.Addr) net.Addr {
+	if a, ok := addr.(*Addr); ok {
+		return a.Addr
+	}
+	return addr
+}
+
+type tlvsKey struct{}
+
+// WithTLVs returns a copy of the given context carrying the given TLVs.
+func WithTLVs(ctx context.Context, tlvs []proxyproto.TLV) context.Context {
+	return context.WithValue(ctx, tlvsKey{}, tlvs)
+}
+
+// TLVsFromContext returns the TLVs carried by the given context, if any.
+func TLVsFromContext(ctx context.Context) []proxyproto.TLV {
+	tlvs, _ := ctx.Value(tlvsKey{}).([]proxyproto.TLV)
+	return tlvs
+}
@@ -0,0 +1,107 @@
+package proxyprotocol
+
+import (
+	"testing"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/pires/go-proxyproto/tlvparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseType(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		name         string
+		expectedType proxyproto.PP2Type
+		expectedErr  bool
+	}{
+		{
+			desc:         "name",
+			name:         "authority",
+			expectedType: proxyproto.PP2_TYPE_AUTHORITY,
+		},
+		{
+			desc:         "name is case insensitive",
+			name:         "ALPN",
+			expectedType: proxyproto.PP2_TYPE_ALPN,
+		},
+		{
+			desc:         "hexadecimal number",
+			name:         "0xEA",
+			expectedType: 0xEA,
+		},
+		{
+			desc:         "decimal number",
+			name:         "224",
+			expectedType: 0xE0,
+		},
+		{
+			desc:        "number out of range",
+			name:        "256",
+			expectedErr: true,
+		},
+		{
+			desc:        "unknown name",
+			name:        "foo",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			typ, err := ParseType(test.name)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedType, typ)
+		})
+	}
+}
+
+func TestValues(t *testing.T) {
+	ssl, err := tlvparse.PP2SSL{
+		Client: tlvparse.PP2_BITFIELD_CLIENT_SSL,
+		TLV: []proxyproto.TLV{
+			{Type: proxyproto.PP2_SUBTYPE_SSL_VERSION, Value: []byte("TLSv1.3")},
+			{Type: proxyproto.PP2_SUBTYPE_SSL_CN, Value: []byte("client.example.com")},
+		},
+	}.Marshal()
+	require.NoError(t, err)
+
+	tlvs := []proxyproto.TLV{
+		ssl,
+		{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("example.com")},
+		{Type: tlvparse.PP2_TYPE_AWS, Value: append([]byte{tlvparse.PP2_SUBTYPE_AWS_VPCE_ID}, "vpce-123"...)},
+		{Type: tlvparse.PP2_TYPE_AZURE, Value: []byte{tlvparse.PP2_SUBTYPE_AZURE_PRIVATEENDPOINT_LINKID, 0x01, 0x00, 0x00, 0x00}},
+		{Type: tlvparse.PP2_TYPE_GCP, Value: []byte{0, 0, 0, 0, 0, 0, 0, 0x2A}},
+		{Type: 0xE5, Value: []byte{0x00, 0xFF}},
+	}
+
+	values := Values(tlvs)
+
+	assert.Equal(t, "TLSv1.3", values[KeySSLVersion])
+	assert.Equal(t, "client.example.com", values[KeySSLClientCN])
+	assert.Equal(t, "vpce-123", values[KeyAWSVPCEID])
+	assert.Equal(t, "1", values[KeyAzureLinkID])
+	assert.Equal(t, "42", values[KeyGCPPSCID])
+	assert.Equal(t, "example.com", values["authority"])
+	assert.Equal(t, "00ff", values["0xE5"])
+
+	value, ok := Lookup(tlvs, "AWS_VPCE_ID")
+	assert.True(t, ok)
+	assert.Equal(t, "vpce-123", value)
+
+	value, ok = Lookup(tlvs, "0xe5")
+	assert.True(t, ok)
+	assert.Equal(t, "00ff", value)
+
+	_, ok = Lookup(tlvs, "alpn")
+	assert.False(t, ok)
+}
@@ -55,6 +55,26 @@ func ParseHostSNI(rule string) ([]string, error) {
 	return lower(parseDomain(buildTree())), nil
 }
 
+// ParseProtocols extracts the protocols declared in a TCP rule.
+func ParseProtocols(rule string) ([]string, error) {
+	parser, err := newTCPParser()
+	if err != nil {
+		return nil, err
+	}
+
+	parse, err := parser.Parse(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	buildTree, ok := parse.(treeBuilder)
+	if !ok {
+		return nil, errors.New("cannot parse")
+	}
+
+	return lower(parseProtocol(buildTree())), nil
+}
+
 func lower(slice []string) []string {
 	var lowerStrings []string
 	for _, value := range slice {
@@ -74,6 +94,17 @@ func parseDomain(tree *tree) []string {
 	}
 }
 
+func parseProtocol(tree *tree) []string {
+	switch tree.matcher {
+	case or:
+		return append(parseProtocol(tree.ruleLeft), parseProtocol(tree.ruleRight)...)
+	case "Protocol":
+		return tree.value
+	default:
+		return nil
+	}
+}
+
 func andFunc(left, right treeBuilder) treeBuilder {
 	return func() *tree {
 		return &tree{
@@ -150,19 +181,21 @@ func newTCPParser() (predicate.Parser, error) {
 	parserFuncs := make(map[string]interface{})
 
 	// FIXME quircky way of waiting for new rules
-	matcherName := "HostSNI"
-	fn := func(value ...string) treeBuilder {
-		return func() *tree {
-			return &tree{
-				matcher: matcherName,
-				value:   value,
+	for _, matcherName := range []string{"HostSNI", "Protocol"} {
+		matcherName := matcherName
+		fn := func(value ...string) treeBuilder {
+			return func() *tree {
+				return &tree{
+					matcher: matcherName,
+					value:   value,
+				}
 			}
 		}
+		parserFuncs[matcherName] = fn
+		parserFuncs[strings.ToLower(matcherName)] = fn
+		parserFuncs[strings.ToUpper(matcherName)] = fn
+		parserFuncs[strings.Title(strings.ToLower(matcherName))] = fn
 	}
-	parserFuncs[matcherName] = fn
-	parserFuncs[strings.ToLower(matcherName)] = fn
-	parserFuncs[strings.ToUpper(matcherName)] = fn
-	parserFuncs[strings.Title(strings.ToLower(matcherName))] = fn
 
 	return predicate.NewParser(predicate.Def{
 		Operators: predicate.Operators{
@@ -10,6 +10,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/ip"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
+	"github.com/traefik/traefik/v2/pkg/proxyprotocol"
 	"github.com/vulcand/predicate"
 )
 
@@ -24,6 +25,8 @@ var funcs = map[string]func(*mux.Route, ...string) error{
 	"Headers":       headers,
 	"HeadersRegexp": headersRegexp,
 	"Query":         query,
+
+	"ProxyProtocolTLV": proxyProtocolTLV,
 }
 
 // Router handle routing with rules.
@@ -182,6 +185,34 @@ func clientIP(route *mux.Route, clientIPs ...string) error {
 	return nil
 }
 
+func proxyProtocolTLV(route *mux.Route, tlv ...string) error {
+	if len(tlv) < 2 {
+		return fmt.Errorf("\"ProxyProtocolTLV\" matcher requires a TLV and at least one value, got %v", tlv)
+	}
+
+	key, values := tlv[0], tlv[1:]
+	if err := proxyprotocol.ValidateKey(key); err != nil {
+		return fmt.Errorf("\"ProxyProtocolTLV\" matcher: %w", err)
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		value, ok := proxyprotocol.Lookup(proxyprotocol.TLVsFromContext(req.Context()), key)
+		if !ok {
+			return false
+		}
+
+		for _, v := range values {
+			if v == value {
+				return true
+			}
+		}
+
+		return false
+	})
+
+	return nil
+}
+
 func hostRegexp(route *mux.Route, hosts ...string) error {
 	router := route.Subrouter()
 	for _, host := range hosts {
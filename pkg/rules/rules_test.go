@@ -6,9 +6,11 @@ import (
 	"testing"
 
 	"github.com/gorilla/mux"
+	"github.com/pires/go-proxyproto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
+	"github.com/traefik/traefik/v2/pkg/proxyprotocol"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
 )
 
@@ -668,6 +670,81 @@ func Test_addRoute(t *testing.T) {
 	}
 }
 
+func TestProxyProtocolTLV(t *testing.T) {
+	tlvs := []proxyproto.TLV{
+		{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("example.com")},
+		{Type: 0xEA, Value: append([]byte{0x01}, "vpce-123"...)},
+	}
+
+	testCases := []struct {
+		desc          string
+		rule          string
+		tlvs          []proxyproto.TLV
+		expected      int
+		expectedError bool
+	}{
+		{
+			desc:          "missing value",
+			rule:          "ProxyProtocolTLV(`authority`)",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid TLV",
+			rule:          "ProxyProtocolTLV(`foo`, `bar`)",
+			expectedError: true,
+		},
+		{
+			desc:     "matching TLV type",
+			rule:     "ProxyProtocolTLV(`authority`, `example.com`)",
+			tlvs:     tlvs,
+			expected: http.StatusOK,
+		},
+		{
+			desc:     "matching derived value among several values",
+			rule:     "ProxyProtocolTLV(`aws_vpce_id`, `vpce-456`, `vpce-123`)",
+			tlvs:     tlvs,
+			expected: http.StatusOK,
+		},
+		{
+			desc:     "non matching value",
+			rule:     "ProxyProtocolTLV(`authority`, `example.org`)",
+			tlvs:     tlvs,
+			expected: http.StatusNotFound,
+		},
+		{
+			desc:     "without TLVs",
+			rule:     "ProxyProtocolTLV(`authority`, `example.com`)",
+			expected: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			router, err := NewRouter()
+			require.NoError(t, err)
+
+			err = router.AddRoute(test.rule, 0, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+			req = req.WithContext(proxyprotocol.WithTLVs(req.Context(), test.tlvs))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expected, w.Code)
+		})
+	}
+}
+
 func Test_addRoutePriority(t *testing.T) {
 	type Case struct {
 		xFrom    string
@@ -958,3 +1035,65 @@ func TestParseDomains(t *testing.T) {
 		})
 	}
 }
+
+func TestParseHostSNIAndProtocols(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		expression        string
+		expectedDomains   []string
+		expectedProtocols []string
+		expectedError     bool
+	}{
+		{
+			desc:            "HostSNI rule",
+			expression:      "HostSNI(`Foo.Bar`, `test.bar`)",
+			expectedDomains: []string{"foo.bar", "test.bar"},
+		},
+		{
+			desc:              "Protocol rule",
+			expression:        "Protocol(`SSH`, `mqtt`)",
+			expectedProtocols: []string{"ssh", "mqtt"},
+		},
+		{
+			desc:              "Protocol rules lower case",
+			expression:        "protocol(`ssh`) || protocol(`redis`)",
+			expectedProtocols: []string{"ssh", "redis"},
+		},
+		{
+			desc:              "HostSNI and Protocol rules",
+			expression:        "HostSNI(`*`) || Protocol(`ssh`)",
+			expectedDomains:   []string{"*"},
+			expectedProtocols: []string{"ssh"},
+		},
+		{
+			desc:          "HostSNI and Protocol rules with the And operator",
+			expression:    "HostSNI(`*`) && Protocol(`ssh`)",
+			expectedError: true,
+		},
+		{
+			desc:          "unknown matcher",
+			expression:    "Host(`foo.bar`)",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			domains, err := ParseHostSNI(test.expression)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			protocols, err := ParseProtocols(test.expression)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedDomains, domains)
+			assert.Equal(t, test.expectedProtocols, protocols)
+		})
+	}
+}
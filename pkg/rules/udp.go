@@ -0,0 +1,217 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/ip"
+	"github.com/vulcand/predicate"
+)
+
+// UDPMatcher reports whether a UDP session matches a rule,
+// given the address of the client and the payload of the first packet of the session.
+type UDPMatcher func(remoteAddr net.Addr, payload []byte) bool
+
+var udpFuncs = map[string]func(values ...string) (UDPMatcher, error){
+	"ClientIP":      udpClientIP,
+	"PayloadPrefix": payloadPrefix,
+	"PayloadHex":    payloadHex,
+}
+
+// NewUDPMatcher builds the matcher of a UDP rule.
+func NewUDPMatcher(rule string) (UDPMatcher, error) {
+	parser, err := newUDPParser()
+	if err != nil {
+		return nil, err
+	}
+
+	parse, err := parser.Parse(rule)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing rule %s: %w", rule, err)
+	}
+
+	buildTree, ok := parse.(treeBuilder)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing rule %s", rule)
+	}
+
+	return buildUDPMatcher(buildTree())
+}
+
+func buildUDPMatcher(rule *tree) (UDPMatcher, error) {
+	switch rule.matcher {
+	case and, or:
+		left, err := buildUDPMatcher(rule.ruleLeft)
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := buildUDPMatcher(rule.ruleRight)
+		if err != nil {
+			return nil, err
+		}
+
+		if rule.matcher == and {
+			return func(remoteAddr net.Addr, payload []byte) bool {
+				return left(remoteAddr, payload) && right(remoteAddr, payload)
+			}, nil
+		}
+
+		return func(remoteAddr net.Addr, payload []byte) bool {
+			return left(remoteAddr, payload) || right(remoteAddr, payload)
+		}, nil
+	default:
+		err := checkRule(rule)
+		if err != nil {
+			return nil, err
+		}
+
+		matcher, err := udpFuncs[rule.matcher](rule.value...)
+		if err != nil {
+			return nil, err
+		}
+
+		if rule.not {
+			return func(remoteAddr net.Addr, payload []byte) bool {
+				return !matcher(remoteAddr, payload)
+			}, nil
+		}
+
+		return matcher, nil
+	}
+}
+
+func udpClientIP(clientIPs ...string) (UDPMatcher, error) {
+	checker, err := ip.NewChecker(clientIPs)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize IP Checker for \"ClientIP\" matcher: %w", err)
+	}
+
+	return func(remoteAddr net.Addr, _ []byte) bool {
+		if udpAddr, ok := remoteAddr.(*net.UDPAddr); ok {
+			return checker.ContainsIP(udpAddr.IP)
+		}
+
+		host, _, err := net.SplitHostPort(remoteAddr.String())
+		if err != nil {
+			host = remoteAddr.String()
+		}
+
+		ok, err := checker.Contains(host)
+		return err == nil && ok
+	}, nil
+}
+
+func payloadPrefix(prefixes ...string) (UDPMatcher, error) {
+	return func(_ net.Addr, payload []byte) bool {
+		for _, prefix := range prefixes {
+			if bytes.HasPrefix(payload, []byte(prefix)) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// bytePattern is a sequence of bytes, where the bytes not to be compared are masked out.
+type bytePattern struct {
+	value []byte
+	mask  []bool
+}
+
+func (p bytePattern) matchPrefix(payload []byte) bool {
+	if len(payload) < len(p.value) {
+		return false
+	}
+
+	for i, b := range p.value {
+		if p.mask[i] && payload[i] != b {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseBytePattern parses a hexadecimal byte pattern, such as "0001??00",
+// where "??" matches any byte. Spaces between the bytes are ignored.
+func parseBytePattern(value string) (bytePattern, error) {
+	value = strings.ReplaceAll(value, " ", "")
+	if len(value)%2 != 0 {
+		return bytePattern{}, errors.New("odd number of hexadecimal digits")
+	}
+
+	var pattern bytePattern
+	for i := 0; i < len(value); i += 2 {
+		digits := value[i : i+2]
+		if digits == "??" {
+			pattern.value = append(pattern.value, 0)
+			pattern.mask = append(pattern.mask, false)
+			continue
+		}
+
+		b, err := hex.DecodeString(digits)
+		if err != nil {
+			return bytePattern{}, err
+		}
+
+		pattern.value = append(pattern.value, b[0])
+		pattern.mask = append(pattern.mask, true)
+	}
+
+	return pattern, nil
+}
+
+func payloadHex(values ...string) (UDPMatcher, error) {
+	var patterns []bytePattern
+	for _, value := range values {
+		pattern, err := parseBytePattern(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for \"PayloadHex\" matcher: %w", value, err)
+		}
+
+		patterns = append(patterns, pattern)
+	}
+
+	return func(_ net.Addr, payload []byte) bool {
+		for _, pattern := range patterns {
+			if pattern.matchPrefix(payload) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func newUDPParser() (predicate.Parser, error) {
+	parserFuncs := make(map[string]interface{})
+
+	for matcherName := range udpFuncs {
+		matcherName := matcherName
+		fn := func(value ...string) treeBuilder {
+			return func() *tree {
+				return &tree{
+					matcher: matcherName,
+					value:   value,
+				}
+			}
+		}
+		parserFuncs[matcherName] = fn
+		parserFuncs[strings.ToLower(matcherName)] = fn
+		parserFuncs[strings.ToUpper(matcherName)] = fn
+		parserFuncs[strings.Title(strings.ToLower(matcherName))] = fn
+	}
+
+	return predicate.NewParser(predicate.Def{
+		Operators: predicate.Operators{
+			AND: andFunc,
+			OR:  orFunc,
+			NOT: notFunc,
+		},
+		Functions: parserFuncs,
+	})
+}
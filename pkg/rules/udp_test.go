@@ -0,0 +1,170 @@
+package rules
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUDPMatcher(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rule          string
+		expectedError bool
+		remoteAddr    string
+		payload       string
+		expected      bool
+	}{
+		{
+			desc:          "Empty rule",
+			rule:          "",
+			expectedError: true,
+		},
+		{
+			desc:          "Unknown matcher",
+			rule:          "HostSNI(`foo.bar`)",
+			expectedError: true,
+		},
+		{
+			desc:          "ClientIP matcher without value",
+			rule:          "ClientIP(``)",
+			expectedError: true,
+		},
+		{
+			desc:          "ClientIP matcher with an invalid CIDR",
+			rule:          "ClientIP(`10.0.0.0/33`)",
+			expectedError: true,
+		},
+		{
+			desc:       "ClientIP matcher, matching IP",
+			rule:       "ClientIP(`10.0.0.1`)",
+			remoteAddr: "10.0.0.1:53000",
+			expected:   true,
+		},
+		{
+			desc:       "ClientIP matcher, matching CIDR",
+			rule:       "ClientIP(`192.168.0.0/16`, `10.0.0.0/8`)",
+			remoteAddr: "10.1.2.3:53000",
+			expected:   true,
+		},
+		{
+			desc:       "ClientIP matcher, matching IPv6 CIDR",
+			rule:       "ClientIP(`fe80::/10`)",
+			remoteAddr: "[fe80::1]:53000",
+			expected:   true,
+		},
+		{
+			desc:       "ClientIP matcher, not matching",
+			rule:       "ClientIP(`192.168.0.0/16`)",
+			remoteAddr: "10.0.0.1:53000",
+		},
+		{
+			desc:       "PayloadPrefix matcher, matching",
+			rule:       "PayloadPrefix(`M-SEARCH`, `NOTIFY`)",
+			remoteAddr: "10.0.0.1:1900",
+			payload:    "NOTIFY * HTTP/1.1\r\n",
+			expected:   true,
+		},
+		{
+			desc:       "PayloadPrefix matcher, not matching",
+			rule:       "PayloadPrefix(`M-SEARCH`)",
+			remoteAddr: "10.0.0.1:1900",
+			payload:    "M-SEA",
+		},
+		{
+			desc:       "PayloadHex matcher, matching",
+			rule:       "PayloadHex(`01 00 00 01`)",
+			remoteAddr: "10.0.0.1:53000",
+			payload:    "\x01\x00\x00\x01\x00\x00",
+			expected:   true,
+		},
+		{
+			desc:       "PayloadHex matcher with wildcards, matching",
+			rule:       "PayloadHex(`????0100`)",
+			remoteAddr: "10.0.0.1:53000",
+			payload:    "\xab\xcd\x01\x00\x00\x01",
+			expected:   true,
+		},
+		{
+			desc:       "PayloadHex matcher, not matching",
+			rule:       "PayloadHex(`????8000`)",
+			remoteAddr: "10.0.0.1:53000",
+			payload:    "\xab\xcd\x01\x00\x00\x01",
+		},
+		{
+			desc:       "PayloadHex matcher, payload shorter than the pattern",
+			rule:       "PayloadHex(`????0100`)",
+			remoteAddr: "10.0.0.1:53000",
+			payload:    "\xab\xcd\x01",
+		},
+		{
+			desc:          "PayloadHex matcher with an odd number of digits",
+			rule:          "PayloadHex(`010`)",
+			expectedError: true,
+		},
+		{
+			desc:          "PayloadHex matcher with an invalid digit",
+			rule:          "PayloadHex(`0g`)",
+			expectedError: true,
+		},
+		{
+			desc:       "Lower case matcher",
+			rule:       "clientip(`10.0.0.1`)",
+			remoteAddr: "10.0.0.1:53000",
+			expected:   true,
+		},
+		{
+			desc:       "And operator, matching",
+			rule:       "ClientIP(`10.0.0.0/8`) && PayloadPrefix(`foo`)",
+			remoteAddr: "10.0.0.1:53000",
+			payload:    "foobar",
+			expected:   true,
+		},
+		{
+			desc:       "And operator, not matching",
+			rule:       "ClientIP(`10.0.0.0/8`) && PayloadPrefix(`bar`)",
+			remoteAddr: "10.0.0.1:53000",
+			payload:    "foobar",
+		},
+		{
+			desc:       "Or operator, matching",
+			rule:       "ClientIP(`192.168.0.0/16`) || PayloadPrefix(`foo`)",
+			remoteAddr: "10.0.0.1:53000",
+			payload:    "foobar",
+			expected:   true,
+		},
+		{
+			desc:       "Not operator, matching",
+			rule:       "!ClientIP(`192.168.0.0/16`)",
+			remoteAddr: "10.0.0.1:53000",
+			expected:   true,
+		},
+		{
+			desc:       "Not operator on an And operator, not matching",
+			rule:       "!(ClientIP(`10.0.0.0/8`) && PayloadPrefix(`foo`))",
+			remoteAddr: "10.0.0.1:53000",
+			payload:    "foobar",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			matcher, err := NewUDPMatcher(test.rule)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			remoteAddr, err := net.ResolveUDPAddr("udp", test.remoteAddr)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, matcher(remoteAddr, []byte(test.payload)))
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package server
 
 import (
+	"strings"
+
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
@@ -102,6 +104,7 @@ func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoint
 				} else {
 					defaultTLSStoreProviders = append(defaultTLSStoreProviders, pvd)
 				}
+				store.SNIOptions = qualifySNIOptions(pvd, store.SNIOptions)
 				conf.TLS.Stores[key] = store
 			}
 
@@ -187,3 +190,21 @@ func containsACMETLS1(stores []string) bool {
 
 	return false
 }
+
+// qualifySNIOptions qualifies the names of the TLS options referenced by the SNI options of a store with the provider name,
+// unless they are already qualified, or are the default options.
+func qualifySNIOptions(pvd string, sniOptions []tls.SNIOptions) []tls.SNIOptions {
+	if len(sniOptions) == 0 {
+		return sniOptions
+	}
+
+	qualified := make([]tls.SNIOptions, len(sniOptions))
+	for i, options := range sniOptions {
+		qualified[i] = options
+		if options.Options != tls.DefaultTLSConfigName && !strings.Contains(options.Options, "@") {
+			qualified[i].Options = provider.MakeQualifiedName(pvd, options.Options)
+		}
+	}
+
+	return qualified
+}
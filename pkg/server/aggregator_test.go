@@ -460,6 +460,33 @@ func Test_mergeConfiguration_tlsStore(t *testing.T) {
 			},
 			expected: map[string]tls.Store{},
 		},
+		{
+			desc: "Qualify the TLS options of the SNI options",
+			given: dynamic.Configurations{
+				"provider-1": &dynamic.Configuration{
+					TLS: &dynamic.TLSConfiguration{
+						Stores: map[string]tls.Store{
+							"default": {
+								SNIOptions: []tls.SNIOptions{
+									{SNI: []string{"*.bank.example.com"}, Options: "strict"},
+									{SNI: []string{"foo.example.com"}, Options: "foo@provider-2"},
+									{SNI: []string{"bar.example.com"}, Options: "default"},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: map[string]tls.Store{
+				"default": {
+					SNIOptions: []tls.SNIOptions{
+						{SNI: []string{"*.bank.example.com"}, Options: "strict@provider-1"},
+						{SNI: []string{"foo.example.com"}, Options: "foo@provider-2"},
+						{SNI: []string{"bar.example.com"}, Options: "default"},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -14,6 +14,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/middlewares/buffering"
 	"github.com/traefik/traefik/v2/pkg/middlewares/chain"
 	"github.com/traefik/traefik/v2/pkg/middlewares/circuitbreaker"
+	"github.com/traefik/traefik/v2/pkg/middlewares/clientcertauth"
 	"github.com/traefik/traefik/v2/pkg/middlewares/compress"
 	"github.com/traefik/traefik/v2/pkg/middlewares/customerrors"
 	"github.com/traefik/traefik/v2/pkg/middlewares/headers"
@@ -163,6 +164,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// ClientCertAuth
+	if config.ClientCertAuth != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return clientcertauth.New(ctx, next, *config.ClientCertAuth, middlewareName)
+		}
+	}
+
 	// Compress
 	if config.Compress != nil {
 		if middleware != nil {
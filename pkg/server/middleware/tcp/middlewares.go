@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	clientcertauth "github.com/traefik/traefik/v2/pkg/middlewares/tcp/clientcertauth"
 	ipwhitelist "github.com/traefik/traefik/v2/pkg/middlewares/tcp/ipwhitelist"
 	rateLimiter "github.com/traefik/traefik/v2/pkg/middlewares/tcp/ratelimiter"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
@@ -101,6 +102,13 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// ClientCertAuth
+	if config.ClientCertAuth != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return clientcertauth.New(ctx, next, *config.ClientCertAuth, middlewareName)
+		}
+	}
+
 	if middleware == nil {
 		return nil, fmt.Errorf("invalid middleware %q configuration: invalid middleware type or middleware does not exist", middlewareName)
 	}
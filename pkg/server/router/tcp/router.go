@@ -11,6 +11,7 @@ import (
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/traefik/traefik/v2/pkg/rules"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	tcpservice "github.com/traefik/traefik/v2/pkg/server/service/tcp"
@@ -29,25 +30,28 @@ func NewManager(conf *runtime.Configuration,
 	httpHandlers map[string]http.Handler,
 	httpsHandlers map[string]http.Handler,
 	tlsManager *traefiktls.Manager,
+	accessLoggerMiddleware *accesslog.Handler,
 ) *Manager {
 	return &Manager{
-		serviceManager:     serviceManager,
-		middlewaresBuilder: middlewaresBuilder,
-		httpHandlers:       httpHandlers,
-		httpsHandlers:      httpsHandlers,
-		tlsManager:         tlsManager,
-		conf:               conf,
+		serviceManager:         serviceManager,
+		middlewaresBuilder:     middlewaresBuilder,
+		httpHandlers:           httpHandlers,
+		httpsHandlers:          httpsHandlers,
+		tlsManager:             tlsManager,
+		accessLoggerMiddleware: accessLoggerMiddleware,
+		conf:                   conf,
 	}
 }
 
 // Manager is a route/router manager.
 type Manager struct {
-	serviceManager     *tcpservice.Manager
-	middlewaresBuilder middlewareBuilder
-	httpHandlers       map[string]http.Handler
-	httpsHandlers      map[string]http.Handler
-	tlsManager         *traefiktls.Manager
-	conf               *runtime.Configuration
+	serviceManager         *tcpservice.Manager
+	middlewaresBuilder     middlewareBuilder
+	httpHandlers           map[string]http.Handler
+	httpsHandlers          map[string]http.Handler
+	tlsManager             *traefiktls.Manager
+	accessLoggerMiddleware *accesslog.Handler
+	conf                   *runtime.Configuration
 }
 
 func (m *Manager) getTCPRouters(ctx context.Context, entryPoints []string) map[string]map[string]*runtime.TCPRouterInfo {
@@ -177,8 +181,8 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 
 		// Domain Fronting
 		if !strings.EqualFold(host, serverName) {
-			tlsOptionSNI := findTLSOptionName(tlsOptionsForHost, serverName)
-			tlsOptionHeader := findTLSOptionName(tlsOptionsForHost, host)
+			tlsOptionSNI := m.findTLSOptionName(tlsOptionsForHost, serverName)
+			tlsOptionHeader := m.findTLSOptionName(tlsOptionsForHost, host)
 
 			if tlsOptionHeader != tlsOptionSNI {
 				log.WithoutContext().
@@ -241,7 +245,7 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
-		handler, err := m.buildTCPHandler(ctxRouter, routerConfig)
+		handler, err := m.buildTCPHandler(ctxRouter, routerName, routerConfig)
 		if err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error(err)
@@ -256,6 +260,29 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
+		protocols, err := rules.ParseProtocols(routerConfig.Rule)
+		if err != nil {
+			routerErr := fmt.Errorf("unknown rule %s", routerConfig.Rule)
+			routerConfig.AddError(routerErr, true)
+			logger.Error(routerErr)
+			continue
+		}
+
+		if len(protocols) > 0 && routerConfig.TLS != nil {
+			routerErr := errors.New("the Protocol rule only applies to non-TLS connections, and cannot be used with a TLS section")
+			routerConfig.AddError(routerErr, true)
+			logger.Error(routerErr)
+			continue
+		}
+
+		for _, protocol := range protocols {
+			logger.Debugf("Adding route for protocol %s on TCP", protocol)
+			if err := router.AddRouteProtocol(protocol, handler); err != nil {
+				routerConfig.AddError(err, true)
+				logger.Error(err)
+			}
+		}
+
 		for _, domain := range domains {
 			logger.Debugf("Adding route %s on TCP", domain)
 			switch {
@@ -301,7 +328,7 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 	return router, nil
 }
 
-func (m *Manager) buildTCPHandler(ctx context.Context, router *runtime.TCPRouterInfo) (tcp.Handler, error) {
+func (m *Manager) buildTCPHandler(ctx context.Context, routerName string, router *runtime.TCPRouterInfo) (tcp.Handler, error) {
 	var qualifiedNames []string
 	for _, name := range router.Middlewares {
 		qualifiedNames = append(qualifiedNames, provider.GetQualifiedName(ctx, name))
@@ -312,18 +339,32 @@ func (m *Manager) buildTCPHandler(ctx context.Context, router *runtime.TCPRouter
 		return nil, errors.New("the service is missing on the router")
 	}
 
-	sHandler, err := m.serviceManager.BuildTCP(ctx, router.Service)
+	sHandler, err := m.serviceManager.BuildTCP(tcpservice.AddRouterInContext(ctx, routerName), router.Service)
 	if err != nil {
 		return nil, err
 	}
 
 	mHandler := m.middlewaresBuilder.BuildChain(ctx, router.Middlewares)
 
-	return tcp.NewChain().Extend(*mHandler).Then(sHandler)
+	chain := tcp.NewChain()
+
+	if m.accessLoggerMiddleware != nil {
+		chain = chain.Append(accesslog.WrapTCPHandler(m.accessLoggerMiddleware), func(next tcp.Handler) (tcp.Handler, error) {
+			return accesslog.NewTCPFieldHandler(next, accesslog.RouterName, routerName, nil), nil
+		})
+	}
+
+	return chain.Extend(*mHandler).Then(sHandler)
 }
 
-func findTLSOptionName(tlsOptionsForHost map[string]string, host string) string {
-	tlsOptions, ok := tlsOptionsForHost[host]
+func (m *Manager) findTLSOptionName(tlsOptionsForHost map[string]string, host string) string {
+	// The SNI options of the store take precedence over the options of the routers.
+	tlsOptions, ok := m.tlsManager.GetSNIOptionsName(traefiktls.DefaultTLSStoreName, host)
+	if ok {
+		return tlsOptions
+	}
+
+	tlsOptions, ok = tlsOptionsForHost[host]
 	if ok {
 		return tlsOptions
 	}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/rules"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	udpservice "github.com/traefik/traefik/v2/pkg/server/service/udp"
 	"github.com/traefik/traefik/v2/pkg/udp"
@@ -15,17 +17,20 @@ import (
 // NewManager Creates a new Manager.
 func NewManager(conf *runtime.Configuration,
 	serviceManager *udpservice.Manager,
+	accessLoggerMiddleware *accesslog.Handler,
 ) *Manager {
 	return &Manager{
-		serviceManager: serviceManager,
-		conf:           conf,
+		serviceManager:         serviceManager,
+		accessLoggerMiddleware: accessLoggerMiddleware,
+		conf:                   conf,
 	}
 }
 
 // Manager is a route/router manager.
 type Manager struct {
-	serviceManager *udpservice.Manager
-	conf           *runtime.Configuration
+	serviceManager         *udpservice.Manager
+	accessLoggerMiddleware *accesslog.Handler
+	conf                   *runtime.Configuration
 }
 
 func (m *Manager) getUDPRouters(ctx context.Context, entryPoints []string) map[string]map[string]*runtime.UDPRouterInfo {
@@ -48,25 +53,22 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string) m
 
 		ctx := log.With(rootCtx, log.Str(log.EntryPointName, entryPointName))
 
-		if len(routers) > 1 {
-			log.FromContext(ctx).Warn("Config has more than one udp router for a given entrypoint.")
-		}
-
-		handlers, err := m.buildEntryPointHandlers(ctx, routers)
+		handler, err := m.buildEntryPointHandler(ctx, routers)
 		if err != nil {
 			log.FromContext(ctx).Error(err)
 			continue
 		}
 
-		if len(handlers) > 0 {
-			// As UDP support only one router per entrypoint, we only take the first one.
-			entryPointHandlers[entryPointName] = handlers[0]
+		if handler != nil {
+			entryPointHandlers[entryPointName] = handler
 		}
 	}
 	return entryPointHandlers
 }
 
-func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[string]*runtime.UDPRouterInfo) ([]udp.Handler, error) {
+// buildEntryPointHandler builds the handler routing the sessions of an entrypoint to its routers.
+// A router without a rule catches all the sessions matching none of the rules of the other routers.
+func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.UDPRouterInfo) (udp.Handler, error) {
 	var rtNames []string
 	for routerName := range configs {
 		rtNames = append(rtNames, routerName)
@@ -76,7 +78,10 @@ func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[strin
 		return rtNames[i] > rtNames[j]
 	})
 
-	var handlers []udp.Handler
+	router := &udp.Router{}
+
+	var catchAll udp.Handler
+	var hasRoutes bool
 
 	for _, routerName := range rtNames {
 		routerConfig := configs[routerName]
@@ -91,6 +96,17 @@ func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[strin
 			continue
 		}
 
+		var matcher rules.UDPMatcher
+		if routerConfig.Rule != "" {
+			var err error
+			matcher, err = rules.NewUDPMatcher(routerConfig.Rule)
+			if err != nil {
+				routerConfig.AddError(err, true)
+				logger.Error(err)
+				continue
+			}
+		}
+
 		handler, err := m.serviceManager.BuildUDP(ctxRouter, routerConfig.Service)
 		if err != nil {
 			routerConfig.AddError(err, true)
@@ -98,8 +114,36 @@ func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[strin
 			continue
 		}
 
-		handlers = append(handlers, handler)
+		if m.accessLoggerMiddleware != nil {
+			handler = accesslog.WrapUDPHandler(m.accessLoggerMiddleware, accesslog.NewUDPFieldHandler(handler, accesslog.RouterName, routerName, nil))
+		}
+
+		if matcher == nil {
+			if catchAll != nil {
+				logger.Warn("Config has more than one udp router without rule for a given entrypoint, ignoring it.")
+				continue
+			}
+
+			catchAll = handler
+			continue
+		}
+
+		priority := routerConfig.Priority
+		if priority == 0 {
+			priority = len(routerConfig.Rule)
+		}
+
+		router.AddRoute(udp.MatcherFunc(matcher), priority, handler)
+		hasRoutes = true
+	}
+
+	if !hasRoutes {
+		return catchAll, nil
+	}
+
+	if catchAll != nil {
+		router.AddCatchAll(catchAll)
 	}
 
-	return handlers, nil
+	return router, nil
 }
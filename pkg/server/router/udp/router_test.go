@@ -86,6 +86,79 @@ func TestRuntimeConfiguration(t *testing.T) {
 			},
 			expectedError: 1,
 		},
+		{
+			desc: "Routers with rules",
+			serviceConfig: map[string]*runtime.UDPServiceInfo{
+				"foo-service": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "127.0.0.1:8085",
+								},
+							},
+						},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.UDPRouterInfo{
+				"foo": {
+					UDPRouter: &dynamic.UDPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "ClientIP(`10.0.0.0/8`)",
+					},
+				},
+				"bar": {
+					UDPRouter: &dynamic.UDPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "PayloadPrefix(`foo`) && !ClientIP(`192.168.0.1`)",
+						Priority:    10,
+					},
+				},
+				"baz": {
+					UDPRouter: &dynamic.UDPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+					},
+				},
+			},
+			expectedError: 0,
+		},
+		{
+			desc: "Router with invalid rule",
+			serviceConfig: map[string]*runtime.UDPServiceInfo{
+				"foo-service": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "127.0.0.1:8085",
+								},
+							},
+						},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.UDPRouterInfo{
+				"foo": {
+					UDPRouter: &dynamic.UDPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`foo.bar`)",
+					},
+				},
+				"bar": {
+					UDPRouter: &dynamic.UDPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "PayloadHex(`0`)",
+					},
+				},
+			},
+			expectedError: 2,
+		},
 		{
 			desc: "Router with broken service",
 			serviceConfig: map[string]*runtime.UDPServiceInfo{
@@ -119,8 +192,8 @@ func TestRuntimeConfiguration(t *testing.T) {
 				UDPServices: test.serviceConfig,
 				UDPRouters:  test.routerConfig,
 			}
-			serviceManager := udp.NewManager(conf)
-			routerManager := NewManager(conf, serviceManager)
+			serviceManager := udp.NewManager(conf, nil)
+			routerManager := NewManager(conf, serviceManager, nil)
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints)
 
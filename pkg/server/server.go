@@ -7,6 +7,7 @@ import (
 	"os/signal"
 	"time"
 
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
@@ -25,6 +26,8 @@ type Server struct {
 
 	signals  chan os.Signal
 	stopChan chan bool
+	// cancel stops the server gracefully, as when the context given to Start is done.
+	cancel context.CancelFunc
 
 	routinesPool *safe.Pool
 }
@@ -45,11 +48,16 @@ func NewServer(routinesPool *safe.Pool, entryPoints TCPEntryPoints, entryPointsU
 
 	srv.configureSignals()
 
+	// When the process is started by an upgrade, the parent process waits for the first configuration to be applied.
+	watcher.AddListener(func(dynamic.Configuration) { notifyUpgradeReady() })
+
 	return srv
 }
 
 // Start starts the server and Stop/Close it when context is Done.
 func (s *Server) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
 	go func() {
 		<-ctx.Done()
 		logger := log.FromContext(ctx)
@@ -111,4 +119,5 @@ func stopMetricsClients() {
 	metrics.StopDatadog()
 	metrics.StopStatsd()
 	metrics.StopInfluxDB()
+	metrics.StopOpenTelemetry()
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/ip"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
 	"github.com/traefik/traefik/v2/pkg/middlewares/forwardedheaders"
 	"github.com/traefik/traefik/v2/pkg/safe"
@@ -60,7 +61,7 @@ func (h *httpForwarder) Accept() (net.Conn, error) {
 type TCPEntryPoints map[string]*TCPEntryPoint
 
 // NewTCPEntryPoints creates a new TCPEntryPoints.
-func NewTCPEntryPoints(entryPointsConfig static.EntryPoints) (TCPEntryPoints, error) {
+func NewTCPEntryPoints(entryPointsConfig static.EntryPoints, metricsRegistry metrics.Registry) (TCPEntryPoints, error) {
 	serverEntryPointsTCP := make(TCPEntryPoints)
 	for entryPointName, config := range entryPointsConfig {
 		protocol, err := config.GetProtocol()
@@ -74,7 +75,7 @@ func NewTCPEntryPoints(entryPointsConfig static.EntryPoints) (TCPEntryPoints, er
 
 		ctx := log.With(context.Background(), log.Str(log.EntryPointName, entryPointName))
 
-		serverEntryPointsTCP[entryPointName], err = NewTCPEntryPoint(ctx, config)
+		serverEntryPointsTCP[entryPointName], err = NewTCPEntryPoint(ctx, entryPointName, config, metricsRegistry)
 		if err != nil {
 			return nil, fmt.Errorf("error while building entryPoint %s: %w", entryPointName, err)
 		}
@@ -121,8 +122,10 @@ func (eps TCPEntryPoints) Switch(routersTCP map[string]*tcp.Router) {
 type TCPEntryPoint struct {
 	listener               net.Listener
 	switcher               *tcp.HandlerSwitcher
+	handler                tcp.Handler
 	transportConfiguration *static.EntryPointsTransport
 	tracker                *connectionTracker
+	limiter                *connectionLimiter
 	httpServer             *httpServer
 	httpsServer            *httpServer
 
@@ -130,10 +133,16 @@ type TCPEntryPoint struct {
 }
 
 // NewTCPEntryPoint creates a new TCPEntryPoint.
-func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint) (*TCPEntryPoint, error) {
+// It uses the socket passed by socket activation for the entry point name, if any.
+func NewTCPEntryPoint(ctx context.Context, name string, configuration *static.EntryPoint, metricsRegistry metrics.Registry) (*TCPEntryPoint, error) {
 	tracker := newConnectionTracker()
 
-	listener, err := buildListener(ctx, configuration)
+	limiter, err := newConnectionLimiter(name, configuration.ConnectionLimits, metricsRegistry)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing connection limits: %w", err)
+	}
+
+	listener, err := buildListener(ctx, name, configuration)
 	if err != nil {
 		return nil, fmt.Errorf("error preparing server: %w", err)
 	}
@@ -152,7 +161,7 @@ func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint) (*T
 		return nil, fmt.Errorf("error preparing httpsServer: %w", err)
 	}
 
-	h3server, err := newHTTP3Server(ctx, configuration, httpsServer)
+	h3server, err := newHTTP3Server(ctx, name, configuration, httpsServer)
 	if err != nil {
 		return nil, err
 	}
@@ -162,11 +171,22 @@ func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint) (*T
 	tcpSwitcher := &tcp.HandlerSwitcher{}
 	tcpSwitcher.Switch(rt)
 
+	// The connections upgraded to TLS in-band are then routed as TLS connections.
+	var handler tcp.Handler = tcpSwitcher
+	if configuration.StartTLS != nil {
+		handler, err = tcp.NewStartTLSHandler(configuration.StartTLS.Protocol, configuration.StartTLS.Hostname, tcpSwitcher)
+		if err != nil {
+			return nil, fmt.Errorf("error preparing STARTTLS: %w", err)
+		}
+	}
+
 	return &TCPEntryPoint{
 		listener:               listener,
 		switcher:               tcpSwitcher,
+		handler:                handler,
 		transportConfiguration: configuration.Transport,
 		tracker:                tracker,
+		limiter:                limiter,
 		httpServer:             httpServer,
 		httpsServer:            httpsServer,
 		http3Server:            h3server,
@@ -198,6 +218,12 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 			return
 		}
 
+		// The connections exceeding the limits are refused before being routed.
+		if e.limiter != nil && !e.limiter.accept() {
+			_ = conn.Close()
+			continue
+		}
+
 		writeCloser, err := writeCloser(conn)
 		if err != nil {
 			panic(err)
@@ -221,7 +247,15 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 				}
 			}
 
-			e.switcher.ServeTCP(newTrackedConnection(writeCloser, e.tracker))
+			if e.limiter != nil {
+				var ok bool
+				writeCloser, ok = e.limiter.limit(writeCloser)
+				if !ok {
+					return
+				}
+			}
+
+			e.handler.ServeTCP(newTrackedConnection(writeCloser, e.tracker))
 		})
 	}
 }
@@ -297,6 +331,9 @@ func (e *TCPEntryPoint) Shutdown(ctx context.Context) {
 
 // SwitchRouter switches the TCP router handler.
 func (e *TCPEntryPoint) SwitchRouter(rt *tcp.Router) {
+	rt.SetProtocolSniffTimeout(time.Duration(e.transportConfiguration.ProtocolSniffTimeout))
+	rt.SetReadTimeout(time.Duration(e.transportConfiguration.RespondingTimeouts.ReadTimeout))
+
 	rt.HTTPForwarder(e.httpServer.Forwarder)
 
 	httpHandler := rt.GetHTTPHandler()
@@ -338,13 +375,19 @@ func (c *writeCloserWrapper) CloseWrite() error {
 func writeCloser(conn net.Conn) (tcp.WriteCloser, error) {
 	switch typedConn := conn.(type) {
 	case *proxyproto.Conn:
+		if underlying, ok := typedConn.UnixConn(); ok {
+			return &proxyProtocolConn{writeCloserWrapper: writeCloserWrapper{writeCloser: underlying, Conn: typedConn}, proxyConn: typedConn}, nil
+		}
+
 		underlying, ok := typedConn.TCPConn()
 		if !ok {
 			return nil, fmt.Errorf("underlying connection is not a tcp connection")
 		}
-		return &writeCloserWrapper{writeCloser: underlying, Conn: typedConn}, nil
+		return &proxyProtocolConn{writeCloserWrapper: writeCloserWrapper{writeCloser: underlying, Conn: typedConn}, proxyConn: typedConn}, nil
 	case *net.TCPConn:
 		return typedConn, nil
+	case *net.UnixConn:
+		return typedConn, nil
 	default:
 		return nil, fmt.Errorf("unknown connection type %T", typedConn)
 	}
@@ -391,6 +434,11 @@ func buildProxyProtocolListener(ctx context.Context, entryPoint *static.EntryPoi
 	}
 
 	proxyListener.Policy = func(upstream net.Addr) (proxyproto.Policy, error) {
+		// The peers of a unix socket are local processes, allowed by the permissions of the socket.
+		if _, ok := upstream.(*net.UnixAddr); ok {
+			return proxyproto.USE, nil
+		}
+
 		ipAddr, ok := upstream.(*net.TCPAddr)
 		if !ok {
 			return proxyproto.REJECT, fmt.Errorf("type error %v", upstream)
@@ -408,13 +456,15 @@ func buildProxyProtocolListener(ctx context.Context, entryPoint *static.EntryPoi
 	return proxyListener, nil
 }
 
-func buildListener(ctx context.Context, entryPoint *static.EntryPoint) (net.Listener, error) {
-	listener, err := net.Listen("tcp", entryPoint.GetAddress())
+func buildListener(ctx context.Context, name string, entryPoint *static.EntryPoint) (net.Listener, error) {
+	listener, err := openListener(ctx, name, entryPoint)
 	if err != nil {
 		return nil, fmt.Errorf("error opening listener: %w", err)
 	}
 
-	listener = tcpKeepAliveListener{listener.(*net.TCPListener)}
+	if tcpListener, ok := listener.(*net.TCPListener); ok {
+		listener = tcpKeepAliveListener{tcpListener}
+	}
 
 	if entryPoint.ProxyProtocol != nil {
 		listener, err = buildProxyProtocolListener(ctx, entryPoint, listener)
@@ -425,6 +475,21 @@ func buildListener(ctx context.Context, entryPoint *static.EntryPoint) (net.List
 	return listener, nil
 }
 
+// openListener returns the socket passed by socket activation for the entry point, if any,
+// or else listens on the address of the entry point.
+func openListener(ctx context.Context, name string, entryPoint *static.EntryPoint) (net.Listener, error) {
+	if listener, ok := inheritedSockets.listener(name); ok {
+		log.FromContext(ctx).Infof("Using the socket %s passed by socket activation", listener.Addr())
+		return listener, nil
+	}
+
+	if entryPoint.IsUnixSocket() {
+		return buildUnixSocketListener(entryPoint.GetAddress(), entryPoint.UnixSocket)
+	}
+
+	return net.Listen("tcp", entryPoint.GetAddress())
+}
+
 func newConnectionTracker() *connectionTracker {
 	return &connectionTracker{
 		conns: make(map[net.Conn]struct{}),
@@ -514,12 +579,20 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 		return nil, err
 	}
 
+	if configuration.ProxyProtocol != nil && len(configuration.ProxyProtocol.TLVHeaders) > 0 {
+		handler, err = newProxyProtocolHeaders(handler, configuration.ProxyProtocol.TLVHeaders)
+		if err != nil {
+			return nil, fmt.Errorf("error while building the Proxy Protocol TLV headers: %w", err)
+		}
+	}
+
 	if withH2c {
 		handler = h2c.NewHandler(handler, &http2.Server{})
 	}
 
 	serverHTTP := &http.Server{
 		Handler:      handler,
+		ConnContext:  proxyProtocolConnContext,
 		ErrorLog:     httpServerLogger,
 		ReadTimeout:  time.Duration(configuration.Transport.RespondingTimeouts.ReadTimeout),
 		WriteTimeout: time.Duration(configuration.Transport.RespondingTimeouts.WriteTimeout),
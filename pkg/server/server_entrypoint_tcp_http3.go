@@ -25,12 +25,16 @@ type http3server struct {
 	getter func(info *tls.ClientHelloInfo) (*tls.Config, error)
 }
 
-func newHTTP3Server(ctx context.Context, configuration *static.EntryPoint, httpsServer *httpServer) (*http3server, error) {
+func newHTTP3Server(ctx context.Context, name string, configuration *static.EntryPoint, httpsServer *httpServer) (*http3server, error) {
 	if configuration.HTTP3 == nil {
 		return nil, nil
 	}
 
-	conn, err := net.ListenPacket("udp", configuration.GetAddress())
+	if configuration.IsUnixSocket() {
+		return nil, errors.New("HTTP/3 is not supported on unix socket entry points")
+	}
+
+	conn, err := listenHTTP3(name, configuration)
 	if err != nil {
 		return nil, fmt.Errorf("error while starting http3 listener: %w", err)
 	}
@@ -70,6 +74,16 @@ func newHTTP3Server(ctx context.Context, configuration *static.EntryPoint, https
 	return h3, nil
 }
 
+// listenHTTP3 returns the datagram socket passed by socket activation for the entry point, if any,
+// or else listens on the address of the entry point.
+func listenHTTP3(name string, configuration *static.EntryPoint) (net.PacketConn, error) {
+	if conn, ok := inheritedSockets.packetConn(name); ok {
+		return conn, nil
+	}
+
+	return net.ListenPacket("udp", configuration.GetAddress())
+}
+
 // TODO: rewrite if at some point `port` become an exported field of http3.Server.
 func getQuicHeadersSetter(configuration *static.EntryPoint) func(header http.Header) error {
 	advertisedAddress := configuration.GetAddress()
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/proxyprotocol"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	"golang.org/x/time/rate"
+)
+
+// Reasons for which a connection is refused, as reported by the refused connections metric.
+const (
+	refusedMaxConnections      = "max_connections"
+	refusedMaxConnectionsPerIP = "max_connections_per_ip"
+	refusedAcceptRate          = "accept_rate"
+)
+
+// connectionLimiter enforces the connection limits of an entry point.
+// The accept rate and the maximum number of connections are checked as soon as a connection is accepted,
+// while the maximum number of connections per IP is checked once the client address is known,
+// as it may require to read the Proxy-Protocol header.
+type connectionLimiter struct {
+	entryPointName string
+	maxConns       int64
+	maxConnsPerIP  int64
+	rateLimiter    *rate.Limiter
+	refusedCounter gokitmetrics.Counter
+
+	lock      sync.Mutex
+	conns     int64
+	connsByIP map[string]int64
+}
+
+func newConnectionLimiter(entryPointName string, config *static.ConnectionLimits, metricsRegistry metrics.Registry) (*connectionLimiter, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	if config.MaxConnections < 0 || config.MaxConnectionsPerIP < 0 {
+		return nil, fmt.Errorf("negative value not valid for the maximum number of connections")
+	}
+
+	limiter := &connectionLimiter{
+		entryPointName: entryPointName,
+		maxConns:       config.MaxConnections,
+		maxConnsPerIP:  config.MaxConnectionsPerIP,
+		refusedCounter: metricsRegistry.EntryPointConnsRefusedCounter(),
+		connsByIP:      make(map[string]int64),
+	}
+
+	if config.AcceptRate != nil && config.AcceptRate.Average > 0 {
+		period := time.Duration(config.AcceptRate.Period)
+		if period < 0 {
+			return nil, fmt.Errorf("negative value not valid for the accept rate period: %v", period)
+		}
+		if period == 0 {
+			period = time.Second
+		}
+
+		burst := config.AcceptRate.Burst
+		if burst < 1 {
+			burst = 1
+		}
+
+		limit := float64(config.AcceptRate.Average*int64(time.Second)) / float64(period)
+		limiter.rateLimiter = rate.NewLimiter(rate.Limit(limit), int(burst))
+	}
+
+	return limiter, nil
+}
+
+// accept reserves a slot for a newly accepted connection,
+// and reports whether the connection is allowed by the accept rate and the maximum number of connections.
+func (l *connectionLimiter) accept() bool {
+	if l.rateLimiter != nil && !l.rateLimiter.Allow() {
+		l.refuse(refusedAcceptRate)
+		return false
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.maxConns > 0 && l.conns >= l.maxConns {
+		l.refuse(refusedMaxConnections)
+		return false
+	}
+
+	l.conns++
+	return true
+}
+
+// limit checks the maximum number of connections of the client IP of the given connection, previously accepted.
+// It returns the connection releasing its slots once closed, or closes the connection if it is refused.
+func (l *connectionLimiter) limit(conn tcp.WriteCloser) (tcp.WriteCloser, bool) {
+	ip := clientIP(conn.RemoteAddr())
+
+	l.lock.Lock()
+
+	if l.maxConnsPerIP > 0 && ip != "" && l.connsByIP[ip] >= l.maxConnsPerIP {
+		l.conns--
+		l.lock.Unlock()
+
+		l.refuse(refusedMaxConnectionsPerIP)
+
+		_ = conn.Close()
+		return nil, false
+	}
+
+	if ip != "" {
+		l.connsByIP[ip]++
+	}
+
+	l.lock.Unlock()
+
+	return &limitedConnection{WriteCloser: conn, release: func() { l.release(ip) }}, true
+}
+
+// release frees the slots of a closed connection.
+func (l *connectionLimiter) release(ip string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.conns--
+
+	if ip == "" {
+		return
+	}
+
+	l.connsByIP[ip]--
+	if l.connsByIP[ip] <= 0 {
+		delete(l.connsByIP, ip)
+	}
+}
+
+func (l *connectionLimiter) refuse(reason string) {
+	log.WithoutContext().WithField(log.EntryPointName, l.entryPointName).
+		Debugf("Connection refused by the connection limits: %s", reason)
+
+	l.refusedCounter.With("entrypoint", l.entryPointName, "reason", reason).Add(1)
+}
+
+// clientIP returns the IP of the given client address, or an empty string for the clients without IP,
+// such as the peers of a unix socket.
+func clientIP(addr net.Addr) string {
+	tcpAddr, ok := proxyprotocol.UnwrapAddr(addr).(*net.TCPAddr)
+	if !ok {
+		return ""
+	}
+
+	return tcpAddr.IP.String()
+}
+
+// limitedConnection releases the slots of the connection in the connection limiter once closed.
+type limitedConnection struct {
+	tcp.WriteCloser
+
+	once    sync.Once
+	release func()
+}
+
+func (c *limitedConnection) Close() error {
+	c.once.Do(c.release)
+	return c.WriteCloser.Close()
+}
@@ -0,0 +1,191 @@
+package server
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+func TestConnectionLimiter(t *testing.T) {
+	clientA := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 40000}
+	clientB := &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 40000}
+	unixClient := &net.UnixAddr{Net: "unix"}
+
+	testCases := []struct {
+		desc             string
+		config           *static.ConnectionLimits
+		clients          []net.Addr
+		expectedAccepted []bool
+		expectedRefused  map[string]float64
+	}{
+		{
+			desc:             "without limits",
+			config:           &static.ConnectionLimits{},
+			clients:          []net.Addr{clientA, clientA, clientB},
+			expectedAccepted: []bool{true, true, true},
+		},
+		{
+			desc:             "max connections",
+			config:           &static.ConnectionLimits{MaxConnections: 2},
+			clients:          []net.Addr{clientA, clientB, clientA},
+			expectedAccepted: []bool{true, true, false},
+			expectedRefused:  map[string]float64{refusedMaxConnections: 1},
+		},
+		{
+			desc:             "max connections per IP",
+			config:           &static.ConnectionLimits{MaxConnectionsPerIP: 1},
+			clients:          []net.Addr{clientA, clientA, clientB, clientB},
+			expectedAccepted: []bool{true, false, true, false},
+			expectedRefused:  map[string]float64{refusedMaxConnectionsPerIP: 2},
+		},
+		{
+			desc:             "max connections per IP does not apply to unix socket peers",
+			config:           &static.ConnectionLimits{MaxConnectionsPerIP: 1},
+			clients:          []net.Addr{unixClient, unixClient},
+			expectedAccepted: []bool{true, true},
+		},
+		{
+			desc:             "max connections per IP refusals free the connection slots",
+			config:           &static.ConnectionLimits{MaxConnections: 2, MaxConnectionsPerIP: 1},
+			clients:          []net.Addr{clientA, clientA, clientA, clientB},
+			expectedAccepted: []bool{true, false, false, true},
+			expectedRefused:  map[string]float64{refusedMaxConnectionsPerIP: 2},
+		},
+		{
+			desc: "accept rate",
+			config: &static.ConnectionLimits{AcceptRate: &static.AcceptRate{
+				Average: 1,
+				Period:  ptypes.Duration(time.Hour),
+				Burst:   2,
+			}},
+			clients:          []net.Addr{clientA, clientB, clientA},
+			expectedAccepted: []bool{true, true, false},
+			expectedRefused:  map[string]float64{refusedAcceptRate: 1},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			counter := newRefusedConnsCounter()
+
+			limiter, err := newConnectionLimiter("web", test.config, metrics.NewVoidRegistry())
+			require.NoError(t, err)
+			limiter.refusedCounter = counter
+
+			var accepted []bool
+			for _, client := range test.clients {
+				conn := &limitTestConn{remoteAddr: client}
+
+				if !limiter.accept() {
+					accepted = append(accepted, false)
+					continue
+				}
+
+				_, ok := limiter.limit(conn)
+				assert.Equal(t, !ok, conn.closed)
+
+				accepted = append(accepted, ok)
+			}
+
+			assert.Equal(t, test.expectedAccepted, accepted)
+
+			if test.expectedRefused == nil {
+				test.expectedRefused = map[string]float64{}
+			}
+			assert.Equal(t, test.expectedRefused, counter.counts)
+		})
+	}
+}
+
+func TestConnectionLimiter_release(t *testing.T) {
+	client := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 40000}
+
+	limiter, err := newConnectionLimiter("web", &static.ConnectionLimits{MaxConnections: 1, MaxConnectionsPerIP: 1}, metrics.NewVoidRegistry())
+	require.NoError(t, err)
+
+	require.True(t, limiter.accept())
+	conn, ok := limiter.limit(&limitTestConn{remoteAddr: client})
+	require.True(t, ok)
+
+	assert.False(t, limiter.accept())
+
+	require.NoError(t, conn.Close())
+	// Closing the connection again must not release its slots twice.
+	require.NoError(t, conn.Close())
+
+	assert.Equal(t, int64(0), limiter.conns)
+	assert.Empty(t, limiter.connsByIP)
+
+	require.True(t, limiter.accept())
+	_, ok = limiter.limit(&limitTestConn{remoteAddr: client})
+	assert.True(t, ok)
+
+	assert.False(t, limiter.accept())
+}
+
+func TestNewConnectionLimiter_invalid(t *testing.T) {
+	_, err := newConnectionLimiter("web", &static.ConnectionLimits{MaxConnections: -1}, metrics.NewVoidRegistry())
+	assert.Error(t, err)
+
+	_, err = newConnectionLimiter("web", &static.ConnectionLimits{AcceptRate: &static.AcceptRate{Average: 1, Period: -1}}, metrics.NewVoidRegistry())
+	assert.Error(t, err)
+}
+
+type limitTestConn struct {
+	net.Conn
+
+	remoteAddr net.Addr
+	closed     bool
+}
+
+func (c *limitTestConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+func (c *limitTestConn) CloseWrite() error {
+	return nil
+}
+
+func (c *limitTestConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+// refusedConnsCounter counts the refused connections by reason.
+type refusedConnsCounter struct {
+	lock   *sync.Mutex
+	counts map[string]float64
+	reason string
+}
+
+func newRefusedConnsCounter() *refusedConnsCounter {
+	return &refusedConnsCounter{lock: &sync.Mutex{}, counts: make(map[string]float64)}
+}
+
+func (c *refusedConnsCounter) With(labelValues ...string) gokitmetrics.Counter {
+	counter := *c
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		if labelValues[i] == "reason" {
+			counter.reason = labelValues[i+1]
+		}
+	}
+	return &counter
+}
+
+func (c *refusedConnsCounter) Add(delta float64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.counts[c.reason] += delta
+}
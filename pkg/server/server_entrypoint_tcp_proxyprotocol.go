@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/proxyprotocol"
+)
+
+// proxyProtocolConn is a connection accepted through the Proxy Protocol.
+// Its remote address carries the TLVs of the Proxy Protocol header of the client, if any.
+type proxyProtocolConn struct {
+	writeCloserWrapper
+
+	proxyConn *proxyproto.Conn
+
+	once       sync.Once
+	remoteAddr net.Addr
+}
+
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	c.once.Do(func() {
+		c.remoteAddr = c.proxyConn.RemoteAddr()
+
+		header := c.proxyConn.ProxyHeader()
+		if header == nil {
+			return
+		}
+
+		tlvs, err := header.TLVs()
+		if err != nil {
+			log.WithoutContext().Debugf("Error while parsing the Proxy Protocol TLVs of %s: %v", c.remoteAddr, err)
+			return
+		}
+
+		if len(tlvs) > 0 {
+			c.remoteAddr = &proxyprotocol.Addr{Addr: c.remoteAddr, TLVs: tlvs}
+		}
+	})
+
+	return c.remoteAddr
+}
+
+// proxyProtocolConnContext adds the Proxy Protocol TLVs of the client of the connection to the context of its requests.
+func proxyProtocolConnContext(ctx context.Context, conn net.Conn) context.Context {
+	if tlvs := proxyprotocol.GetTLVs(conn.RemoteAddr()); len(tlvs) > 0 {
+		return proxyprotocol.WithTLVs(ctx, tlvs)
+	}
+	return ctx
+}
+
+// proxyProtocolHeaders sets request headers to the values of the Proxy Protocol TLVs of the client.
+// The headers sent by the client are always removed, so that they cannot be forged.
+type proxyProtocolHeaders struct {
+	next    http.Handler
+	headers map[string]string
+}
+
+func newProxyProtocolHeaders(next http.Handler, headers map[string]string) (http.Handler, error) {
+	for name, key := range headers {
+		if err := proxyprotocol.ValidateKey(key); err != nil {
+			return nil, fmt.Errorf("header %s: %w", name, err)
+		}
+	}
+
+	return &proxyProtocolHeaders{next: next, headers: headers}, nil
+}
+
+func (p *proxyProtocolHeaders) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	tlvs := proxyprotocol.TLVsFromContext(req.Context())
+
+	for name, key := range p.headers {
+		req.Header.Del(name)
+
+		if value, ok := proxyprotocol.Lookup(tlvs, key); ok {
+			req.Header.Set(name, value)
+		}
+	}
+
+	p.next.ServeHTTP(rw, req)
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/proxyprotocol"
+)
+
+func TestProxyProtocolHeaders(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		tlvs            []proxyproto.TLV
+		requestHeaders  map[string]string
+		expectedHeaders map[string]string
+	}{
+		{
+			desc: "TLVs of the client",
+			tlvs: []proxyproto.TLV{
+				{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("example.com")},
+				{Type: 0xEA, Value: append([]byte{0x01}, "vpce-123"...)},
+			},
+			expectedHeaders: map[string]string{
+				"X-Authority": "example.com",
+				"X-Vpce-Id":   "vpce-123",
+			},
+		},
+		{
+			desc:           "headers sent by the client are removed",
+			tlvs:           []proxyproto.TLV{{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("example.com")}},
+			requestHeaders: map[string]string{"X-Vpce-Id": "forged"},
+			expectedHeaders: map[string]string{
+				"X-Authority": "example.com",
+				"X-Vpce-Id":   "",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			headers := make(map[string]string)
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				for name := range test.expectedHeaders {
+					headers[name] = req.Header.Get(name)
+				}
+			})
+
+			handler, err := newProxyProtocolHeaders(next, map[string]string{
+				"X-Authority": "authority",
+				"X-Vpce-Id":   "aws_vpce_id",
+			})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+			req = req.WithContext(proxyprotocol.WithTLVs(req.Context(), test.tlvs))
+			for name, value := range test.requestHeaders {
+				req.Header.Set(name, value)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.expectedHeaders, headers)
+		})
+	}
+}
+
+func TestNewProxyProtocolHeaders_invalid(t *testing.T) {
+	_, err := newProxyProtocolHeaders(http.NotFoundHandler(), map[string]string{"X-Foo": "foo"})
+	assert.Error(t, err)
+}
@@ -28,7 +28,7 @@ func NewUDPEntryPoints(cfg static.EntryPoints) (UDPEntryPoints, error) {
 			continue
 		}
 
-		ep, err := NewUDPEntryPoint(entryPoint)
+		ep, err := NewUDPEntryPoint(entryPointName, entryPoint)
 		if err != nil {
 			return nil, fmt.Errorf("error while building entryPoint %s: %w", entryPointName, err)
 		}
@@ -84,7 +84,17 @@ type UDPEntryPoint struct {
 }
 
 // NewUDPEntryPoint returns a UDP entry point.
-func NewUDPEntryPoint(cfg *static.EntryPoint) (*UDPEntryPoint, error) {
+// It uses the socket passed by socket activation for the entry point name, if any.
+func NewUDPEntryPoint(name string, cfg *static.EntryPoint) (*UDPEntryPoint, error) {
+	if packetConn, ok := inheritedSockets.packetConn(name); ok {
+		listener, err := udp.ListenPacketConn(packetConn, time.Duration(cfg.UDP.Timeout))
+		if err != nil {
+			return nil, err
+		}
+
+		return &UDPEntryPoint{listener: listener, switcher: &udp.HandlerSwitcher{}, transportConfiguration: cfg.Transport}, nil
+	}
+
 	addr, err := net.ResolveUDPAddr("udp", cfg.GetAddress())
 	if err != nil {
 		return nil, err
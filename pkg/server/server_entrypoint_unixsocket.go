@@ -0,0 +1,132 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"strconv"
+
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+// buildUnixSocketListener listens on the unix socket at the given path,
+// and applies the file mode and owner of the configuration.
+func buildUnixSocketListener(path string, config *static.UnixSocketConfig) (net.Listener, error) {
+	if err := removeStaleUnixSocket(path); err != nil {
+		return nil, err
+	}
+
+	// When a file mode is configured, the socket is only made accessible once it is applied.
+	var listener net.Listener
+	var err error
+	if config != nil && config.Mode != "" {
+		listener, err = listenUnixSocketPrivate(path)
+	} else {
+		listener, err = net.Listen("unix", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if config == nil {
+		return listener, nil
+	}
+
+	if err := configureUnixSocket(path, config); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("error configuring unix socket %s: %w", path, err)
+	}
+
+	return listener, nil
+}
+
+// removeStaleUnixSocket removes the socket file left at the given path by a previous instance,
+// which would otherwise prevent to listen on it.
+func removeStaleUnixSocket(path string) error {
+	fileInfo, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	if fileInfo.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s already exists and is not a unix socket", path)
+	}
+
+	// A socket accepting connections is still in use.
+	if conn, err := net.Dial("unix", path); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("unix socket %s is already in use", path)
+	}
+
+	return os.Remove(path)
+}
+
+func configureUnixSocket(path string, config *static.UnixSocketConfig) error {
+	if config.Owner != "" || config.Group != "" {
+		uid, gid := -1, -1
+
+		if config.Owner != "" {
+			var err error
+			uid, err = lookupUserID(config.Owner)
+			if err != nil {
+				return err
+			}
+		}
+
+		if config.Group != "" {
+			var err error
+			gid, err = lookupGroupID(config.Group)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := os.Chown(path, uid, gid); err != nil {
+			return err
+		}
+	}
+
+	if config.Mode != "" {
+		mode, err := strconv.ParseUint(config.Mode, 8, 32)
+		if err != nil || mode > 0o777 {
+			return fmt.Errorf("invalid file mode %q", config.Mode)
+		}
+
+		if err := os.Chmod(path, os.FileMode(mode)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func lookupUserID(name string) (int, error) {
+	if uid, err := strconv.Atoi(name); err == nil {
+		return uid, nil
+	}
+
+	u, err := user.Lookup(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(u.Uid)
+}
+
+func lookupGroupID(name string) (int, error) {
+	if gid, err := strconv.Atoi(name); err == nil {
+		return gid, nil
+	}
+
+	g, err := user.LookupGroup(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(g.Gid)
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestBuildUnixSocketListener(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes of unix sockets are not supported on windows")
+	}
+
+	testCases := []struct {
+		desc         string
+		setup        func(t *testing.T, path string)
+		config       *static.UnixSocketConfig
+		expectedMode os.FileMode
+		expectedErr  bool
+	}{
+		{
+			desc: "without configuration",
+		},
+		{
+			desc:         "with file mode",
+			config:       &static.UnixSocketConfig{Mode: "0600"},
+			expectedMode: 0o600,
+		},
+		{
+			desc:         "with owner and group IDs",
+			config:       &static.UnixSocketConfig{Owner: "-1", Group: "-1", Mode: "0660"},
+			expectedMode: 0o660,
+		},
+		{
+			desc:        "with invalid file mode",
+			config:      &static.UnixSocketConfig{Mode: "rw-rw----"},
+			expectedErr: true,
+		},
+		{
+			desc: "with a stale socket",
+			setup: func(t *testing.T, path string) {
+				t.Helper()
+
+				listener, err := net.Listen("unix", path)
+				require.NoError(t, err)
+
+				// Leaves the socket file behind, as a crashed instance would.
+				listener.(*net.UnixListener).SetUnlinkOnClose(false)
+				require.NoError(t, listener.Close())
+			},
+		},
+		{
+			desc: "with a socket in use",
+			setup: func(t *testing.T, path string) {
+				t.Helper()
+
+				listener, err := net.Listen("unix", path)
+				require.NoError(t, err)
+				t.Cleanup(func() { _ = listener.Close() })
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "with a regular file",
+			setup: func(t *testing.T, path string) {
+				t.Helper()
+
+				require.NoError(t, os.WriteFile(path, []byte("data"), 0o600))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "traefik.sock")
+
+			if test.setup != nil {
+				test.setup(t, path)
+			}
+
+			listener, err := buildUnixSocketListener(path, test.config)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = listener.Close() })
+
+			fileInfo, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.NotZero(t, fileInfo.Mode()&os.ModeSocket)
+
+			if test.expectedMode != 0 {
+				assert.Equal(t, test.expectedMode, fileInfo.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestListenUnixSocketPrivate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes of unix sockets are not supported on windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "traefik.sock")
+
+	listener, err := listenUnixSocketPrivate(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	fileInfo, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), fileInfo.Mode().Perm())
+}
+
+func TestUnixSocketEntryPoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traefik.sock")
+
+	epConfig := &static.EntryPointsTransport{}
+	epConfig.SetDefaults()
+
+	entryPoint, err := NewTCPEntryPoint(context.Background(), "", &static.EntryPoint{
+		Address:          static.UnixSocketPrefix + path,
+		Transport:        epConfig,
+		ForwardedHeaders: &static.ForwardedHeaders{},
+	}, metrics.NewVoidRegistry())
+	require.NoError(t, err)
+	t.Cleanup(func() { entryPoint.Shutdown(context.Background()) })
+
+	router := &tcp.Router{}
+	router.HTTPHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+
+	go entryPoint.Start(context.Background())
+	entryPoint.SwitchRouter(router)
+
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	request, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	require.NoError(t, err)
+	require.NoError(t, request.Write(conn))
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), request)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
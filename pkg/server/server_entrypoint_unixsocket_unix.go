@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package server
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenUnixSocketPrivate listens on the unix socket at the given path, created without any permission for the group and the others,
+// so that no one can connect to it before its file mode and owner are configured.
+func listenUnixSocketPrivate(path string) (net.Listener, error) {
+	// The umask applies to the whole process, and is only changed while the socket is created.
+	umask := syscall.Umask(0o177)
+	defer syscall.Umask(umask)
+
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,12 @@
+//go:build windows
+// +build windows
+
+package server
+
+import "net"
+
+// listenUnixSocketPrivate listens on the unix socket at the given path.
+// The file modes of unix sockets are not supported on Windows.
+func listenUnixSocketPrivate(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
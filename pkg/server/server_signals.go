@@ -12,15 +12,36 @@ import (
 )
 
 func (s *Server) configureSignals() {
-	signal.Notify(s.signals, syscall.SIGUSR1)
+	signal.Notify(s.signals, syscall.SIGUSR1, syscall.SIGUSR2)
 }
 
 func (s *Server) listenSignals(ctx context.Context) {
+	var upgraded bool
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case sig := <-s.signals:
+			if sig == syscall.SIGUSR2 {
+				if upgraded {
+					log.WithoutContext().Warnf("Ignoring %+v: the server is already stopping after an upgrade", sig)
+					continue
+				}
+
+				log.WithoutContext().Infof("Upgrading: starting a new process with the entry points sockets: %+v", sig)
+
+				if err := s.upgrade(); err != nil {
+					log.WithoutContext().Errorf("Error while upgrading: %v", err)
+					continue
+				}
+
+				upgraded = true
+
+				log.WithoutContext().Info("Upgrade done, stopping gracefully")
+				s.cancel()
+			}
+
 			if sig == syscall.SIGUSR1 {
 				log.WithoutContext().Infof("Closing and re-opening log files for rotation: %+v", sig)
 
@@ -0,0 +1,219 @@
+//go:build !windows
+// +build !windows
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/coreos/go-systemd/daemon"
+	"github.com/pires/go-proxyproto"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// upgradeEnv is the environment variable holding the names of the sockets passed to the upgraded process.
+const upgradeEnv = "TRAEFIK_UPGRADE_FDNAMES"
+
+// upgradeReadyFD is the file descriptor of the pipe on which the upgraded process notifies it is ready.
+// The sockets are passed as the following file descriptors, in the order of their names.
+const upgradeReadyFD = 3
+
+// upgradeReadyTimeout is the maximum duration to wait for the upgraded process to be ready.
+const upgradeReadyTimeout = time.Minute
+
+// upgradeFiles holds the files passed by the parent process, when the process is started by an upgrade.
+var upgradeFiles struct {
+	once    sync.Once
+	ready   *os.File
+	sockets []*os.File
+}
+
+func loadUpgradeFiles() {
+	upgradeFiles.once.Do(func() {
+		names, ok := os.LookupEnv(upgradeEnv)
+		if !ok {
+			return
+		}
+
+		// The files must not be passed to the processes started by this one.
+		_ = os.Unsetenv(upgradeEnv)
+
+		syscall.CloseOnExec(upgradeReadyFD)
+		upgradeFiles.ready = os.NewFile(upgradeReadyFD, "upgrade-ready")
+
+		if names == "" {
+			return
+		}
+
+		for i, name := range strings.Split(names, ":") {
+			fd := upgradeReadyFD + 1 + i
+			syscall.CloseOnExec(fd)
+			upgradeFiles.sockets = append(upgradeFiles.sockets, os.NewFile(uintptr(fd), name))
+		}
+	})
+}
+
+// notifyUpgradeReady notifies the parent process that the upgraded process is ready,
+// when the process is started by an upgrade.
+func notifyUpgradeReady() {
+	loadUpgradeFiles()
+
+	if upgradeFiles.ready == nil {
+		return
+	}
+
+	if _, err := upgradeFiles.ready.Write([]byte{1}); err != nil {
+		log.WithoutContext().Errorf("Error while notifying the parent process of the upgrade: %v", err)
+	}
+
+	_ = upgradeFiles.ready.Close()
+	upgradeFiles.ready = nil
+}
+
+// upgrade starts a new process from the current executable, handing over the sockets of the entry points to it,
+// and waits for the new process to be ready.
+func (s *Server) upgrade() error {
+	var names []string
+	var files []*os.File
+
+	defer func() {
+		for _, file := range files {
+			_ = file.Close()
+		}
+	}()
+
+	for name, entryPoint := range s.tcpEntryPoints {
+		file, err := listenerFile(entryPoint.listener)
+		if err != nil {
+			return fmt.Errorf("entry point %s: %w", name, err)
+		}
+
+		names = append(names, name)
+		files = append(files, file)
+
+		if entryPoint.http3Server != nil {
+			file, err := packetConnFile(entryPoint.http3Server.http3conn)
+			if err != nil {
+				return fmt.Errorf("entry point %s: %w", name, err)
+			}
+
+			names = append(names, name)
+			files = append(files, file)
+		}
+	}
+
+	for name, entryPoint := range s.udpEntryPoints {
+		file, err := entryPoint.listener.File()
+		if err != nil {
+			return fmt.Errorf("entry point %s: %w", name, err)
+		}
+
+		names = append(names, name)
+		files = append(files, file)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	readyReader, readyWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = readyReader.Close() }()
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), upgradeEnv+"="+strings.Join(names, ":"))
+	cmd.ExtraFiles = append([]*os.File{readyWriter}, files...)
+
+	err = cmd.Start()
+	_ = readyWriter.Close()
+	if err != nil {
+		return fmt.Errorf("error while starting the new process: %w", err)
+	}
+
+	pid := cmd.Process.Pid
+
+	if err := waitUpgradeReady(readyReader); err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return fmt.Errorf("the new process %d is not ready: %w", pid, err)
+	}
+
+	_ = cmd.Process.Release()
+
+	log.WithoutContext().Infof("The new process %d is ready", pid)
+
+	// The unix sockets are only kept once the new process took them over,
+	// so that they are still removed when this process stops after a failed upgrade.
+	for _, entryPoint := range s.tcpEntryPoints {
+		keepUnixSocket(entryPoint.listener)
+	}
+
+	// Lets systemd track the new process as the main process of the service.
+	if _, err := daemon.SdNotify(false, fmt.Sprintf("MAINPID=%d", pid)); err != nil {
+		log.WithoutContext().Errorf("Failed to notify the new main process: %v", err)
+	}
+
+	return nil
+}
+
+// waitUpgradeReady waits for the new process to write on the ready pipe.
+// The pipe is closed without being written on if the new process exits.
+func waitUpgradeReady(ready *os.File) error {
+	if err := ready.SetReadDeadline(time.Now().Add(upgradeReadyTimeout)); err != nil {
+		return err
+	}
+
+	_, err := ready.Read(make([]byte, 1))
+	return err
+}
+
+// listenerFile returns a copy of the socket file of the given listener.
+func listenerFile(listener net.Listener) (*os.File, error) {
+	switch l := listener.(type) {
+	case *proxyproto.Listener:
+		return listenerFile(l.Listener)
+	case tcpKeepAliveListener:
+		return l.File()
+	case *net.TCPListener:
+		return l.File()
+	case *net.UnixListener:
+		return l.File()
+	default:
+		return nil, fmt.Errorf("unsupported listener type %T", listener)
+	}
+}
+
+// keepUnixSocket keeps the socket of the given listener, if it is a unix socket,
+// so that it stays reachable for the new process once the listener is closed.
+func keepUnixSocket(listener net.Listener) {
+	switch l := listener.(type) {
+	case *proxyproto.Listener:
+		keepUnixSocket(l.Listener)
+	case *net.UnixListener:
+		l.SetUnlinkOnClose(false)
+	}
+}
+
+// packetConnFile returns a copy of the socket file of the given packet connection.
+func packetConnFile(conn net.PacketConn) (*os.File, error) {
+	fileConn, ok := conn.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, errors.New("unsupported packet connection type")
+	}
+
+	return fileConn.File()
+}
@@ -0,0 +1,121 @@
+//go:build !windows
+// +build !windows
+
+package server
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitUpgradeReady(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		ready       bool
+		expectedErr error
+	}{
+		{
+			desc:  "new process ready",
+			ready: true,
+		},
+		{
+			desc:        "new process exited",
+			expectedErr: io.EOF,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			reader, writer, err := os.Pipe()
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = reader.Close() })
+
+			if test.ready {
+				_, err = writer.Write([]byte{1})
+				require.NoError(t, err)
+			}
+			require.NoError(t, writer.Close())
+
+			err = waitUpgradeReady(reader)
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestListenerFile(t *testing.T) {
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = tcpListener.Close() })
+
+	socket := filepath.Join(t.TempDir(), "traefik.sock")
+	unixListener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		listener net.Listener
+		expected net.Listener
+	}{
+		{
+			desc:     "TCP listener",
+			listener: tcpKeepAliveListener{tcpListener.(*net.TCPListener)},
+			expected: tcpListener,
+		},
+		{
+			desc:     "Proxy Protocol listener",
+			listener: &proxyproto.Listener{Listener: tcpKeepAliveListener{tcpListener.(*net.TCPListener)}},
+			expected: tcpListener,
+		},
+		{
+			desc:     "unix socket listener",
+			listener: unixListener,
+			expected: unixListener,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			file, err := listenerFile(test.listener)
+			require.NoError(t, err)
+
+			listener, err := net.FileListener(file)
+			require.NoError(t, err)
+			require.NoError(t, file.Close())
+			t.Cleanup(func() { _ = listener.Close() })
+
+			assert.Equal(t, test.expected.Addr().String(), listener.Addr().String())
+		})
+	}
+
+	// The unix socket is only kept once the new process is ready.
+	require.NoError(t, unixListener.Close())
+	_, err = os.Stat(socket)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestKeepUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "traefik.sock")
+	unixListener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	keepUnixSocket(&proxyproto.Listener{Listener: unixListener})
+
+	require.NoError(t, unixListener.Close())
+	_, err = os.Stat(socket)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,7 @@
+//go:build windows
+// +build windows
+
+package server
+
+// notifyUpgradeReady does nothing, as upgrades are not supported on Windows.
+func notifyUpgradeReady() {}
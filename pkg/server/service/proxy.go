@@ -60,6 +60,11 @@ func buildProxy(passHostHeader *bool, responseForwarding *dynamic.ResponseForwar
 			// Do not pass client Host header unless optsetter PassHostHeader is set.
 			if passHostHeader != nil && !*passHostHeader {
 				outReq.Host = outReq.URL.Host
+
+				// The host of a unix socket server only makes sense to Traefik.
+				if _, ok := unixSocketPath(outReq.Host); ok {
+					outReq.Host = "localhost"
+				}
 			}
 
 			// Even if the websocket RFC says that headers should be case-insensitive,
@@ -13,6 +13,7 @@ import (
 
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/spiffe"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
 	"golang.org/x/net/http2"
 )
@@ -27,10 +28,12 @@ func (t *h2cTransportWrapper) RoundTrip(req *http.Request) (*http.Response, erro
 }
 
 // NewRoundTripperManager creates a new RoundTripperManager.
-func NewRoundTripperManager() *RoundTripperManager {
+// The SPIFFE X.509 source is optional, and only required by the transports enabling SPIFFE.
+func NewRoundTripperManager(spiffeX509Source spiffe.Source) *RoundTripperManager {
 	return &RoundTripperManager{
-		roundTrippers: make(map[string]http.RoundTripper),
-		configs:       make(map[string]*dynamic.ServersTransport),
+		roundTrippers:    make(map[string]http.RoundTripper),
+		configs:          make(map[string]*dynamic.ServersTransport),
+		spiffeX509Source: spiffeX509Source,
 	}
 }
 
@@ -39,6 +42,8 @@ type RoundTripperManager struct {
 	rtLock        sync.RWMutex
 	roundTrippers map[string]http.RoundTripper
 	configs       map[string]*dynamic.ServersTransport
+
+	spiffeX509Source spiffe.Source
 }
 
 // Update updates the roundtrippers configurations.
@@ -58,12 +63,7 @@ func (r *RoundTripperManager) Update(newConfigs map[string]*dynamic.ServersTrans
 			continue
 		}
 
-		var err error
-		r.roundTrippers[configName], err = createRoundTripper(newConfig)
-		if err != nil {
-			log.WithoutContext().Errorf("Could not configure HTTP Transport %s, fallback on default transport: %v", configName, err)
-			r.roundTrippers[configName] = http.DefaultTransport
-		}
+		r.roundTrippers[configName] = r.buildRoundTripper(configName, newConfig)
 	}
 
 	for newConfigName, newConfig := range newConfigs {
@@ -71,17 +71,39 @@ func (r *RoundTripperManager) Update(newConfigs map[string]*dynamic.ServersTrans
 			continue
 		}
 
-		var err error
-		r.roundTrippers[newConfigName], err = createRoundTripper(newConfig)
-		if err != nil {
-			log.WithoutContext().Errorf("Could not configure HTTP Transport %s, fallback on default transport: %v", newConfigName, err)
-			r.roundTrippers[newConfigName] = http.DefaultTransport
-		}
+		r.roundTrippers[newConfigName] = r.buildRoundTripper(newConfigName, newConfig)
 	}
 
 	r.configs = newConfigs
 }
 
+// buildRoundTripper creates the roundtripper of the given transport configuration.
+// When the configuration is invalid, the default transport is used instead,
+// unless SPIFFE is enabled: the requests then fail, rather than being sent without the SPIFFE mTLS.
+func (r *RoundTripperManager) buildRoundTripper(name string, config *dynamic.ServersTransport) http.RoundTripper {
+	rt, err := r.createRoundTripper(config)
+	if err == nil {
+		return rt
+	}
+
+	if config != nil && config.Spiffe != nil {
+		log.WithoutContext().Errorf("Could not configure HTTP Transport %s, the requests using it will fail: %v", name, err)
+		return errorRoundTripper{err: fmt.Errorf("invalid servers transport %s: %w", name, err)}
+	}
+
+	log.WithoutContext().Errorf("Could not configure HTTP Transport %s, fallback on default transport: %v", name, err)
+	return http.DefaultTransport
+}
+
+// errorRoundTripper is a roundtripper failing all the requests with its error.
+type errorRoundTripper struct {
+	err error
+}
+
+func (e errorRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, e.err
+}
+
 // Get get a roundtripper by name.
 func (r *RoundTripperManager) Get(name string) (http.RoundTripper, error) {
 	if len(name) == 0 {
@@ -102,7 +124,7 @@ func (r *RoundTripperManager) Get(name string) (http.RoundTripper, error) {
 // For the settings that can't be configured in Traefik it uses the default http.Transport settings.
 // An exception to this is the MaxIdleConns setting as we only provide the option MaxIdleConnsPerHost in Traefik at this point in time.
 // Setting this value to the default of 100 could lead to confusing behavior and backwards compatibility issues.
-func createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error) {
+func (r *RoundTripperManager) createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error) {
 	if cfg == nil {
 		return nil, errors.New("no transport configuration given")
 	}
@@ -117,8 +139,8 @@ func createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error
 	}
 
 	transport := &http.Transport{
-		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           dialer.DialContext,
+		Proxy:                 unixSocketProxy(http.ProxyFromEnvironment),
+		DialContext:           unixSocketDialContext(dialer.DialContext),
 		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
@@ -147,6 +169,21 @@ func createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error
 		}
 	}
 
+	// The SPIFFE configuration replaces the TLS configuration,
+	// as the certificates and the trusted CAs are the ones provided by the SPIFFE Workload API.
+	if cfg.Spiffe != nil {
+		if r.spiffeX509Source == nil {
+			return nil, errors.New("SPIFFE is enabled for this transport, but the SPIFFE Workload API is not configured")
+		}
+
+		authorize, err := spiffe.NewAuthorizer(cfg.Spiffe.IDs, cfg.Spiffe.TrustDomain)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SPIFFE configuration: %w", err)
+		}
+
+		transport.TLSClientConfig = spiffe.TLSClientConfig(r.spiffeX509Source, authorize)
+	}
+
 	// Return directly HTTP/1.1 transport when HTTP/2 is disabled
 	if cfg.DisableHTTP2 {
 		return transport, nil
@@ -129,7 +129,7 @@ func TestKeepConnectionWhenSameConfiguration(t *testing.T) {
 	srv.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
 	srv.StartTLS()
 
-	rtManager := NewRoundTripperManager()
+	rtManager := NewRoundTripperManager(nil)
 
 	dynamicConf := map[string]*dynamic.ServersTransport{
 		"test": {
@@ -197,7 +197,7 @@ func TestMTLS(t *testing.T) {
 	}
 	srv.StartTLS()
 
-	rtManager := NewRoundTripperManager()
+	rtManager := NewRoundTripperManager(nil)
 
 	dynamicConf := map[string]*dynamic.ServersTransport{
 		"test": {
@@ -269,7 +269,7 @@ func TestDisableHTTP2(t *testing.T) {
 			srv.EnableHTTP2 = test.serverHTTP2
 			srv.StartTLS()
 
-			rtManager := NewRoundTripperManager()
+			rtManager := NewRoundTripperManager(nil)
 
 			dynamicConf := map[string]*dynamic.ServersTransport{
 				"test": {
@@ -293,3 +293,30 @@ func TestDisableHTTP2(t *testing.T) {
 		})
 	}
 }
+
+func TestSpiffeNotConfigured(t *testing.T) {
+	rtManager := NewRoundTripperManager(nil)
+
+	_, err := rtManager.createRoundTripper(&dynamic.ServersTransport{
+		Spiffe: &dynamic.Spiffe{IDs: []string{"spiffe://example.org/backend"}},
+	})
+	assert.Error(t, err)
+}
+
+func TestSpiffeNotConfigured_failClosed(t *testing.T) {
+	rtManager := NewRoundTripperManager(nil)
+
+	rtManager.Update(map[string]*dynamic.ServersTransport{
+		"spiffe": {
+			Spiffe: &dynamic.Spiffe{IDs: []string{"spiffe://example.org/backend"}},
+		},
+	})
+
+	rt, err := rtManager.Get("spiffe")
+	require.NoError(t, err)
+	assert.NotEqual(t, http.DefaultTransport, rt)
+
+	req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1", nil)
+	_, err = rt.RoundTrip(req)
+	assert.Error(t, err)
+}
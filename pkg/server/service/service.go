@@ -360,6 +360,13 @@ func (m *Manager) upsertServers(ctx context.Context, lb healthcheck.BalancerHand
 
 		logger.WithField(log.ServerName, name).Debugf("Creating server %d %s", name, u)
 
+		if u.Scheme == "unix" {
+			u, err = unixSocketURL(u)
+			if err != nil {
+				return err
+			}
+		}
+
 		if err := lb.UpsertServer(u, roundrobin.Weight(1)); err != nil {
 			return fmt.Errorf("error adding server %s to load balancer: %w", srv.URL, err)
 		}
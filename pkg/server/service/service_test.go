@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -322,6 +324,74 @@ func TestGetLoadBalancerServiceHandler(t *testing.T) {
 	}
 }
 
+func TestGetLoadBalancerServiceHandler_unixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "app.sock")
+
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	server := &http.Server{Handler: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Host", req.Host)
+		rw.Header().Set("X-URI", req.RequestURI)
+	})}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	roundTripperManager := NewRoundTripperManager(nil)
+	roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
+
+	sm := NewManager(nil, nil, nil, roundTripperManager)
+
+	testCases := []struct {
+		desc           string
+		url            string
+		passHostHeader bool
+		expectedErr    bool
+		expectedHost   string
+	}{
+		{
+			desc:           "passes the host",
+			url:            "unix://" + socket,
+			passHostHeader: true,
+			expectedHost:   "callme",
+		},
+		{
+			desc:         "does not pass the host",
+			url:          "unix://" + socket,
+			expectedHost: "localhost",
+		},
+		{
+			desc:        "relative socket path",
+			url:         "unix://app.sock",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			handler, err := sm.getLoadBalancerServiceHandler(context.Background(), "test", &dynamic.ServersLoadBalancer{
+				PassHostHeader: Bool(test.passHostHeader),
+				Servers:        []dynamic.Server{{URL: test.url}},
+			})
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://callme/foo?bar=baz", nil)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, test.expectedHost, recorder.Header().Get("X-Host"))
+			assert.Equal(t, "/foo?bar=baz", recorder.Header().Get("X-URI"))
+		})
+	}
+}
+
 func TestManager_Build(t *testing.T) {
 	testCases := []struct {
 		desc         string
@@ -0,0 +1,124 @@
+package tcp
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+type routerNameType int
+
+const routerNameKey routerNameType = iota
+
+// AddRouterInContext adds the name of the router the services are built for to the context,
+// so that the services of a router are drained when the router is removed,
+// even if they are still used by other routers.
+func AddRouterInContext(ctx context.Context, routerName string) context.Context {
+	return context.WithValue(ctx, routerNameKey, routerName)
+}
+
+func getRouterName(ctx context.Context) string {
+	routerName, _ := ctx.Value(routerNameKey).(string)
+	return routerName
+}
+
+// Drainer keeps track of the proxies built for the load balancer services across configuration reloads,
+// to drain the connections of the services which are removed or changed by a new configuration.
+type Drainer struct {
+	lock sync.Mutex
+	// services are the services of the current configuration.
+	services map[drainedServiceKey]*drainedService
+	// building are the services built for the new configuration.
+	building map[drainedServiceKey]*drainedService
+}
+
+// drainedServiceKey identifies a service built for a router.
+type drainedServiceKey struct {
+	routerName  string
+	serviceName string
+}
+
+type drainedService struct {
+	config *dynamic.TCPServersLoadBalancer
+	// proxies are the proxies built for the configuration.
+	proxies []*tcp.Proxy
+	// retired are the proxies of the previous configurations, which still have connections.
+	retired []*tcp.Proxy
+}
+
+// NewDrainer creates a new Drainer.
+func NewDrainer() *Drainer {
+	return &Drainer{
+		services: make(map[drainedServiceKey]*drainedService),
+		building: make(map[drainedServiceKey]*drainedService),
+	}
+}
+
+// track records the proxies built for the given service of the new configuration.
+// A service used by a router on several entry points is built once per entry point.
+func (d *Drainer) track(ctx context.Context, serviceName string, config *dynamic.TCPServersLoadBalancer, proxies []*tcp.Proxy) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	key := drainedServiceKey{routerName: getRouterName(ctx), serviceName: serviceName}
+
+	svc, ok := d.building[key]
+	if !ok {
+		svc = &drainedService{config: config.DeepCopy()}
+		d.building[key] = svc
+	}
+
+	svc.proxies = append(svc.proxies, proxies...)
+}
+
+// Apply is called once all the services of the new configuration have been built,
+// and before the routers of the new configuration are switched in.
+// The connections of the services which are not built anymore for a router, or whose configuration changed,
+// are closed once the drain timeout of their previous configuration has elapsed,
+// including the connections accepted until the switch, as the connections are closed when the timeout elapses.
+// The connections of the unchanged services are kept, and drained along with the new ones later on.
+func (d *Drainer) Apply() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	for key, old := range d.services {
+		svc, ok := d.building[key]
+		if ok && reflect.DeepEqual(old.config, svc.config) {
+			// The proxies retired by the previous configuration cannot get new connections anymore,
+			// as the routers of the previous configuration have been switched in since then.
+			for _, proxy := range old.retired {
+				if proxy.ConnCount() > 0 {
+					svc.retired = append(svc.retired, proxy)
+				}
+			}
+
+			// The proxies of the current configuration can still get new connections until the switch.
+			svc.retired = append(svc.retired, old.proxies...)
+			continue
+		}
+
+		timeout := time.Duration(old.config.DrainTimeout)
+		if timeout <= 0 {
+			continue
+		}
+
+		log.WithoutContext().WithField(log.ServiceName, key.serviceName).WithField(log.RouterName, key.routerName).
+			Debugf("Draining the connections of the TCP service in %s", timeout)
+
+		for _, proxy := range old.proxies {
+			proxy.Drain(timeout)
+		}
+
+		for _, proxy := range old.retired {
+			proxy.Drain(timeout)
+		}
+	}
+
+	d.services = d.building
+	d.building = make(map[drainedServiceKey]*drainedService)
+}
@@ -0,0 +1,202 @@
+package tcp
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+// forwardConn forwards a connection to an echo server through the given proxy,
+// and returns the client side of the connection once it is established.
+func forwardConn(t *testing.T, proxy *tcp.Proxy) net.Conn {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		proxy.ServeTCP(conn.(*net.TCPConn))
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+
+	return conn
+}
+
+func echoServer(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				_, _ = io.Copy(conn, conn)
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestDrainer_Apply(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         *dynamic.TCPServersLoadBalancer
+		newConfig      *dynamic.TCPServersLoadBalancer
+		expectedClosed bool
+	}{
+		{
+			desc:           "service removed, with a drain timeout",
+			config:         &dynamic.TCPServersLoadBalancer{DrainTimeout: ptypes.Duration(50 * time.Millisecond)},
+			expectedClosed: true,
+		},
+		{
+			desc:   "service removed, without drain timeout",
+			config: &dynamic.TCPServersLoadBalancer{},
+		},
+		{
+			desc:           "service changed",
+			config:         &dynamic.TCPServersLoadBalancer{DrainTimeout: ptypes.Duration(50 * time.Millisecond)},
+			newConfig:      &dynamic.TCPServersLoadBalancer{DrainTimeout: ptypes.Duration(time.Second)},
+			expectedClosed: true,
+		},
+		{
+			desc:      "service unchanged",
+			config:    &dynamic.TCPServersLoadBalancer{DrainTimeout: ptypes.Duration(50 * time.Millisecond)},
+			newConfig: &dynamic.TCPServersLoadBalancer{DrainTimeout: ptypes.Duration(50 * time.Millisecond)},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			proxy, err := tcp.NewProxy(echoServer(t), 10*time.Millisecond, nil, tcp.DialConfig{}, tcp.ConnTimeouts{}, nil)
+			require.NoError(t, err)
+
+			conn := forwardConn(t, proxy)
+
+			ctx := AddRouterInContext(context.Background(), "router@file")
+
+			drainer := NewDrainer()
+			drainer.track(ctx, "foo@file", test.config, []*tcp.Proxy{proxy})
+			drainer.Apply()
+
+			if test.newConfig != nil {
+				drainer.track(ctx, "foo@file", test.newConfig, nil)
+			}
+			drainer.Apply()
+
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(300*time.Millisecond)))
+			_, err = conn.Read(make([]byte, 1))
+
+			if test.expectedClosed {
+				assert.ErrorIs(t, err, io.EOF)
+				return
+			}
+
+			var netErr net.Error
+			require.ErrorAs(t, err, &netErr)
+			assert.True(t, netErr.Timeout())
+
+			if test.newConfig != nil {
+				// The connections of the unchanged service are drained along with the new ones later on.
+				assert.Equal(t, []*tcp.Proxy{proxy}, drainer.services[drainedServiceKey{routerName: "router@file", serviceName: "foo@file"}].retired)
+			}
+		})
+	}
+}
+
+func TestDrainer_Apply_routerRemoved(t *testing.T) {
+	config := &dynamic.TCPServersLoadBalancer{DrainTimeout: ptypes.Duration(50 * time.Millisecond)}
+
+	fooProxy, err := tcp.NewProxy(echoServer(t), 10*time.Millisecond, nil, tcp.DialConfig{}, tcp.ConnTimeouts{}, nil)
+	require.NoError(t, err)
+
+	barProxy, err := tcp.NewProxy(echoServer(t), 10*time.Millisecond, nil, tcp.DialConfig{}, tcp.ConnTimeouts{}, nil)
+	require.NoError(t, err)
+
+	fooCtx := AddRouterInContext(context.Background(), "foo@file")
+	barCtx := AddRouterInContext(context.Background(), "bar@file")
+
+	drainer := NewDrainer()
+	drainer.track(fooCtx, "service@file", config, []*tcp.Proxy{fooProxy})
+	drainer.track(barCtx, "service@file", config, []*tcp.Proxy{barProxy})
+	drainer.Apply()
+
+	fooConn := forwardConn(t, fooProxy)
+	barConn := forwardConn(t, barProxy)
+
+	// The bar router is removed, while its service is still used by the foo router.
+	drainer.track(fooCtx, "service@file", config, nil)
+	drainer.Apply()
+
+	require.NoError(t, barConn.SetReadDeadline(time.Now().Add(300*time.Millisecond)))
+	_, err = barConn.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+
+	require.NoError(t, fooConn.SetReadDeadline(time.Now().Add(300*time.Millisecond)))
+	_, err = fooConn.Read(make([]byte, 1))
+
+	var netErr net.Error
+	require.ErrorAs(t, err, &netErr)
+	assert.True(t, netErr.Timeout())
+}
+
+func TestDrainer_Apply_connectionsBeforeSwitch(t *testing.T) {
+	config := &dynamic.TCPServersLoadBalancer{DrainTimeout: ptypes.Duration(50 * time.Millisecond)}
+	ctx := AddRouterInContext(context.Background(), "router@file")
+
+	proxy, err := tcp.NewProxy(echoServer(t), 10*time.Millisecond, nil, tcp.DialConfig{}, tcp.ConnTimeouts{}, nil)
+	require.NoError(t, err)
+
+	drainer := NewDrainer()
+	drainer.track(ctx, "foo@file", config, []*tcp.Proxy{proxy})
+	drainer.Apply()
+
+	// The service is unchanged, and its proxy has no connection when the new configuration is applied.
+	drainer.track(ctx, "foo@file", config, nil)
+	drainer.Apply()
+
+	// The proxy gets a connection before the routers of the new configuration are switched in.
+	conn := forwardConn(t, proxy)
+
+	// The service is then removed.
+	drainer.Apply()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(300*time.Millisecond)))
+	_, err = conn.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -5,23 +5,32 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	"github.com/traefik/traefik/v2/pkg/tcp"
 )
 
 // Manager is the TCPHandlers factory.
 type Manager struct {
-	configs map[string]*runtime.TCPServiceInfo
+	configs         map[string]*runtime.TCPServiceInfo
+	metricsRegistry metrics.Registry
+	drainer         *Drainer
 }
 
 // NewManager creates a new manager.
-func NewManager(conf *runtime.Configuration) *Manager {
+// The given drainer, if not nil, drains the connections of the services once they are replaced by a new configuration.
+func NewManager(conf *runtime.Configuration, metricsRegistry metrics.Registry, drainer *Drainer) *Manager {
 	return &Manager{
-		configs: conf.TCPServices,
+		configs:         conf.TCPServices,
+		metricsRegistry: metricsRegistry,
+		drainer:         drainer,
 	}
 }
 
@@ -53,22 +62,50 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 		}
 		duration := time.Duration(*conf.LoadBalancer.TerminationDelay) * time.Millisecond
 
+		dialConfig := tcp.DialConfig{
+			Timeout: time.Duration(conf.LoadBalancer.DialTimeout),
+			DNSTTL:  time.Duration(conf.LoadBalancer.DNSTTL),
+		}
+		if dialConfig.Timeout == 0 {
+			dialConfig.Timeout = time.Duration(dynamic.DefaultTCPDialTimeout)
+		}
+		if dialConfig.DNSTTL == 0 {
+			dialConfig.DNSTTL = time.Duration(dynamic.DefaultTCPDNSTTL)
+		}
+
+		timeouts := tcp.ConnTimeouts{
+			Idle:        time.Duration(conf.LoadBalancer.IdleTimeout),
+			MaxLifetime: time.Duration(conf.LoadBalancer.MaxLifetime),
+		}
+
+		connMetrics := metrics.NewConnMetrics(m.metricsRegistry, "tcp", serviceQualifiedName)
+
+		var proxies []*tcp.Proxy
+
 		for name, server := range conf.LoadBalancer.Servers {
-			if _, _, err := net.SplitHostPort(server.Address); err != nil {
-				logger.Errorf("In service %q: %v", serviceQualifiedName, err)
-				continue
+			if !strings.HasPrefix(server.Address, tcp.UnixSocketPrefix) {
+				if _, _, err := net.SplitHostPort(server.Address); err != nil {
+					logger.Errorf("In service %q: %v", serviceQualifiedName, err)
+					continue
+				}
 			}
 
-			handler, err := tcp.NewProxy(server.Address, duration, conf.LoadBalancer.ProxyProtocol)
+			handler, err := tcp.NewProxy(server.Address, duration, conf.LoadBalancer.ProxyProtocol, dialConfig, timeouts, connMetrics)
 			if err != nil {
 				logger.Errorf("In service %q server %q: %v", serviceQualifiedName, server.Address, err)
 				continue
 			}
+			proxies = append(proxies, handler)
 
-			loadBalancer.AddServer(handler)
+			loadBalancer.AddServer(accesslog.NewTCPFieldHandler(handler, accesslog.ServiceAddr, server.Address, accesslog.AddTCPServiceFields))
 			logger.WithField(log.ServerName, name).Debugf("Creating TCP server %d at %s", name, server.Address)
 		}
-		return loadBalancer, nil
+
+		if m.drainer != nil {
+			m.drainer.track(rootCtx, serviceQualifiedName, conf.LoadBalancer, proxies)
+		}
+
+		return accesslog.NewTCPFieldHandler(loadBalancer, accesslog.ServiceName, serviceQualifiedName, nil), nil
 	case conf.Weighted != nil:
 		loadBalancer := tcp.NewWRRLoadBalancer()
 		for _, service := range conf.Weighted.Services {
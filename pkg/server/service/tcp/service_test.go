@@ -36,7 +36,7 @@ func TestManager_BuildTCP(t *testing.T) {
 			expectedError: `the service "test" does not have any type defined`,
 		},
 		{
-			desc:        "no such host, server is skipped, error is logged",
+			desc:        "no such host, server is resolved when dialing",
 			serviceName: "test",
 			configs: map[string]*runtime.TCPServiceInfo{
 				"test": {
@@ -180,7 +180,7 @@ func TestManager_BuildTCP(t *testing.T) {
 
 			manager := NewManager(&runtime.Configuration{
 				TCPServices: test.configs,
-			})
+			}, nil, nil)
 
 			ctx := context.Background()
 			if len(test.providerName) > 0 {
@@ -199,3 +199,27 @@ func TestManager_BuildTCP(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_BuildTCP_dialDefaultsNotStored(t *testing.T) {
+	loadBalancer := &dynamic.TCPServersLoadBalancer{
+		Servers: []dynamic.TCPServer{
+			{Address: "127.0.0.1:8080"},
+		},
+	}
+
+	manager := NewManager(&runtime.Configuration{
+		TCPServices: map[string]*runtime.TCPServiceInfo{
+			"test@provider-1": {
+				TCPService: &dynamic.TCPService{LoadBalancer: loadBalancer},
+			},
+		},
+	}, nil, nil)
+
+	handler, err := manager.BuildTCP(context.Background(), "test@provider-1")
+	require.NoError(t, err)
+	require.NotNil(t, handler)
+
+	// The defaults are applied to the dialer only, the runtime configuration stays as it was declared.
+	assert.Zero(t, loadBalancer.DialTimeout)
+	assert.Zero(t, loadBalancer.DNSTTL)
+}
@@ -8,19 +8,23 @@ import (
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	"github.com/traefik/traefik/v2/pkg/udp"
 )
 
 // Manager handles UDP services creation.
 type Manager struct {
-	configs map[string]*runtime.UDPServiceInfo
+	configs         map[string]*runtime.UDPServiceInfo
+	metricsRegistry metrics.Registry
 }
 
 // NewManager creates a new manager.
-func NewManager(conf *runtime.Configuration) *Manager {
+func NewManager(conf *runtime.Configuration, metricsRegistry metrics.Registry) *Manager {
 	return &Manager{
-		configs: conf.UDPServices,
+		configs:         conf.UDPServices,
+		metricsRegistry: metricsRegistry,
 	}
 }
 
@@ -45,6 +49,7 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 	switch {
 	case conf.LoadBalancer != nil:
 		loadBalancer := udp.NewWRRLoadBalancer()
+		connMetrics := metrics.NewConnMetrics(m.metricsRegistry, "udp", serviceQualifiedName)
 
 		for name, server := range conf.LoadBalancer.Servers {
 			if _, _, err := net.SplitHostPort(server.Address); err != nil {
@@ -52,16 +57,16 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 				continue
 			}
 
-			handler, err := udp.NewProxy(server.Address)
+			handler, err := udp.NewProxy(server.Address, connMetrics)
 			if err != nil {
 				logger.Errorf("In udp service %q server %q: %v", serviceQualifiedName, server.Address, err)
 				continue
 			}
 
-			loadBalancer.AddServer(handler)
+			loadBalancer.AddServer(accesslog.NewUDPFieldHandler(handler, accesslog.ServiceAddr, server.Address, accesslog.AddUDPServiceFields))
 			logger.WithField(log.ServerName, name).Debugf("Creating UDP server %d at %s", name, server.Address)
 		}
-		return loadBalancer, nil
+		return accesslog.NewUDPFieldHandler(loadBalancer, accesslog.ServiceName, serviceQualifiedName, nil), nil
 	case conf.Weighted != nil:
 		loadBalancer := udp.NewWRRLoadBalancer()
 		for _, service := range conf.Weighted.Services {
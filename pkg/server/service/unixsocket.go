@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// unixSocketHostSuffix is the reserved domain of the hosts encoding the path of a unix socket server.
+// The load balancers and the transports identify the servers by their URL host,
+// so the path of the socket is carried by the host until the connection is dialed.
+const unixSocketHostSuffix = ".unix-socket.traefik.internal"
+
+// unixSocketURL returns the HTTP URL targeting the unix socket of the given unix:///path server URL.
+func unixSocketURL(u *url.URL) (*url.URL, error) {
+	if u.Host != "" || u.Path == "" {
+		return nil, fmt.Errorf("invalid unix socket URL %s: the path of the socket must be absolute (e.g. unix:///var/run/app.sock)", u)
+	}
+
+	return &url.URL{Scheme: "http", Host: hex.EncodeToString([]byte(u.Path)) + unixSocketHostSuffix}, nil
+}
+
+// unixSocketPath returns the path of the unix socket encoded in the given host, or host:port address, if any.
+func unixSocketPath(addr string) (string, bool) {
+	host := addr
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+
+	if !strings.HasSuffix(host, unixSocketHostSuffix) {
+		return "", false
+	}
+
+	path, err := hex.DecodeString(strings.TrimSuffix(host, unixSocketHostSuffix))
+	if err != nil || len(path) == 0 {
+		return "", false
+	}
+
+	return string(path), true
+}
+
+type dialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// unixSocketDialContext wraps the given dial function to dial the unix sockets encoded in the addresses.
+func unixSocketDialContext(dial dialContextFunc) dialContextFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if path, ok := unixSocketPath(addr); ok {
+			return dial(ctx, "unix", path)
+		}
+		return dial(ctx, network, addr)
+	}
+}
+
+// unixSocketProxy wraps the given proxy function to never proxy the requests to unix sockets.
+func unixSocketProxy(proxy func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if _, ok := unixSocketPath(req.URL.Host); ok {
+			return nil, nil
+		}
+		return proxy(req)
+	}
+}
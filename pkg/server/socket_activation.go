@@ -0,0 +1,100 @@
+package server
+
+import (
+	"net"
+	"os"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// inheritedSockets holds the sockets passed by systemd socket activation,
+// or by the parent process on upgrade.
+var inheritedSockets = &socketActivation{getFiles: socketActivationFiles}
+
+// socketActivation holds the sockets passed to the process by socket activation (LISTEN_FDS and LISTEN_FDNAMES),
+// by name, for the entry points of the same name to use them instead of opening their own sockets.
+// An entry point can be given a stream socket, and a datagram socket for HTTP/3.
+type socketActivation struct {
+	getFiles func() []*os.File
+
+	once        sync.Once
+	lock        sync.Mutex
+	listeners   map[string]net.Listener
+	packetConns map[string]net.PacketConn
+}
+
+// listener returns the stream socket passed for the given entry point, if any.
+// A socket is only given once.
+func (s *socketActivation) listener(name string) (net.Listener, bool) {
+	s.once.Do(s.load)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	listener, ok := s.listeners[name]
+	delete(s.listeners, name)
+
+	return listener, ok
+}
+
+// packetConn returns the datagram socket passed for the given entry point, if any.
+// A socket is only given once.
+func (s *socketActivation) packetConn(name string) (net.PacketConn, bool) {
+	s.once.Do(s.load)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	packetConn, ok := s.packetConns[name]
+	delete(s.packetConns, name)
+
+	return packetConn, ok
+}
+
+func (s *socketActivation) load() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.listeners = make(map[string]net.Listener)
+	s.packetConns = make(map[string]net.PacketConn)
+
+	for _, file := range s.getFiles() {
+		s.add(file)
+
+		// The listeners and packet connections use their own copy of the file descriptor.
+		_ = file.Close()
+	}
+}
+
+func (s *socketActivation) add(file *os.File) {
+	logger := log.WithoutContext()
+	name := file.Name()
+
+	if listener, err := net.FileListener(file); err == nil {
+		if _, exists := s.listeners[name]; exists {
+			logger.Errorf("Several stream sockets named %q are passed by socket activation, only the first one is used", name)
+			_ = listener.Close()
+			return
+		}
+
+		s.listeners[name] = listener
+		logger.Debugf("Socket %q (%s) passed by socket activation", name, listener.Addr())
+		return
+	}
+
+	packetConn, err := net.FilePacketConn(file)
+	if err != nil {
+		logger.Errorf("Unsupported socket %q passed by socket activation: %v", name, err)
+		return
+	}
+
+	if _, exists := s.packetConns[name]; exists {
+		logger.Errorf("Several datagram sockets named %q are passed by socket activation, only the first one is used", name)
+		_ = packetConn.Close()
+		return
+	}
+
+	s.packetConns[name] = packetConn
+	logger.Debugf("Socket %q (%s/udp) passed by socket activation", name, packetConn.LocalAddr())
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+// +build !windows
+
+package server
+
+import (
+	"os"
+
+	"github.com/coreos/go-systemd/activation"
+)
+
+// socketActivationFiles returns the files of the sockets passed by the parent process on upgrade, or else by systemd,
+// and unsets the related environment variables to not pass them to child processes.
+func socketActivationFiles() []*os.File {
+	loadUpgradeFiles()
+	if len(upgradeFiles.sockets) > 0 {
+		return upgradeFiles.sockets
+	}
+
+	return activation.Files(true)
+}
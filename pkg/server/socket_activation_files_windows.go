@@ -0,0 +1,11 @@
+//go:build windows
+// +build windows
+
+package server
+
+import "os"
+
+// socketActivationFiles returns no file, as socket activation is not supported on Windows.
+func socketActivationFiles() []*os.File {
+	return nil
+}
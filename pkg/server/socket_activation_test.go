@@ -0,0 +1,80 @@
+//go:build !windows
+// +build !windows
+
+package server
+
+import (
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// socketFile returns a file holding a duplicate of the socket of the given listener or packet connection,
+// named as systemd names the sockets it passes.
+func socketFile(t *testing.T, socket syscall.Conn, name string) *os.File {
+	t.Helper()
+
+	rawConn, err := socket.SyscallConn()
+	require.NoError(t, err)
+
+	var fd int
+	var dupErr error
+	err = rawConn.Control(func(s uintptr) {
+		fd, dupErr = syscall.Dup(int(s))
+	})
+	require.NoError(t, err)
+	require.NoError(t, dupErr)
+
+	return os.NewFile(uintptr(fd), name)
+}
+
+func TestSocketActivation(t *testing.T) {
+	webListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = webListener.Close() })
+
+	otherWebListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = otherWebListener.Close() })
+
+	dnsConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = dnsConn.Close() })
+
+	sockets := &socketActivation{
+		getFiles: func() []*os.File {
+			return []*os.File{
+				socketFile(t, webListener.(*net.TCPListener), "web"),
+				socketFile(t, otherWebListener.(*net.TCPListener), "web"),
+				socketFile(t, dnsConn.(*net.UDPConn), "dns"),
+			}
+		},
+	}
+
+	listener, ok := sockets.listener("web")
+	require.True(t, ok)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	// Only the first socket of a given name is used.
+	assert.Equal(t, webListener.Addr().String(), listener.Addr().String())
+
+	// A socket is only given once.
+	_, ok = sockets.listener("web")
+	assert.False(t, ok)
+
+	_, ok = sockets.listener("dns")
+	assert.False(t, ok)
+
+	packetConn, ok := sockets.packetConn("dns")
+	require.True(t, ok)
+	t.Cleanup(func() { _ = packetConn.Close() })
+
+	assert.Equal(t, dnsConn.LocalAddr().String(), packetConn.LocalAddr().String())
+
+	_, ok = sockets.listener("unknown")
+	assert.False(t, ok)
+}
@@ -0,0 +1,183 @@
+package spiffe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// SVID is an X.509 SPIFFE Verifiable Identity Document.
+type SVID struct {
+	// ID is the SPIFFE ID of the SVID (e.g. spiffe://example.org/traefik).
+	ID string
+	// Certificate holds the certificate chain and the private key of the SVID.
+	Certificate *tls.Certificate
+}
+
+// X509Source holds the X.509 SVID and the trust bundles obtained from the SPIFFE Workload API,
+// and keeps them up to date as the agent rotates them.
+type X509Source struct {
+	addr string
+
+	lock   sync.RWMutex
+	source *workloadapi.X509Source
+
+	ready chan struct{}
+}
+
+// NewX509Source creates a new X509Source for the Workload API listening on the given address,
+// such as unix:///run/spire/sockets/agent.sock or tcp://127.0.0.1:8081.
+// When the address is empty, the SPIFFE_ENDPOINT_SOCKET environment variable is used.
+func NewX509Source(addr string) (*X509Source, error) {
+	if addr == "" {
+		addr, _ = workloadapi.GetDefaultAddress()
+	}
+
+	if addr == "" {
+		return nil, fmt.Errorf("no Workload API address configured, and %s is not set", workloadapi.SocketEnv)
+	}
+
+	if err := workloadapi.ValidateAddress(addr); err != nil {
+		return nil, fmt.Errorf("invalid Workload API address %q: %w", addr, err)
+	}
+
+	return &X509Source{
+		addr:  addr,
+		ready: make(chan struct{}),
+	}, nil
+}
+
+// Watch watches the X.509 SVIDs and trust bundles updates of the Workload API until the context is canceled.
+// The Workload API client reconnects to the agent when the stream fails,
+// and keeps the previous SVID when an update is invalid.
+func (s *X509Source) Watch(ctx context.Context) {
+	logger := log.FromContext(ctx)
+
+	// The source is returned once a first X.509 SVID has been received.
+	source, err := workloadapi.NewX509Source(ctx, workloadapi.WithClientOptions(workloadapi.WithAddr(s.addr), workloadapi.WithLogger(logger)))
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Errorf("Cannot watch the SPIFFE Workload API: %v", err)
+		}
+		return
+	}
+
+	s.lock.Lock()
+	s.source = source
+	s.lock.Unlock()
+
+	close(s.ready)
+
+	<-ctx.Done()
+
+	if err := source.Close(); err != nil {
+		logger.Errorf("Error while closing the SPIFFE Workload API client: %v", err)
+	}
+}
+
+// WaitUntilReady waits until a first X.509 SVID has been received, or the context is canceled.
+func (s *X509Source) WaitUntilReady(ctx context.Context) error {
+	select {
+	case <-s.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// GetX509SVID returns the current X.509 SVID.
+func (s *X509Source) GetX509SVID() (*SVID, error) {
+	source, err := s.getSource()
+	if err != nil {
+		return nil, err
+	}
+
+	svid, err := source.GetX509SVID()
+	if err != nil {
+		return nil, err
+	}
+
+	return newSVID(svid), nil
+}
+
+// GetX509Bundle returns the CA certificates of the given trust domain.
+func (s *X509Source) GetX509Bundle(trustDomain string) ([]*x509.Certificate, error) {
+	source, err := s.getSource()
+	if err != nil {
+		return nil, err
+	}
+
+	td, err := spiffeid.TrustDomainFromString(trustDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, err := source.GetX509BundleForTrustDomain(td)
+	if err != nil {
+		return nil, fmt.Errorf("no X.509 bundle for the trust domain %q", trustDomain)
+	}
+
+	return bundle.X509Authorities(), nil
+}
+
+// newSVID converts an SVID validated by the Workload API client, which has at least one certificate.
+func newSVID(svid *x509svid.SVID) *SVID {
+	cert := &tls.Certificate{
+		PrivateKey: svid.PrivateKey,
+		Leaf:       svid.Certificates[0],
+	}
+	for _, c := range svid.Certificates {
+		cert.Certificate = append(cert.Certificate, c.Raw)
+	}
+
+	return &SVID{ID: svid.ID.String(), Certificate: cert}
+}
+
+func (s *X509Source) getSource() (*workloadapi.X509Source, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.source == nil {
+		return nil, errors.New("no X.509 SVID received from the SPIFFE Workload API")
+	}
+
+	return s.source, nil
+}
+
+// parseID validates the given SPIFFE ID, or trust domain ID, and returns its trust domain.
+func parseID(id string) (string, error) {
+	u, err := url.Parse(id)
+	if err != nil {
+		return "", fmt.Errorf("invalid SPIFFE ID %q: %w", id, err)
+	}
+
+	if u.Scheme != "spiffe" || u.Host == "" || u.User != nil || u.Port() != "" || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("invalid SPIFFE ID %q", id)
+	}
+
+	return strings.ToLower(u.Host), nil
+}
+
+// getID returns the SPIFFE ID of the given certificate, which must be its only URI SAN.
+func getID(cert *x509.Certificate) (string, error) {
+	if len(cert.URIs) != 1 {
+		return "", fmt.Errorf("the certificate must have exactly one URI SAN, got %d", len(cert.URIs))
+	}
+
+	id := cert.URIs[0].String()
+	if _, err := parseID(id); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
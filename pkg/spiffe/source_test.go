@@ -0,0 +1,261 @@
+package spiffe
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/proto/spiffe/workload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// testCA is the CA of a trust domain, issuing SVIDs.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, trustDomain string) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{trustDomain}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		URIs:                  []*url.URL{{Scheme: "spiffe", Host: trustDomain}},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key}
+}
+
+// issue returns the Workload API message of an SVID issued by the CA for the given SPIFFE ID.
+func (c *testCA) issue(t *testing.T, id string) *workload.X509SVID {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	uri, err := url.Parse(id)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		URIs:         []*url.URL{uri},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, c.cert, key.Public(), c.key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return &workload.X509SVID{
+		SpiffeId:    id,
+		X509Svid:    der,
+		X509SvidKey: keyDER,
+		Bundle:      c.cert.Raw,
+	}
+}
+
+// fakeAgent is a fake SPIFFE Workload API, streaming the responses sent on its channel.
+type fakeAgent struct {
+	workload.UnimplementedSpiffeWorkloadAPIServer
+
+	responses chan *workload.X509SVIDResponse
+}
+
+// startFakeAgent starts a fake agent listening on a unix socket, and returns its Workload API address.
+func startFakeAgent(t *testing.T) (*fakeAgent, string) {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	agent := &fakeAgent{responses: make(chan *workload.X509SVIDResponse, 10)}
+
+	server := grpc.NewServer()
+	workload.RegisterSpiffeWorkloadAPIServer(server, agent)
+
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return agent, "unix://" + socket
+}
+
+func (a *fakeAgent) FetchX509SVID(_ *workload.X509SVIDRequest, stream workload.SpiffeWorkloadAPI_FetchX509SVIDServer) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	if len(md.Get("workload.spiffe.io")) == 0 {
+		return errors.New("security header missing from request")
+	}
+
+	for {
+		select {
+		case resp := <-a.responses:
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func TestX509Source(t *testing.T) {
+	agent, addr := startFakeAgent(t)
+
+	ca := newTestCA(t, "example.org")
+	federatedCA := newTestCA(t, "federated.org")
+
+	agent.responses <- &workload.X509SVIDResponse{
+		Svids:            []*workload.X509SVID{ca.issue(t, "spiffe://example.org/traefik")},
+		FederatedBundles: map[string][]byte{"spiffe://federated.org": federatedCA.cert.Raw},
+	}
+
+	source, err := NewX509Source(addr)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go source.Watch(ctx)
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer waitCancel()
+	require.NoError(t, source.WaitUntilReady(waitCtx))
+
+	svid, err := source.GetX509SVID()
+	require.NoError(t, err)
+	assert.Equal(t, "spiffe://example.org/traefik", svid.ID)
+	assert.Equal(t, "spiffe://example.org/traefik", svid.Certificate.Leaf.URIs[0].String())
+
+	bundle, err := source.GetX509Bundle("example.org")
+	require.NoError(t, err)
+	assert.Equal(t, []*x509.Certificate{ca.cert}, bundle)
+
+	bundle, err = source.GetX509Bundle("federated.org")
+	require.NoError(t, err)
+	assert.Equal(t, []*x509.Certificate{federatedCA.cert}, bundle)
+
+	_, err = source.GetX509Bundle("unknown.org")
+	assert.Error(t, err)
+
+	// The agent rotates the SVID.
+	rotated := ca.issue(t, "spiffe://example.org/traefik")
+	agent.responses <- &workload.X509SVIDResponse{Svids: []*workload.X509SVID{rotated}}
+
+	assert.Eventually(t, func() bool {
+		svid, err := source.GetX509SVID()
+		require.NoError(t, err)
+		return string(svid.Certificate.Certificate[0]) == string(rotated.X509Svid)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err = source.GetX509Bundle("federated.org")
+	assert.Error(t, err)
+
+	// An invalid update is ignored, and the previous SVID is kept.
+	agent.responses <- &workload.X509SVIDResponse{Svids: []*workload.X509SVID{{SpiffeId: "spiffe://example.org/traefik", X509Svid: []byte("invalid")}}}
+	agent.responses <- &workload.X509SVIDResponse{}
+
+	time.Sleep(100 * time.Millisecond)
+
+	svid, err = source.GetX509SVID()
+	require.NoError(t, err)
+	assert.Equal(t, rotated.X509Svid, svid.Certificate.Certificate[0])
+}
+
+func TestX509Source_WaitUntilReady(t *testing.T) {
+	_, addr := startFakeAgent(t)
+
+	source, err := NewX509Source(addr)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go source.Watch(ctx)
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer waitCancel()
+	assert.ErrorIs(t, source.WaitUntilReady(waitCtx), context.DeadlineExceeded)
+
+	_, err = source.GetX509SVID()
+	assert.Error(t, err)
+}
+
+func TestNewX509Source(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		addr        string
+		expectedErr bool
+	}{
+		{
+			desc: "unix socket",
+			addr: "unix:///run/spire/sockets/agent.sock",
+		},
+		{
+			desc:        "relative unix socket",
+			addr:        "unix:agent.sock",
+			expectedErr: true,
+		},
+		{
+			desc: "TCP address",
+			addr: "tcp://127.0.0.1:8081",
+		},
+		{
+			desc:        "TCP address with a host name",
+			addr:        "tcp://localhost:8081",
+			expectedErr: true,
+		},
+		{
+			desc:        "unsupported scheme",
+			addr:        "http://127.0.0.1:8081",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewX509Source(test.addr)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package spiffe
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Source is a source of X.509 SVIDs and trust bundles.
+type Source interface {
+	GetX509SVID() (*SVID, error)
+	GetX509Bundle(trustDomain string) ([]*x509.Certificate, error)
+}
+
+// Authorizer authorizes the SPIFFE ID of a peer, once its certificate has been verified.
+type Authorizer func(id, trustDomain string) error
+
+// NewAuthorizer creates an Authorizer allowing the given SPIFFE IDs when defined,
+// or else the members of the given trust domain when defined, or else any SPIFFE ID.
+func NewAuthorizer(ids []string, trustDomain string) (Authorizer, error) {
+	if len(ids) > 0 {
+		allowed := make(map[string]struct{}, len(ids))
+		for _, id := range ids {
+			if _, err := parseID(id); err != nil {
+				return nil, err
+			}
+			allowed[id] = struct{}{}
+		}
+
+		return func(id, _ string) error {
+			if _, ok := allowed[id]; !ok {
+				return fmt.Errorf("unexpected SPIFFE ID %q", id)
+			}
+			return nil
+		}, nil
+	}
+
+	if trustDomain != "" {
+		// The trust domain can be given as a name (example.org), or as an ID (spiffe://example.org).
+		name := strings.TrimPrefix(trustDomain, "spiffe://")
+		if strings.Contains(name, "/") {
+			return nil, fmt.Errorf("invalid SPIFFE trust domain %q", trustDomain)
+		}
+
+		expected, err := parseID("spiffe://" + name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SPIFFE trust domain %q", trustDomain)
+		}
+
+		return func(id, trustDomain string) error {
+			if trustDomain != expected {
+				return fmt.Errorf("the SPIFFE ID %q is not a member of the trust domain %q", id, expected)
+			}
+			return nil
+		}, nil
+	}
+
+	return func(string, string) error { return nil }, nil
+}
+
+// TLSClientConfig creates a TLS configuration presenting the SVID of the source as client certificate,
+// and verifying the certificate of the server against the trust bundles of the source.
+func TLSClientConfig(source Source, authorize Authorizer) *tls.Config {
+	return &tls.Config{
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			svid, err := source.GetX509SVID()
+			if err != nil {
+				return nil, err
+			}
+			return svid.Certificate, nil
+		},
+		// The server certificate is verified by VerifyPeerCertificate against the bundle of its trust domain,
+		// as an SVID is identified by its SPIFFE ID rather than by a server name.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return VerifyPeerCertificate(source, authorize, rawCerts)
+		},
+	}
+}
+
+// VerifyPeerCertificate verifies the given certificate chain against the trust bundle of the trust domain of its SPIFFE ID,
+// and authorizes its SPIFFE ID.
+func VerifyPeerCertificate(source Source, authorize Authorizer, rawCerts [][]byte) error {
+	if len(rawCerts) == 0 {
+		return errors.New("no peer certificate")
+	}
+
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("invalid peer certificate: %w", err)
+		}
+		certs[i] = cert
+	}
+
+	id, err := getID(certs[0])
+	if err != nil {
+		return fmt.Errorf("invalid peer SVID: %w", err)
+	}
+
+	trustDomain, err := parseID(id)
+	if err != nil {
+		return fmt.Errorf("invalid peer SVID: %w", err)
+	}
+
+	bundle, err := source.GetX509Bundle(trustDomain)
+	if err != nil {
+		return fmt.Errorf("cannot verify the peer SVID %q: %w", id, err)
+	}
+
+	roots := x509.NewCertPool()
+	for _, cert := range bundle {
+		roots.AddCert(cert)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return fmt.Errorf("cannot verify the peer SVID %q: %w", id, err)
+	}
+
+	return authorize(id, trustDomain)
+}
@@ -0,0 +1,220 @@
+package spiffe
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/proto/spiffe/workload"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticSource is a Source holding a fixed SVID and bundles.
+type staticSource struct {
+	svid    *SVID
+	bundles map[string][]*x509.Certificate
+}
+
+func newStaticSource(t *testing.T, svid *workload.X509SVID, bundles ...*testCA) *staticSource {
+	t.Helper()
+
+	parsed, err := x509svid.ParseRaw(svid.X509Svid, svid.X509SvidKey)
+	require.NoError(t, err)
+
+	source := &staticSource{
+		svid:    newSVID(parsed),
+		bundles: make(map[string][]*x509.Certificate),
+	}
+
+	for _, ca := range bundles {
+		source.bundles[ca.cert.URIs[0].Host] = []*x509.Certificate{ca.cert}
+	}
+
+	return source
+}
+
+func (s *staticSource) GetX509SVID() (*SVID, error) {
+	return s.svid, nil
+}
+
+func (s *staticSource) GetX509Bundle(trustDomain string) ([]*x509.Certificate, error) {
+	bundle, ok := s.bundles[trustDomain]
+	if !ok {
+		return nil, fmt.Errorf("no bundle for %s", trustDomain)
+	}
+	return bundle, nil
+}
+
+func TestNewAuthorizer(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		ids          []string
+		trustDomain  string
+		id           string
+		expectedErr  bool
+		expectedDeny bool
+	}{
+		{
+			desc: "any SPIFFE ID",
+			id:   "spiffe://example.org/backend",
+		},
+		{
+			desc: "allowed SPIFFE ID",
+			ids:  []string{"spiffe://example.org/other", "spiffe://example.org/backend"},
+			id:   "spiffe://example.org/backend",
+		},
+		{
+			desc:         "SPIFFE ID not allowed",
+			ids:          []string{"spiffe://example.org/other"},
+			id:           "spiffe://example.org/backend",
+			expectedDeny: true,
+		},
+		{
+			desc:         "SPIFFE IDs take precedence over the trust domain",
+			ids:          []string{"spiffe://example.org/other"},
+			trustDomain:  "example.org",
+			id:           "spiffe://example.org/backend",
+			expectedDeny: true,
+		},
+		{
+			desc:        "member of the trust domain",
+			trustDomain: "example.org",
+			id:          "spiffe://example.org/backend",
+		},
+		{
+			desc:        "member of the trust domain given as an ID",
+			trustDomain: "spiffe://example.org",
+			id:          "spiffe://example.org/backend",
+		},
+		{
+			desc:         "not a member of the trust domain",
+			trustDomain:  "example.org",
+			id:           "spiffe://other.org/backend",
+			expectedDeny: true,
+		},
+		{
+			desc:        "invalid SPIFFE ID",
+			ids:         []string{"https://example.org/backend"},
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid trust domain",
+			trustDomain: "spiffe://example.org/backend",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			authorize, err := NewAuthorizer(test.ids, test.trustDomain)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			trustDomain, err := parseID(test.id)
+			require.NoError(t, err)
+
+			err = authorize(test.id, trustDomain)
+			if test.expectedDeny {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTLSClientConfig(t *testing.T) {
+	ca := newTestCA(t, "example.org")
+	otherCA := newTestCA(t, "other.org")
+
+	testCases := []struct {
+		desc         string
+		serverSVID   *workload.X509SVID
+		ids          []string
+		expectedErr  bool
+		clientBundle []*testCA
+	}{
+		{
+			desc:         "allowed server SPIFFE ID",
+			serverSVID:   ca.issue(t, "spiffe://example.org/backend"),
+			ids:          []string{"spiffe://example.org/backend"},
+			clientBundle: []*testCA{ca},
+		},
+		{
+			desc:         "server SPIFFE ID not allowed",
+			serverSVID:   ca.issue(t, "spiffe://example.org/other"),
+			ids:          []string{"spiffe://example.org/backend"},
+			clientBundle: []*testCA{ca},
+			expectedErr:  true,
+		},
+		{
+			desc:         "server SVID of a federated trust domain",
+			serverSVID:   otherCA.issue(t, "spiffe://other.org/backend"),
+			clientBundle: []*testCA{ca, otherCA},
+		},
+		{
+			desc:         "server SVID of an unknown trust domain",
+			serverSVID:   otherCA.issue(t, "spiffe://other.org/backend"),
+			clientBundle: []*testCA{ca},
+			expectedErr:  true,
+		},
+		{
+			desc:         "server SVID signed by another CA",
+			serverSVID:   otherCA.issue(t, "spiffe://example.org/backend"),
+			clientBundle: []*testCA{ca, otherCA},
+			expectedErr:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			serverSource := newStaticSource(t, test.serverSVID, ca)
+			clientSource := newStaticSource(t, ca.issue(t, "spiffe://example.org/traefik"), test.clientBundle...)
+
+			var clientID string
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				clientID = req.TLS.PeerCertificates[0].URIs[0].String()
+			}))
+
+			srv.TLS = &tls.Config{
+				Certificates: []tls.Certificate{*serverSource.svid.Certificate},
+				ClientAuth:   tls.RequireAnyClientCert,
+				VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+					return VerifyPeerCertificate(serverSource, func(string, string) error { return nil }, rawCerts)
+				},
+			}
+			srv.StartTLS()
+			t.Cleanup(srv.Close)
+
+			authorize, err := NewAuthorizer(test.ids, "")
+			require.NoError(t, err)
+
+			client := http.Client{Transport: &http.Transport{TLSClientConfig: TLSClientConfig(clientSource, authorize)}}
+
+			resp, err := client.Get(srv.URL)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "spiffe://example.org/traefik", clientID)
+		})
+	}
+}
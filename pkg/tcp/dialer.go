@@ -0,0 +1,235 @@
+package tcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// connectionAttemptDelay is the delay after which the next address is dialed,
+// while the connection attempt to the previous address is still pending (RFC 8305 section 5).
+const connectionAttemptDelay = 250 * time.Millisecond
+
+// DialConfig holds the configuration of the dialing of the servers of a TCP service.
+type DialConfig struct {
+	// Timeout is the maximum duration for the connection to a server to be established.
+	// Zero or a negative value means no timeout.
+	Timeout time.Duration
+	// DNSTTL is the duration during which the addresses resolved for the host name of a server are reused.
+	// Zero or a negative value means the host name is resolved for each connection.
+	DNSTTL time.Duration
+}
+
+// dialer dials a TCP server, re-resolving its host name once the resolved addresses have expired,
+// and racing the connection attempts to all the resolved addresses (Happy Eyeballs, RFC 8305).
+type dialer struct {
+	host    string
+	port    string
+	timeout time.Duration
+	ttl     time.Duration
+
+	lookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
+	dialContext  func(ctx context.Context, network, address string) (net.Conn, error)
+
+	lock    sync.Mutex
+	addrs   []string
+	expires time.Time
+}
+
+func newDialer(address string, config DialConfig) (*dialer, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &dialer{
+		host:         host,
+		port:         port,
+		timeout:      config.Timeout,
+		ttl:          config.DNSTTL,
+		lookupIPAddr: net.DefaultResolver.LookupIPAddr,
+		dialContext:  (&net.Dialer{}).DialContext,
+	}
+
+	// An IP address, or an empty host meaning the local system, never needs to be resolved.
+	if host == "" || net.ParseIP(host) != nil {
+		d.addrs = []string{address}
+	}
+
+	return d, nil
+}
+
+// dial connects to the server.
+func (d *dialer) dial() (WriteCloser, error) {
+	ctx := context.Background()
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+
+	addrs, err := d.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := d.dialHappyEyeballs(ctx, addrs)
+	if err != nil {
+		return nil, err
+	}
+
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected connection type %T", conn)
+	}
+
+	return tcpConn, nil
+}
+
+// resolve returns the addresses of the server, resolving its host name if the previously resolved addresses have expired.
+func (d *dialer) resolve(ctx context.Context) ([]string, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if len(d.addrs) > 0 && (d.expires.IsZero() || time.Now().Before(d.expires)) {
+		return d.addrs, nil
+	}
+
+	ipAddrs, err := d.lookupIPAddr(ctx, d.host)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ipAddrs) == 0 {
+		return nil, fmt.Errorf("no addresses found for %s", d.host)
+	}
+
+	addrs := make([]string, 0, len(ipAddrs))
+	for _, ipAddr := range interleaveFamilies(ipAddrs) {
+		addrs = append(addrs, net.JoinHostPort(ipAddr.String(), d.port))
+	}
+
+	if d.ttl > 0 {
+		d.addrs = addrs
+		d.expires = time.Now().Add(d.ttl)
+	}
+
+	return addrs, nil
+}
+
+type dialResult struct {
+	conn net.Conn
+	err  error
+}
+
+// dialHappyEyeballs dials the given addresses in order, and returns the first established connection.
+// The next address is dialed as soon as the connection attempt to the previous one failed,
+// or once the connection attempt delay has elapsed.
+func (d *dialer) dialHappyEyeballs(ctx context.Context, addrs []string) (net.Conn, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The channel is large enough for the pending attempts not to block once a connection is established.
+	results := make(chan dialResult, len(addrs))
+
+	var next, pending int
+	dialNext := func() {
+		addr := addrs[next]
+		next++
+		pending++
+
+		go func() {
+			conn, err := d.dialContext(ctx, "tcp", addr)
+			results <- dialResult{conn: conn, err: err}
+		}()
+	}
+
+	dialNext()
+
+	delay := time.NewTimer(connectionAttemptDelay)
+	defer delay.Stop()
+
+	var firstErr error
+	for pending > 0 {
+		select {
+		case result := <-results:
+			pending--
+
+			if result.err == nil {
+				go closeLateConns(results, pending)
+				return result.conn, nil
+			}
+
+			if firstErr == nil {
+				firstErr = result.err
+			}
+
+			if next < len(addrs) {
+				if !delay.Stop() {
+					select {
+					case <-delay.C:
+					default:
+					}
+				}
+				delay.Reset(connectionAttemptDelay)
+
+				dialNext()
+			}
+
+		case <-delay.C:
+			if next < len(addrs) {
+				delay.Reset(connectionAttemptDelay)
+
+				dialNext()
+			}
+		}
+	}
+
+	if firstErr == nil {
+		firstErr = errors.New("no addresses to dial")
+	}
+
+	return nil, firstErr
+}
+
+// closeLateConns closes the connections established by the attempts still pending once a connection has been established.
+func closeLateConns(results <-chan dialResult, pending int) {
+	for i := 0; i < pending; i++ {
+		if result := <-results; result.conn != nil {
+			_ = result.conn.Close()
+		}
+	}
+}
+
+// interleaveFamilies interleaves the IPv6 and IPv4 addresses,
+// starting with the family of the first address and otherwise keeping the order of the resolver (RFC 8305 section 4).
+func interleaveFamilies(ipAddrs []net.IPAddr) []net.IPAddr {
+	var first, second []net.IPAddr
+	for _, ipAddr := range ipAddrs {
+		if isIPv4(ipAddr.IP) == isIPv4(ipAddrs[0].IP) {
+			first = append(first, ipAddr)
+		} else {
+			second = append(second, ipAddr)
+		}
+	}
+
+	interleaved := make([]net.IPAddr, 0, len(ipAddrs))
+	for i := 0; i < len(first) || i < len(second); i++ {
+		if i < len(first) {
+			interleaved = append(interleaved, first[i])
+		}
+		if i < len(second) {
+			interleaved = append(interleaved, second[i])
+		}
+	}
+
+	return interleaved
+}
+
+func isIPv4(ip net.IP) bool {
+	return ip.To4() != nil
+}
@@ -0,0 +1,187 @@
+package tcp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialer_resolve(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		address         string
+		ttl             time.Duration
+		expectedLookups int
+	}{
+		{
+			desc:            "IP address is never resolved",
+			address:         "10.0.0.1:80",
+			ttl:             time.Hour,
+			expectedLookups: 0,
+		},
+		{
+			desc:            "host name resolution is cached",
+			address:         "example.com:80",
+			ttl:             time.Hour,
+			expectedLookups: 1,
+		},
+		{
+			desc:            "host name is resolved for each connection without TTL",
+			address:         "example.com:80",
+			expectedLookups: 3,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := newDialer(test.address, DialConfig{DNSTTL: test.ttl})
+			require.NoError(t, err)
+
+			var lookups int
+			d.lookupIPAddr = func(_ context.Context, host string) ([]net.IPAddr, error) {
+				lookups++
+				return []net.IPAddr{{IP: net.ParseIP("10.0.0.2")}}, nil
+			}
+
+			for i := 0; i < 3; i++ {
+				_, err := d.resolve(context.Background())
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedLookups, lookups)
+		})
+	}
+}
+
+func TestDialer_resolveExpires(t *testing.T) {
+	d, err := newDialer("example.com:80", DialConfig{DNSTTL: time.Hour})
+	require.NoError(t, err)
+
+	ip := "10.0.0.1"
+	d.lookupIPAddr = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
+	}
+
+	addrs, err := d.resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1:80"}, addrs)
+
+	ip = "10.0.0.2"
+	d.expires = time.Now().Add(-time.Second)
+
+	addrs, err = d.resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.2:80"}, addrs)
+}
+
+func TestInterleaveFamilies(t *testing.T) {
+	ipAddrs := []net.IPAddr{
+		{IP: net.ParseIP("2001:db8::1")},
+		{IP: net.ParseIP("2001:db8::2")},
+		{IP: net.ParseIP("2001:db8::3")},
+		{IP: net.ParseIP("10.0.0.1")},
+		{IP: net.ParseIP("10.0.0.2")},
+	}
+
+	var interleaved []string
+	for _, ipAddr := range interleaveFamilies(ipAddrs) {
+		interleaved = append(interleaved, ipAddr.String())
+	}
+
+	assert.Equal(t, []string{"2001:db8::1", "10.0.0.1", "2001:db8::2", "10.0.0.2", "2001:db8::3"}, interleaved)
+}
+
+func TestDialer_dialHappyEyeballs(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		addrs        []string
+		failing      map[string]bool
+		hanging      map[string]bool
+		expectedAddr string
+		expectedErr  bool
+	}{
+		{
+			desc:         "first address reachable",
+			addrs:        []string{"a", "b"},
+			expectedAddr: "a",
+		},
+		{
+			desc:         "first address failing",
+			addrs:        []string{"a", "b"},
+			failing:      map[string]bool{"a": true},
+			expectedAddr: "b",
+		},
+		{
+			desc:         "first address hanging",
+			addrs:        []string{"a", "b"},
+			hanging:      map[string]bool{"a": true},
+			expectedAddr: "b",
+		},
+		{
+			desc:        "all addresses failing",
+			addrs:       []string{"a", "b", "c"},
+			failing:     map[string]bool{"a": true, "b": true, "c": true},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := newDialer("example.com:80", DialConfig{})
+			require.NoError(t, err)
+
+			var lock sync.Mutex
+			var dialed []string
+			d.dialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+				lock.Lock()
+				dialed = append(dialed, address)
+				lock.Unlock()
+
+				switch {
+				case test.failing[address]:
+					return nil, errors.New("connection refused")
+				case test.hanging[address]:
+					<-ctx.Done()
+					return nil, ctx.Err()
+				default:
+					return &addrNetConn{addr: address}, nil
+				}
+			}
+
+			conn, err := d.dialHappyEyeballs(context.Background(), test.addrs)
+			if test.expectedErr {
+				require.Error(t, err)
+
+				lock.Lock()
+				defer lock.Unlock()
+				assert.Equal(t, test.addrs, dialed)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedAddr, conn.(*addrNetConn).addr)
+		})
+	}
+}
+
+type addrNetConn struct {
+	net.Conn
+
+	addr string
+}
+
+func (c *addrNetConn) Close() error {
+	return nil
+}
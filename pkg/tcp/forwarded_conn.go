@@ -0,0 +1,139 @@
+package tcp
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reasons for which a forwarded connection is closed by the proxy itself.
+const (
+	CloseReasonIdleTimeout = "idle_timeout"
+	CloseReasonMaxLifetime = "max_lifetime"
+	CloseReasonDrained     = "drained"
+)
+
+// ConnTimeouts holds the timeouts of the connections forwarded to the servers of a TCP service.
+type ConnTimeouts struct {
+	// Idle is the maximum duration without any data exchanged with the client or the server.
+	// Zero or a negative value means no idle timeout.
+	Idle time.Duration
+	// MaxLifetime is the maximum duration of a connection.
+	// Zero or a negative value means no maximum.
+	MaxLifetime time.Duration
+}
+
+// forwardedConn is a client connection forwarded to a server connection.
+type forwardedConn struct {
+	client  WriteCloser
+	backend WriteCloser
+
+	once   sync.Once
+	reason atomic.Value
+}
+
+// close closes both sides of the connection, for the given reason.
+func (c *forwardedConn) close(reason string) {
+	c.once.Do(func() {
+		c.reason.Store(reason)
+		setCloseReason(c.client, reason)
+
+		_ = c.client.Close()
+		_ = c.backend.Close()
+	})
+}
+
+// closeReason returns the reason for which the connection was closed by the proxy, if any.
+func (c *forwardedConn) closeReason() string {
+	reason, _ := c.reason.Load().(string)
+	return reason
+}
+
+// setCloseReason reports the reason for which the proxy closes the given connection,
+// to the wrapping connections keeping track of it, such as the connections of the access logs.
+func setCloseReason(conn WriteCloser, reason string) {
+	for {
+		switch c := conn.(type) {
+		case interface{ SetCloseReason(string) }:
+			c.SetCloseReason(reason)
+			return
+		case interface{ Unwrap() WriteCloser }:
+			conn = c.Unwrap()
+		default:
+			return
+		}
+	}
+}
+
+// idleTimer calls its function once no activity has been recorded for the duration of its timeout.
+type idleTimer struct {
+	timeout time.Duration
+	// last is the time of the last activity, in nanoseconds since the Unix epoch.
+	last int64
+
+	lock    sync.Mutex
+	timer   *time.Timer
+	stopped bool
+}
+
+func newIdleTimer(timeout time.Duration, f func()) *idleTimer {
+	t := &idleTimer{timeout: timeout}
+	t.touch()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.timer = time.AfterFunc(timeout, func() { t.check(f) })
+
+	return t
+}
+
+// touch records an activity.
+func (t *idleTimer) touch() {
+	atomic.StoreInt64(&t.last, time.Now().UnixNano())
+}
+
+// check calls f if no activity has been recorded for the duration of the timeout,
+// and otherwise checks again once the timeout may have elapsed.
+func (t *idleTimer) check(f func()) {
+	t.lock.Lock()
+
+	if t.stopped {
+		t.lock.Unlock()
+		return
+	}
+
+	idle := time.Since(time.Unix(0, atomic.LoadInt64(&t.last)))
+	if idle < t.timeout {
+		t.timer.Reset(t.timeout - idle)
+		t.lock.Unlock()
+		return
+	}
+
+	t.lock.Unlock()
+
+	f()
+}
+
+func (t *idleTimer) stop() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.stopped = true
+	t.timer.Stop()
+}
+
+// activityReader records an activity on its idle timer for each read.
+type activityReader struct {
+	WriteCloser
+
+	idle *idleTimer
+}
+
+func (r activityReader) Read(p []byte) (int, error) {
+	n, err := r.WriteCloser.Read(p)
+	if n > 0 {
+		r.idle.touch()
+	}
+	return n, err
+}
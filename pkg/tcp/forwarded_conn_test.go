@@ -0,0 +1,66 @@
+package tcp
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdleTimer(t *testing.T) {
+	var fired int32
+	idle := newIdleTimer(100*time.Millisecond, func() { atomic.AddInt32(&fired, 1) })
+	defer idle.stop()
+
+	for i := 0; i < 5; i++ {
+		time.Sleep(40 * time.Millisecond)
+		idle.touch()
+	}
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&fired))
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&fired) == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestIdleTimer_stop(t *testing.T) {
+	var fired int32
+	idle := newIdleTimer(50*time.Millisecond, func() { atomic.AddInt32(&fired, 1) })
+	idle.stop()
+
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&fired))
+}
+
+type closeReasonConn struct {
+	WriteCloser
+
+	reason string
+	closed int
+}
+
+func (c *closeReasonConn) SetCloseReason(reason string) {
+	c.reason = reason
+}
+
+func (c *closeReasonConn) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestForwardedConn_close(t *testing.T) {
+	client := &closeReasonConn{}
+	backend := &closeReasonConn{}
+
+	fc := &forwardedConn{client: &retryConn{WriteCloser: client}, backend: backend}
+	assert.Empty(t, fc.closeReason())
+
+	fc.close(CloseReasonIdleTimeout)
+	fc.close(CloseReasonDrained)
+
+	assert.Equal(t, CloseReasonIdleTimeout, fc.closeReason())
+	assert.Equal(t, CloseReasonIdleTimeout, client.reason)
+	assert.Equal(t, 1, client.closed)
+	assert.Equal(t, 1, backend.closed)
+}
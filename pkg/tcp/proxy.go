@@ -1,102 +1,337 @@
 package tcp
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"net"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pires/go-proxyproto"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/proxyprotocol"
+)
+
+// UnixSocketPrefix is the prefix of the server addresses targeting a unix socket.
+const UnixSocketPrefix = "unix://"
+
+// defaultHandshakeTimeout is the maximum duration of the TLS handshake with the client,
+// completed before building the Proxy Protocol TLVs which depend on it.
+const defaultHandshakeTimeout = 10 * time.Second
+
+// Sources of the values of the Proxy Protocol TLVs taken from the client connection.
+const (
+	tlvFromSNI    = "sni"
+	tlvFromALPN   = "alpn"
+	tlvFromClient = "client"
 )
 
 // Proxy forwards a TCP request to a TCP service.
 type Proxy struct {
 	address          string
-	target           *net.TCPAddr
+	dialer           *dialer
+	unixSocket       string
+	dialTimeout      time.Duration
 	terminationDelay time.Duration
+	timeouts         ConnTimeouts
 	proxyProtocol    *dynamic.ProxyProtocol
-	refreshTarget    bool
+	tlvs             []proxyProtocolTLV
+	handshakeTimeout time.Duration
+	metrics          *metrics.ConnMetrics
+
+	lock  sync.Mutex
+	conns map[*forwardedConn]struct{}
 }
 
 // NewProxy creates a new Proxy.
-func NewProxy(address string, terminationDelay time.Duration, proxyProtocol *dynamic.ProxyProtocol) (*Proxy, error) {
-	tcpAddr, err := net.ResolveTCPAddr("tcp", address)
-	if err != nil {
-		return nil, err
-	}
-
+// The host name of the address, if any, is resolved when dialing the server, according to the given dialConfig.
+// The forwarded connections are closed once they reach any of the given timeouts.
+// The given connMetrics, if not nil, records the connections forwarded by the proxy.
+func NewProxy(address string, terminationDelay time.Duration, proxyProtocol *dynamic.ProxyProtocol, dialConfig DialConfig, timeouts ConnTimeouts, connMetrics *metrics.ConnMetrics) (*Proxy, error) {
 	if proxyProtocol != nil && (proxyProtocol.Version < 1 || proxyProtocol.Version > 2) {
 		return nil, fmt.Errorf("unknown proxyProtocol version: %d", proxyProtocol.Version)
 	}
 
-	// enable the refresh of the target only if the address in not an IP
-	refreshTarget := false
-	if host, _, err := net.SplitHostPort(address); err == nil && net.ParseIP(host) == nil {
-		refreshTarget = true
+	var tlvs []proxyProtocolTLV
+	if proxyProtocol != nil && len(proxyProtocol.TLVs) > 0 {
+		if proxyProtocol.Version != 2 {
+			return nil, fmt.Errorf("proxyProtocol TLVs are only supported by the version 2, not by the version %d", proxyProtocol.Version)
+		}
+
+		var err error
+		tlvs, err = newProxyProtocolTLVs(proxyProtocol.TLVs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if strings.HasPrefix(address, UnixSocketPrefix) {
+		unixSocket := strings.TrimPrefix(address, UnixSocketPrefix)
+		if unixSocket == "" {
+			return nil, errors.New("missing unix socket path")
+		}
+
+		return &Proxy{
+			address:          address,
+			unixSocket:       unixSocket,
+			dialTimeout:      dialConfig.Timeout,
+			terminationDelay: terminationDelay,
+			timeouts:         timeouts,
+			proxyProtocol:    proxyProtocol,
+			tlvs:             tlvs,
+			handshakeTimeout: defaultHandshakeTimeout,
+			metrics:          connMetrics,
+			conns:            make(map[*forwardedConn]struct{}),
+		}, nil
+	}
+
+	d, err := newDialer(address, dialConfig)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Proxy{
 		address:          address,
-		target:           tcpAddr,
-		refreshTarget:    refreshTarget,
+		dialer:           d,
+		dialTimeout:      dialConfig.Timeout,
 		terminationDelay: terminationDelay,
+		timeouts:         timeouts,
 		proxyProtocol:    proxyProtocol,
+		tlvs:             tlvs,
+		handshakeTimeout: defaultHandshakeTimeout,
+		metrics:          connMetrics,
+		conns:            make(map[*forwardedConn]struct{}),
 	}, nil
 }
 
 // ServeTCP forwards the connection to a service.
+// If the server cannot be reached, and the connection is served by a load balancer,
+// the connection is left open for the load balancer to try the next server.
 func (p *Proxy) ServeTCP(conn WriteCloser) {
 	log.WithoutContext().Debugf("Handling connection from %s", conn.RemoteAddr())
 
-	// needed because of e.g. server.trackedConnection
-	defer conn.Close()
-
 	connBackend, err := p.dialBackend()
 	if err != nil {
+		p.metrics.DialFailed(p.address)
 		log.WithoutContext().Errorf("Error while connecting to backend: %v", err)
+
+		if !retryNextServer(conn) {
+			conn.Close()
+		}
 		return
 	}
 
+	// needed because of e.g. server.trackedConnection
+	defer conn.Close()
+
 	// maybe not needed, but just in case
 	defer connBackend.Close()
-	errChan := make(chan error)
 
+	var header *proxyproto.Header
 	if p.proxyProtocol != nil && p.proxyProtocol.Version > 0 && p.proxyProtocol.Version < 3 {
-		header := proxyproto.HeaderProxyFromAddrs(byte(p.proxyProtocol.Version), conn.RemoteAddr(), conn.LocalAddr())
+		header, err = p.proxyProtocolHeader(conn)
+		if err != nil {
+			log.WithoutContext().Errorf("Error while building proxy protocol headers: %v", err)
+			return
+		}
+	}
+
+	p.metrics.Opened()
+	start := time.Now()
+	defer p.metrics.Closed(start)
+
+	errChan := make(chan error)
+
+	if header != nil {
 		if _, err := header.WriteTo(connBackend); err != nil {
 			log.WithoutContext().Errorf("Error while writing proxy protocol headers to backend connection: %v", err)
 			return
 		}
 	}
 
-	go p.connCopy(conn, connBackend, errChan)
-	go p.connCopy(connBackend, conn, errChan)
+	if c, ok := getStartTLSConn(conn); ok && c.terminated {
+		if err := p.skipServerGreeting(connBackend, c.protocol); err != nil {
+			log.WithoutContext().Errorf("Error while reading the %s greeting of the backend: %v", c.protocol, err)
+			return
+		}
+	}
+
+	fc := p.track(conn, connBackend)
+	defer p.untrack(fc)
+
+	if p.timeouts.MaxLifetime > 0 {
+		lifetime := time.AfterFunc(p.timeouts.MaxLifetime, func() { fc.close(CloseReasonMaxLifetime) })
+		defer lifetime.Stop()
+	}
+
+	src, srcBackend := conn, connBackend
+	if p.timeouts.Idle > 0 {
+		idle := newIdleTimer(p.timeouts.Idle, func() { fc.close(CloseReasonIdleTimeout) })
+		defer idle.stop()
+
+		src = activityReader{WriteCloser: conn, idle: idle}
+		srcBackend = activityReader{WriteCloser: connBackend, idle: idle}
+	}
+
+	go p.connCopy(conn, srcBackend, errChan, p.metrics.SentWriter)
+	go p.connCopy(connBackend, src, errChan, p.metrics.ReceivedWriter)
 
 	err = <-errChan
-	if err != nil {
+	if reason := fc.closeReason(); reason != "" {
+		log.WithoutContext().Debugf("Connection from %s closed: %s", conn.RemoteAddr(), reason)
+	} else if err != nil {
 		log.WithoutContext().Errorf("Error during connection: %v", err)
 	}
 
 	<-errChan
 }
 
-func (p Proxy) dialBackend() (*net.TCPConn, error) {
-	if !p.refreshTarget {
-		return net.DialTCP("tcp", nil, p.target)
+// Drain closes the connections forwarded by the proxy once the given timeout has elapsed.
+// It is called once the proxy is no longer used to forward new connections.
+func (p *Proxy) Drain(timeout time.Duration) {
+	time.AfterFunc(timeout, func() {
+		p.lock.Lock()
+		conns := make([]*forwardedConn, 0, len(p.conns))
+		for fc := range p.conns {
+			conns = append(conns, fc)
+		}
+		p.lock.Unlock()
+
+		for _, fc := range conns {
+			fc.close(CloseReasonDrained)
+		}
+	})
+}
+
+// skipServerGreeting skips the greeting of the server of a connection upgraded with STARTTLS and terminated by Traefik,
+// as the client already received the greeting of Traefik before upgrading the connection.
+// The greeting is expected within the dial timeout of the proxy, or within the default greeting timeout.
+func (p *Proxy) skipServerGreeting(connBackend WriteCloser, protocol string) error {
+	timeout := defaultGreetingTimeout
+	if p.dialTimeout > 0 {
+		timeout = p.dialTimeout
 	}
 
-	conn, err := net.Dial("tcp", p.address)
-	if err != nil {
+	if err := connBackend.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+
+	if err := skipServerGreeting(connBackend, protocol); err != nil {
+		return err
+	}
+
+	return connBackend.SetReadDeadline(time.Time{})
+}
+
+// ConnCount returns the number of connections currently forwarded by the proxy.
+func (p *Proxy) ConnCount() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return len(p.conns)
+}
+
+func (p *Proxy) track(conn, connBackend WriteCloser) *forwardedConn {
+	fc := &forwardedConn{client: conn, backend: connBackend}
+
+	p.lock.Lock()
+	p.conns[fc] = struct{}{}
+	p.lock.Unlock()
+
+	return fc
+}
+
+func (p *Proxy) untrack(fc *forwardedConn) {
+	p.lock.Lock()
+	delete(p.conns, fc)
+	p.lock.Unlock()
+}
+
+// proxyProtocolHeader returns the Proxy Protocol header of the given client connection, with the configured TLVs.
+func (p *Proxy) proxyProtocolHeader(conn WriteCloser) (*proxyproto.Header, error) {
+	header := proxyproto.HeaderProxyFromAddrs(byte(p.proxyProtocol.Version), proxyprotocol.UnwrapAddr(conn.RemoteAddr()), conn.LocalAddr())
+	if len(p.tlvs) == 0 {
+		return header, nil
+	}
+
+	var tlvs []proxyproto.TLV
+	for _, tlv := range p.tlvs {
+		switch tlv.from {
+		case tlvFromSNI, tlvFromALPN:
+			// The server name and the application protocol are only known once the TLS handshake is done.
+			if tlsConn, ok := GetTLSConn(conn); ok {
+				if err := p.handshake(tlsConn); err != nil {
+					return nil, err
+				}
+			}
+
+			value := GetServerName(conn)
+			if tlv.from == tlvFromALPN {
+				value = GetALPN(conn)
+			}
+
+			if value != "" {
+				tlvs = append(tlvs, proxyproto.TLV{Type: tlv.typ, Value: []byte(value)})
+			}
+
+		case tlvFromClient:
+			for _, clientTLV := range proxyprotocol.GetTLVs(conn.RemoteAddr()) {
+				if clientTLV.Type == tlv.typ {
+					tlvs = append(tlvs, clientTLV)
+				}
+			}
+
+		default:
+			tlvs = append(tlvs, proxyproto.TLV{Type: tlv.typ, Value: tlv.value})
+		}
+	}
+
+	if err := header.SetTLVs(tlvs); err != nil {
 		return nil, err
 	}
 
-	return conn.(*net.TCPConn), nil
+	return header, nil
+}
+
+// handshake completes the TLS handshake of the client connection within the handshake timeout.
+func (p *Proxy) handshake(tlsConn *tls.Conn) error {
+	if err := tlsConn.SetDeadline(time.Now().Add(p.handshakeTimeout)); err != nil {
+		return err
+	}
+
+	if err := tlsConn.Handshake(); err != nil {
+		return err
+	}
+
+	return tlsConn.SetDeadline(time.Time{})
+}
+
+func (p *Proxy) dialBackend() (WriteCloser, error) {
+	if p.unixSocket != "" {
+		var timeout time.Duration
+		if p.dialTimeout > 0 {
+			timeout = p.dialTimeout
+		}
+
+		conn, err := net.DialTimeout("unix", p.unixSocket, timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		return conn.(*net.UnixConn), nil
+	}
+
+	return p.dialer.dial()
 }
 
-func (p Proxy) connCopy(dst, src WriteCloser, errCh chan error) {
-	_, err := io.Copy(dst, src)
+// connCopy copies src to dst, through the writer returned by count to record the copied bytes, and reports to errCh.
+func (p *Proxy) connCopy(dst, src WriteCloser, errCh chan error, count func(io.Writer) io.Writer) {
+	_, err := io.Copy(count(dst), src)
 	errCh <- err
 
 	errClose := dst.CloseWrite()
@@ -112,3 +347,47 @@ func (p Proxy) connCopy(dst, src WriteCloser, errCh chan error) {
 		}
 	}
 }
+
+// proxyProtocolTLV is a TLV sent in the Proxy Protocol header.
+type proxyProtocolTLV struct {
+	typ   proxyproto.PP2Type
+	value []byte
+	from  string
+}
+
+func newProxyProtocolTLVs(config []dynamic.ProxyProtocolTLV) ([]proxyProtocolTLV, error) {
+	var tlvs []proxyProtocolTLV
+	for _, cfg := range config {
+		tlv := proxyProtocolTLV{value: []byte(cfg.Value), from: strings.ToLower(cfg.From)}
+
+		typ := cfg.Type
+		switch tlv.from {
+		case "":
+		case tlvFromSNI:
+			if typ == "" {
+				typ = "authority"
+			}
+		case tlvFromALPN:
+			if typ == "" {
+				typ = "alpn"
+			}
+		case tlvFromClient:
+		default:
+			return nil, fmt.Errorf("unknown source %q of the proxyProtocol TLV", cfg.From)
+		}
+
+		if typ == "" {
+			return nil, errors.New("missing type of the proxyProtocol TLV")
+		}
+
+		var err error
+		tlv.typ, err = proxyprotocol.ParseType(typ)
+		if err != nil {
+			return nil, err
+		}
+
+		tlvs = append(tlvs, tlv)
+	}
+
+	return tlvs, nil
+}
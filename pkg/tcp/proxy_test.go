@@ -2,10 +2,12 @@ package tcp
 
 import (
 	"bytes"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
 	"net"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -13,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/proxyprotocol"
 )
 
 func fakeRedis(t *testing.T, listener net.Listener) {
@@ -54,7 +57,7 @@ func TestCloseWrite(t *testing.T) {
 	_, port, err := net.SplitHostPort(backendListener.Addr().String())
 	require.NoError(t, err)
 
-	proxy, err := NewProxy(":"+port, 10*time.Millisecond, nil)
+	proxy, err := NewProxy(":"+port, 10*time.Millisecond, nil, DialConfig{}, ConnTimeouts{}, nil)
 	require.NoError(t, err)
 
 	proxyListener, err := net.Listen("tcp", ":0")
@@ -88,6 +91,45 @@ func TestCloseWrite(t *testing.T) {
 	require.Equal(t, "PONG", buffer.String())
 }
 
+func TestUnixSocketBackend(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "redis.sock")
+
+	backendListener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	go fakeRedis(t, backendListener)
+
+	proxy, err := NewProxy(UnixSocketPrefix+socket, 10*time.Millisecond, nil, DialConfig{}, ConnTimeouts{}, nil)
+	require.NoError(t, err)
+
+	proxyListener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := proxyListener.Accept()
+			require.NoError(t, err)
+			proxy.ServeTCP(conn.(*net.TCPConn))
+		}
+	}()
+
+	conn, err := net.Dial("tcp", proxyListener.Addr().String())
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte("ping\n"))
+	require.NoError(t, err)
+
+	err = conn.(*net.TCPConn).CloseWrite()
+	require.NoError(t, err)
+
+	var buf []byte
+	buffer := bytes.NewBuffer(buf)
+	n, err := io.Copy(buffer, conn)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), n)
+	require.Equal(t, "PONG", buffer.String())
+}
+
 func TestProxyProtocol(t *testing.T) {
 	testCases := []struct {
 		desc    string
@@ -133,7 +175,7 @@ func TestProxyProtocol(t *testing.T) {
 			_, port, err := net.SplitHostPort(proxyBackendListener.Addr().String())
 			require.NoError(t, err)
 
-			proxy, err := NewProxy(":"+port, 10*time.Millisecond, &dynamic.ProxyProtocol{Version: test.version})
+			proxy, err := NewProxy(":"+port, 10*time.Millisecond, &dynamic.ProxyProtocol{Version: test.version}, DialConfig{}, ConnTimeouts{}, nil)
 			require.NoError(t, err)
 
 			proxyListener, err := net.Listen("tcp", ":0")
@@ -172,6 +214,154 @@ func TestProxyProtocol(t *testing.T) {
 	}
 }
 
+func TestProxyProtocolTLVs(t *testing.T) {
+	clientAddr := &proxyprotocol.Addr{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 40000},
+		TLVs: []proxyproto.TLV{
+			{Type: 0xEA, Value: []byte("\x01vpce-123")},
+			{Type: proxyproto.PP2_TYPE_UNIQUE_ID, Value: []byte("id")},
+		},
+	}
+
+	testCases := []struct {
+		desc         string
+		tlvs         []dynamic.ProxyProtocolTLV
+		expectedTLVs []proxyproto.TLV
+	}{
+		{
+			desc: "static value",
+			tlvs: []dynamic.ProxyProtocolTLV{{Type: "0xE0", Value: "foo"}},
+			expectedTLVs: []proxyproto.TLV{
+				{Type: 0xE0, Value: []byte("foo")},
+			},
+		},
+		{
+			desc: "server name and application protocol",
+			tlvs: []dynamic.ProxyProtocolTLV{{From: "sni"}, {From: "alpn"}},
+			expectedTLVs: []proxyproto.TLV{
+				{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("foo.bar")},
+				{Type: proxyproto.PP2_TYPE_ALPN, Value: []byte("h2")},
+			},
+		},
+		{
+			desc: "server name with a custom type",
+			tlvs: []dynamic.ProxyProtocolTLV{{Type: "0xE1", From: "sni"}},
+			expectedTLVs: []proxyproto.TLV{
+				{Type: 0xE1, Value: []byte("foo.bar")},
+			},
+		},
+		{
+			desc: "TLV of the client",
+			tlvs: []dynamic.ProxyProtocolTLV{{Type: "0xEA", From: "client"}},
+			expectedTLVs: []proxyproto.TLV{
+				{Type: 0xEA, Value: []byte("\x01vpce-123")},
+			},
+		},
+		{
+			desc: "missing TLV of the client",
+			tlvs: []dynamic.ProxyProtocolTLV{{Type: "netns", From: "client"}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			proxy, err := NewProxy("127.0.0.1:8080", 10*time.Millisecond, &dynamic.ProxyProtocol{Version: 2, TLVs: test.tlvs}, DialConfig{}, ConnTimeouts{}, nil)
+			require.NoError(t, err)
+
+			conn := &Conn{
+				ServerName:    "foo.bar",
+				ALPNProtocols: []string{"h2", "http/1.1"},
+				WriteCloser:   &addrConn{remoteAddr: clientAddr},
+			}
+
+			header, err := proxy.proxyProtocolHeader(conn)
+			require.NoError(t, err)
+
+			assert.Equal(t, clientAddr.Addr, header.SourceAddr)
+
+			tlvs, err := header.TLVs()
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedTLVs, tlvs)
+		})
+	}
+}
+
+func TestProxyProtocolTLVs_handshakeTimeout(t *testing.T) {
+	proxy, err := NewProxy("127.0.0.1:8080", 10*time.Millisecond, &dynamic.ProxyProtocol{Version: 2, TLVs: []dynamic.ProxyProtocolTLV{{From: "sni"}}}, DialConfig{}, ConnTimeouts{}, nil)
+	require.NoError(t, err)
+	proxy.handshakeTimeout = 50 * time.Millisecond
+
+	// The client never sends its ClientHello.
+	client, server := net.Pipe()
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = server.Close()
+	})
+
+	start := time.Now()
+
+	_, err = proxy.proxyProtocolHeader(tls.Server(server, &tls.Config{}))
+
+	var netErr net.Error
+	require.ErrorAs(t, err, &netErr)
+	assert.True(t, netErr.Timeout())
+	assert.Less(t, int64(time.Since(start)), int64(defaultHandshakeTimeout))
+}
+
+func TestNewProxy_invalidProxyProtocolTLVs(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		proxyProtocol *dynamic.ProxyProtocol
+	}{
+		{
+			desc:          "version 1",
+			proxyProtocol: &dynamic.ProxyProtocol{Version: 1, TLVs: []dynamic.ProxyProtocolTLV{{Type: "authority", Value: "foo"}}},
+		},
+		{
+			desc:          "missing type",
+			proxyProtocol: &dynamic.ProxyProtocol{Version: 2, TLVs: []dynamic.ProxyProtocolTLV{{Value: "foo"}}},
+		},
+		{
+			desc:          "unknown type",
+			proxyProtocol: &dynamic.ProxyProtocol{Version: 2, TLVs: []dynamic.ProxyProtocolTLV{{Type: "foo", Value: "foo"}}},
+		},
+		{
+			desc:          "unknown source",
+			proxyProtocol: &dynamic.ProxyProtocol{Version: 2, TLVs: []dynamic.ProxyProtocolTLV{{From: "foo"}}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewProxy("127.0.0.1:8080", 10*time.Millisecond, test.proxyProtocol, DialConfig{}, ConnTimeouts{}, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+type addrConn struct {
+	WriteCloser
+
+	remoteAddr net.Addr
+}
+
+func (c *addrConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+func (c *addrConn) LocalAddr() net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 443}
+}
+
 func TestLookupAddress(t *testing.T) {
 	testCases := []struct {
 		desc          string
@@ -194,10 +384,10 @@ func TestLookupAddress(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			proxy, err := NewProxy(test.address, 10*time.Millisecond, nil)
+			proxy, err := NewProxy(test.address, 10*time.Millisecond, nil, DialConfig{}, ConnTimeouts{}, nil)
 			require.NoError(t, err)
 
-			require.NotNil(t, proxy.target)
+			require.NotNil(t, proxy.dialer)
 
 			conn, err := proxy.dialBackend()
 			require.NoError(t, err)
@@ -210,3 +400,150 @@ func TestLookupAddress(t *testing.T) {
 		})
 	}
 }
+
+func echoServer(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				_, _ = io.Copy(conn, conn)
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func serveProxy(t *testing.T, proxy *Proxy) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go proxy.ServeTCP(conn.(*net.TCPConn))
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestProxy_timeouts(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		timeouts ConnTimeouts
+		// activity is the duration during which the client keeps exchanging data.
+		activity time.Duration
+		// closedAfter is the minimal duration after which the connection is expected to be closed.
+		closedAfter time.Duration
+	}{
+		{
+			desc:        "idle timeout, postponed by the activity",
+			timeouts:    ConnTimeouts{Idle: 100 * time.Millisecond},
+			activity:    300 * time.Millisecond,
+			closedAfter: 400 * time.Millisecond,
+		},
+		{
+			desc:        "max lifetime, despite the activity",
+			timeouts:    ConnTimeouts{MaxLifetime: 200 * time.Millisecond},
+			activity:    time.Second,
+			closedAfter: 200 * time.Millisecond,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			proxy, err := NewProxy(echoServer(t), 10*time.Millisecond, nil, DialConfig{}, test.timeouts, nil)
+			require.NoError(t, err)
+
+			start := time.Now()
+
+			conn, err := net.Dial("tcp", serveProxy(t, proxy))
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				_, _ = io.Copy(io.Discard, conn)
+			}()
+
+			ticker := time.NewTicker(20 * time.Millisecond)
+			defer ticker.Stop()
+
+			timeout := time.After(test.activity)
+		activity:
+			for {
+				select {
+				case <-ticker.C:
+					if _, err := conn.Write([]byte("ping")); err != nil {
+						break activity
+					}
+				case <-timeout:
+					break activity
+				case <-closed:
+					break activity
+				}
+			}
+
+			select {
+			case <-closed:
+			case <-time.After(2 * time.Second):
+				t.Fatal("the connection was not closed")
+			}
+
+			assert.GreaterOrEqual(t, int64(time.Since(start)), int64(test.closedAfter))
+			assert.Equal(t, 0, proxy.ConnCount())
+		})
+	}
+}
+
+func TestProxy_Drain(t *testing.T) {
+	proxy, err := NewProxy(echoServer(t), 10*time.Millisecond, nil, DialConfig{}, ConnTimeouts{}, nil)
+	require.NoError(t, err)
+
+	conn, err := net.Dial("tcp", serveProxy(t, proxy))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+
+	assert.Equal(t, 1, proxy.ConnCount())
+
+	start := time.Now()
+	proxy.Drain(100 * time.Millisecond)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	_, err = conn.Read(buf)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(100*time.Millisecond))
+
+	assert.Eventually(t, func() bool { return proxy.ConnCount() == 0 }, time.Second, 10*time.Millisecond)
+}
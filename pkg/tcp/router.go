@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
@@ -19,23 +20,37 @@ const defaultBufSize = 4096
 
 // Router is a TCP router.
 type Router struct {
-	routingTable      map[string]Handler
-	httpForwarder     Handler
-	httpsForwarder    Handler
-	httpHandler       http.Handler
-	httpsHandler      http.Handler
-	httpsTLSConfig    *tls.Config // default TLS config
-	catchAllNoTLS     Handler
-	hostHTTPTLSConfig map[string]*tls.Config // TLS configs keyed by SNI
+	routingTable         map[string]Handler
+	httpForwarder        Handler
+	httpsForwarder       Handler
+	httpHandler          http.Handler
+	httpsHandler         http.Handler
+	httpsTLSConfig       *tls.Config // default TLS config
+	catchAllNoTLS        Handler
+	protocolRoutes       map[string]Handler // handlers of the non-TLS connections keyed by protocol
+	protocolSniffTimeout time.Duration
+	readTimeout          time.Duration          // read timeout of the entry point, enforced while peeking the first bytes
+	hostHTTPTLSConfig    map[string]*tls.Config // TLS configs keyed by SNI
 }
 
 // GetTLSGetClientInfo is called after a ClientHello is received from a client.
 func (r *Router) GetTLSGetClientInfo() func(info *tls.ClientHelloInfo) (*tls.Config, error) {
 	return func(info *tls.ClientHelloInfo) (*tls.Config, error) {
-		if tlsConfig, ok := r.hostHTTPTLSConfig[info.ServerName]; ok {
-			return tlsConfig, nil
+		tlsConfig, ok := r.hostHTTPTLSConfig[info.ServerName]
+		if !ok {
+			tlsConfig = r.httpsTLSConfig
 		}
-		return r.httpsTLSConfig, nil
+
+		// The configuration returned by GetConfigForClient is not evaluated again,
+		// so the configuration selected by the SNI options of the TLS store is returned directly.
+		if tlsConfig != nil && tlsConfig.GetConfigForClient != nil {
+			sniConfig, err := tlsConfig.GetConfigForClient(info)
+			if err != nil || sniConfig != nil {
+				return sniConfig, err
+			}
+		}
+
+		return tlsConfig, nil
 	}
 }
 
@@ -43,31 +58,46 @@ func (r *Router) GetTLSGetClientInfo() func(info *tls.ClientHelloInfo) (*tls.Con
 func (r *Router) ServeTCP(conn WriteCloser) {
 	// FIXME -- Check if ProxyProtocol changes the first bytes of the request
 
-	if r.catchAllNoTLS != nil && len(r.routingTable) == 0 {
+	if r.catchAllNoTLS != nil && len(r.routingTable) == 0 && len(r.protocolRoutes) == 0 {
 		r.catchAllNoTLS.ServeTCP(conn)
 		return
 	}
 
 	br := bufio.NewReader(conn)
-	serverName, tls, peeked, err := clientHelloServerName(br)
-	if err != nil {
-		conn.Close()
+
+	// The read deadline of the entry point, restored once the protocol sniff timeout does not apply anymore.
+	var readDeadline time.Time
+	if r.readTimeout > 0 {
+		readDeadline = time.Now().Add(r.readTimeout)
+	}
+
+	// The MySQL client waits for the greeting of the server before sending anything.
+	if target, ok := r.protocolRoutes[ProtocolMySQL]; ok && !clientSpeaksFirst(conn, br, r.getProtocolSniffTimeout(), readDeadline) {
+		removeDeadlines(conn)
+		target.ServeTCP(conn)
 		return
 	}
 
-	// Remove read/write deadline and delegate this to underlying tcp server (for now only handled by HTTP Server)
-	err = conn.SetReadDeadline(time.Time{})
+	hello, tls, peeked, err := clientHelloInfo(br)
 	if err != nil {
-		log.WithoutContext().Errorf("Error while setting read deadline: %v", err)
+		conn.Close()
+		return
 	}
 
-	err = conn.SetWriteDeadline(time.Time{})
-	if err != nil {
-		log.WithoutContext().Errorf("Error while setting write deadline: %v", err)
+	// The protocol is recognized before removing the deadlines, as more bytes may have to be read from the client.
+	var protocolTarget Handler
+	if !tls && len(r.protocolRoutes) > 0 {
+		protocolTarget = r.sniffProtocol(conn, br, readDeadline)
+		peeked = getPeeked(br)
 	}
 
+	// Remove read/write deadline and delegate this to underlying tcp server (for now only handled by HTTP Server)
+	removeDeadlines(conn)
+
 	if !tls {
 		switch {
+		case protocolTarget != nil:
+			protocolTarget.ServeTCP(r.GetConn(conn, peeked))
 		case r.catchAllNoTLS != nil:
 			r.catchAllNoTLS.ServeTCP(r.GetConn(conn, peeked))
 		case r.httpForwarder != nil:
@@ -79,17 +109,17 @@ func (r *Router) ServeTCP(conn WriteCloser) {
 	}
 
 	// FIXME Optimize and test the routing table before helloServerName
-	serverName = types.CanonicalDomain(serverName)
+	serverName := types.CanonicalDomain(hello.serverName)
 	if r.routingTable != nil && serverName != "" {
 		if target, ok := r.routingTable[serverName]; ok {
-			target.ServeTCP(r.GetConn(conn, peeked))
+			target.ServeTCP(r.getTLSConn(conn, peeked, hello))
 			return
 		}
 	}
 
 	// FIXME Needs tests
 	if target, ok := r.routingTable["*"]; ok {
-		target.ServeTCP(r.GetConn(conn, peeked))
+		target.ServeTCP(r.getTLSConn(conn, peeked, hello))
 		return
 	}
 
@@ -100,6 +130,18 @@ func (r *Router) ServeTCP(conn WriteCloser) {
 	}
 }
 
+func removeDeadlines(conn WriteCloser) {
+	err := conn.SetReadDeadline(time.Time{})
+	if err != nil {
+		log.WithoutContext().Errorf("Error while setting read deadline: %v", err)
+	}
+
+	err = conn.SetWriteDeadline(time.Time{})
+	if err != nil {
+		log.WithoutContext().Errorf("Error while setting write deadline: %v", err)
+	}
+}
+
 // AddRoute defines a handler for a given sniHost (* is the only valid option).
 func (r *Router) AddRoute(sniHost string, target Handler) {
 	if r.routingTable == nil {
@@ -124,6 +166,22 @@ func (r *Router) AddRouteHTTPTLS(sniHost string, config *tls.Config) {
 	r.hostHTTPTLSConfig[sniHost] = config
 }
 
+// AddRouteProtocol defines a handler for the non-TLS connections of a given protocol,
+// recognized from the first bytes sent by the client.
+func (r *Router) AddRouteProtocol(protocol string, target Handler) error {
+	protocol = strings.ToLower(protocol)
+	if !isSniffedProtocol(protocol) {
+		return fmt.Errorf("unsupported protocol %q", protocol)
+	}
+
+	if r.protocolRoutes == nil {
+		r.protocolRoutes = map[string]Handler{}
+	}
+	r.protocolRoutes[protocol] = target
+
+	return nil
+}
+
 // AddCatchAllNoTLS defines the fallback tcp handler.
 func (r *Router) AddCatchAllNoTLS(handler Handler) {
 	r.catchAllNoTLS = handler
@@ -139,6 +197,17 @@ func (r *Router) GetConn(conn WriteCloser, peeked string) WriteCloser {
 	return conn
 }
 
+// getTLSConn creates a connection proxy with a peeked string,
+// keeping track of the server name and application protocols indicated by the client.
+func (r *Router) getTLSConn(conn WriteCloser, peeked string, hello clientHello) WriteCloser {
+	return &Conn{
+		Peeked:        []byte(peeked),
+		ServerName:    hello.serverName,
+		ALPNProtocols: hello.protos,
+		WriteCloser:   conn,
+	}
+}
+
 // GetHTTPHandler gets the attached http handler.
 func (r *Router) GetHTTPHandler() http.Handler {
 	return r.httpHandler
@@ -184,6 +253,12 @@ type Conn struct {
 	// by Read calls. It set to nil by Read when fully consumed.
 	Peeked []byte
 
+	// ServerName is the server name indicated by the client in its TLS ClientHello, if any.
+	ServerName string
+
+	// ALPNProtocols are the application protocols offered by the client in its TLS ClientHello, if any.
+	ALPNProtocols []string
+
 	// Conn is the underlying connection.
 	// It can be type asserted against *net.TCPConn or other types
 	// as needed. It should not be read from directly unless
@@ -204,10 +279,16 @@ func (c *Conn) Read(p []byte) (n int, err error) {
 	return c.WriteCloser.Read(p)
 }
 
-// clientHelloServerName returns the SNI server name inside the TLS ClientHello,
+// clientHello holds the information of a TLS ClientHello used by the router.
+type clientHello struct {
+	serverName string
+	protos     []string
+}
+
+// clientHelloInfo returns the SNI server name and the ALPN protocols inside the TLS ClientHello,
 // without consuming any bytes from br.
-// On any error, the empty string is returned.
-func clientHelloServerName(br *bufio.Reader) (string, bool, string, error) {
+// On any error, empty values are returned.
+func clientHelloInfo(br *bufio.Reader) (clientHello, bool, string, error) {
 	hdr, err := br.Peek(1)
 	if err != nil {
 		var opErr *net.OpError
@@ -215,7 +296,7 @@ func clientHelloServerName(br *bufio.Reader) (string, bool, string, error) {
 			log.WithoutContext().Debugf("Error while Peeking first byte: %s", err)
 		}
 
-		return "", false, "", err
+		return clientHello{}, false, "", err
 	}
 
 	// No valid TLS record has a type of 0x80, however SSLv2 handshakes
@@ -227,16 +308,16 @@ func clientHelloServerName(br *bufio.Reader) (string, bool, string, error) {
 	if hdr[0] != recordTypeHandshake {
 		if hdr[0] == recordTypeSSLv2 {
 			// we consider SSLv2 as TLS and it will be refuse by real TLS handshake.
-			return "", true, getPeeked(br), nil
+			return clientHello{}, true, getPeeked(br), nil
 		}
-		return "", false, getPeeked(br), nil // Not TLS.
+		return clientHello{}, false, getPeeked(br), nil // Not TLS.
 	}
 
 	const recordHeaderLen = 5
 	hdr, err = br.Peek(recordHeaderLen)
 	if err != nil {
 		log.Errorf("Error while Peeking hello: %s", err)
-		return "", false, getPeeked(br), nil
+		return clientHello{}, false, getPeeked(br), nil
 	}
 
 	recLen := int(hdr[3])<<8 | int(hdr[4]) // ignoring version in hdr[1:3]
@@ -248,19 +329,20 @@ func clientHelloServerName(br *bufio.Reader) (string, bool, string, error) {
 	helloBytes, err := br.Peek(recordHeaderLen + recLen)
 	if err != nil {
 		log.Errorf("Error while Hello: %s", err)
-		return "", true, getPeeked(br), nil
+		return clientHello{}, true, getPeeked(br), nil
 	}
 
-	sni := ""
+	var hello clientHello
 	server := tls.Server(sniSniffConn{r: bytes.NewReader(helloBytes)}, &tls.Config{
-		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
-			sni = hello.ServerName
+		GetConfigForClient: func(info *tls.ClientHelloInfo) (*tls.Config, error) {
+			hello.serverName = info.ServerName
+			hello.protos = info.SupportedProtos
 			return nil, nil
 		},
 	})
 	_ = server.Handshake()
 
-	return sni, true, getPeeked(br), nil
+	return hello, true, getPeeked(br), nil
 }
 
 func getPeeked(br *bufio.Reader) string {
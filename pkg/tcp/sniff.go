@@ -0,0 +1,229 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// Protocols of the non-TLS connections which can be routed with the Protocol matcher.
+const (
+	ProtocolSSH      = "ssh"
+	ProtocolMQTT     = "mqtt"
+	ProtocolRedis    = "redis"
+	ProtocolPostgres = "postgres"
+	ProtocolMySQL    = "mysql"
+)
+
+// defaultProtocolSniffTimeout is the default maximum duration to wait for the bytes needed to recognize the protocol of a connection.
+// A client which does not send anything during this duration is considered to wait for the server to speak first,
+// as in the MySQL protocol.
+const defaultProtocolSniffTimeout = time.Second
+
+// maxSniffLength is the number of bytes needed to recognize any of the protocols.
+const maxSniffLength = 13
+
+// PostgreSQL protocol version and request code sent by a client in its first message, besides the SSLRequest and GSSENCRequest.
+const (
+	postgresProtocolVersion3  = 196608
+	postgresCancelRequestCode = 80877102
+)
+
+type sniffResult int
+
+const (
+	sniffNoMatch sniffResult = iota
+	sniffMatch
+	sniffNeedMore
+)
+
+// sniffers recognize the protocols in which the client speaks first, from the first bytes it sent.
+var sniffers = map[string]func(data []byte) sniffResult{
+	ProtocolSSH:      sniffSSH,
+	ProtocolMQTT:     sniffMQTT,
+	ProtocolRedis:    sniffRedis,
+	ProtocolPostgres: sniffPostgres,
+}
+
+// isSniffedProtocol returns whether the protocol can be routed with the Protocol matcher.
+func isSniffedProtocol(protocol string) bool {
+	_, ok := sniffers[protocol]
+	return ok || protocol == ProtocolMySQL
+}
+
+// sniffSSH recognizes the identification string of an SSH client (RFC 4253 section 4.2).
+func sniffSSH(data []byte) sniffResult {
+	return sniffPrefix(data, []byte("SSH-"))
+}
+
+// sniffRedis recognizes a command sent as a RESP array by a Redis client.
+func sniffRedis(data []byte) sniffResult {
+	switch {
+	case len(data) == 0:
+		return sniffNeedMore
+	case data[0] != '*':
+		return sniffNoMatch
+	case len(data) == 1:
+		return sniffNeedMore
+	case data[1] >= '0' && data[1] <= '9':
+		return sniffMatch
+	default:
+		return sniffNoMatch
+	}
+}
+
+// sniffMQTT recognizes the CONNECT packet of an MQTT client, with the protocol name of MQTT 3.1 or later.
+func sniffMQTT(data []byte) sniffResult {
+	if len(data) == 0 {
+		return sniffNeedMore
+	}
+
+	const connectPacket = 0x10
+	if data[0] != connectPacket {
+		return sniffNoMatch
+	}
+
+	// The remaining length of the packet is encoded in 1 to 4 bytes.
+	i := 1
+	for ; ; i++ {
+		if i >= len(data) {
+			return sniffNeedMore
+		}
+		if data[i]&0x80 == 0 {
+			break
+		}
+		if i == 4 {
+			return sniffNoMatch
+		}
+	}
+
+	name := data[i+1:]
+	if result := sniffPrefix(name, []byte("\x00\x04MQTT")); result != sniffNoMatch {
+		return result
+	}
+
+	return sniffPrefix(name, []byte("\x00\x06MQIsdp"))
+}
+
+// sniffPostgres recognizes the first message of a PostgreSQL client.
+func sniffPostgres(data []byte) sniffResult {
+	// The message starts with its length, which is far less than 2^24.
+	if len(data) > 0 && data[0] != 0 {
+		return sniffNoMatch
+	}
+
+	if len(data) < 8 {
+		return sniffNeedMore
+	}
+
+	length := binary.BigEndian.Uint32(data[:4])
+	if length < 8 {
+		return sniffNoMatch
+	}
+
+	switch binary.BigEndian.Uint32(data[4:8]) {
+	case postgresProtocolVersion3, postgresSSLRequestCode, postgresGSSENCRequestCode, postgresCancelRequestCode:
+		return sniffMatch
+	default:
+		return sniffNoMatch
+	}
+}
+
+func sniffPrefix(data, prefix []byte) sniffResult {
+	if len(data) < len(prefix) {
+		if bytes.HasPrefix(prefix, data) {
+			return sniffNeedMore
+		}
+		return sniffNoMatch
+	}
+
+	if bytes.HasPrefix(data, prefix) {
+		return sniffMatch
+	}
+	return sniffNoMatch
+}
+
+// sniffProtocol returns the handler of the protocol recognized from the first bytes sent by the client, if any.
+// More bytes are read from the client, up to the protocol sniff timeout, as long as needed to recognize a protocol.
+// The given read deadline is restored on the connection afterwards.
+func (r *Router) sniffProtocol(conn WriteCloser, br *bufio.Reader, readDeadline time.Time) Handler {
+	for {
+		data, _ := br.Peek(br.Buffered())
+
+		var needMore bool
+		for protocol, target := range r.protocolRoutes {
+			sniff, ok := sniffers[protocol]
+			if !ok {
+				continue
+			}
+
+			switch sniff(data) {
+			case sniffMatch:
+				return target
+			case sniffNeedMore:
+				needMore = true
+			}
+		}
+
+		if !needMore || len(data) >= maxSniffLength {
+			return nil
+		}
+
+		if err := conn.SetReadDeadline(time.Now().Add(r.getProtocolSniffTimeout())); err != nil {
+			log.WithoutContext().Errorf("Error while setting read deadline: %v", err)
+		}
+
+		_, err := br.Peek(len(data) + 1)
+		setReadDeadline(conn, readDeadline)
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// clientSpeaksFirst returns whether the client sends something before the given timeout,
+// rather than waiting for the server to speak first.
+// The given read deadline is restored on the connection afterwards.
+func clientSpeaksFirst(conn WriteCloser, br *bufio.Reader, timeout time.Duration, readDeadline time.Time) bool {
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		log.WithoutContext().Errorf("Error while setting read deadline: %v", err)
+		return true
+	}
+
+	_, err := br.Peek(1)
+	setReadDeadline(conn, readDeadline)
+
+	var netErr net.Error
+	return !errors.As(err, &netErr) || !netErr.Timeout()
+}
+
+// SetProtocolSniffTimeout sets the maximum duration to wait for the bytes needed to recognize the protocol of a connection.
+// Zero or a negative value means the default of one second.
+func (r *Router) SetProtocolSniffTimeout(timeout time.Duration) {
+	r.protocolSniffTimeout = timeout
+}
+
+// SetReadTimeout sets the read timeout of the entry point,
+// whose deadline is restored on the connections once their protocol has been sniffed.
+// Zero or a negative value means no deadline.
+func (r *Router) SetReadTimeout(timeout time.Duration) {
+	r.readTimeout = timeout
+}
+
+func (r *Router) getProtocolSniffTimeout() time.Duration {
+	if r.protocolSniffTimeout <= 0 {
+		return defaultProtocolSniffTimeout
+	}
+	return r.protocolSniffTimeout
+}
+
+func setReadDeadline(conn WriteCloser, deadline time.Time) {
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		log.WithoutContext().Errorf("Error while setting read deadline: %v", err)
+	}
+}
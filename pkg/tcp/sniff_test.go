@@ -0,0 +1,320 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSniffers(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		protocol string
+		data     string
+		expected sniffResult
+	}{
+		{
+			desc:     "SSH identification string",
+			protocol: ProtocolSSH,
+			data:     "SSH-2.0-OpenSSH_8.9\r\n",
+			expected: sniffMatch,
+		},
+		{
+			desc:     "SSH partial identification string",
+			protocol: ProtocolSSH,
+			data:     "SS",
+			expected: sniffNeedMore,
+		},
+		{
+			desc:     "SSH, HTTP request",
+			protocol: ProtocolSSH,
+			data:     "GET / HTTP/1.1\r\n",
+			expected: sniffNoMatch,
+		},
+		{
+			desc:     "Redis RESP array",
+			protocol: ProtocolRedis,
+			data:     "*1\r\n$4\r\nPING\r\n",
+			expected: sniffMatch,
+		},
+		{
+			desc:     "Redis partial RESP array",
+			protocol: ProtocolRedis,
+			data:     "*",
+			expected: sniffNeedMore,
+		},
+		{
+			desc:     "Redis inline command",
+			protocol: ProtocolRedis,
+			data:     "PING\r\n",
+			expected: sniffNoMatch,
+		},
+		{
+			desc:     "MQTT 3.1.1 CONNECT",
+			protocol: ProtocolMQTT,
+			data:     "\x10\x10\x00\x04MQTT\x04\x02\x00\x3c\x00\x04test",
+			expected: sniffMatch,
+		},
+		{
+			desc:     "MQTT 3.1 CONNECT, with a two bytes remaining length",
+			protocol: ProtocolMQTT,
+			data:     "\x10\x80\x01\x00\x06MQIsdp\x03",
+			expected: sniffMatch,
+		},
+		{
+			desc:     "MQTT partial CONNECT",
+			protocol: ProtocolMQTT,
+			data:     "\x10\x10\x00\x04MQ",
+			expected: sniffNeedMore,
+		},
+		{
+			desc:     "MQTT, other packet",
+			protocol: ProtocolMQTT,
+			data:     "\x30\x10\x00\x04MQTT",
+			expected: sniffNoMatch,
+		},
+		{
+			desc:     "MQTT, invalid remaining length",
+			protocol: ProtocolMQTT,
+			data:     "\x10\x80\x80\x80\x80\x01",
+			expected: sniffNoMatch,
+		},
+		{
+			desc:     "PostgreSQL StartupMessage",
+			protocol: ProtocolPostgres,
+			data:     "\x00\x00\x00\x29\x00\x03\x00\x00user\x00postgres\x00",
+			expected: sniffMatch,
+		},
+		{
+			desc:     "PostgreSQL SSLRequest",
+			protocol: ProtocolPostgres,
+			data:     "\x00\x00\x00\x08\x04\xd2\x16\x2f",
+			expected: sniffMatch,
+		},
+		{
+			desc:     "PostgreSQL partial StartupMessage",
+			protocol: ProtocolPostgres,
+			data:     "\x00\x00\x00\x29",
+			expected: sniffNeedMore,
+		},
+		{
+			desc:     "PostgreSQL, unknown protocol version",
+			protocol: ProtocolPostgres,
+			data:     "\x00\x00\x00\x29\x00\x02\x00\x00",
+			expected: sniffNoMatch,
+		},
+		{
+			desc:     "PostgreSQL, SSH identification string",
+			protocol: ProtocolPostgres,
+			data:     "SSH-2.0",
+			expected: sniffNoMatch,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, sniffers[test.protocol]([]byte(test.data)))
+		})
+	}
+}
+
+func TestRouter_AddRouteProtocol(t *testing.T) {
+	router := &Router{}
+
+	assert.NoError(t, router.AddRouteProtocol("SSH", HandlerFunc(func(conn WriteCloser) {})))
+	assert.NoError(t, router.AddRouteProtocol(ProtocolMySQL, HandlerFunc(func(conn WriteCloser) {})))
+	assert.Error(t, router.AddRouteProtocol("ftp", HandlerFunc(func(conn WriteCloser) {})))
+
+	assert.Len(t, router.protocolRoutes, 2)
+	assert.Contains(t, router.protocolRoutes, ProtocolSSH)
+}
+
+func TestRouter_protocolRoutes(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		data             []string
+		expectedHandler  string
+		expectedReceived string
+	}{
+		{
+			desc:             "SSH",
+			data:             []string{"SSH-2.0-OpenSSH_8.9\r\n"},
+			expectedHandler:  ProtocolSSH,
+			expectedReceived: "SSH-2.0-OpenSSH_8.9\r\n",
+		},
+		{
+			desc:             "SSH, identification string sent in several parts",
+			data:             []string{"S", "SH-2.0\r\n"},
+			expectedHandler:  ProtocolSSH,
+			expectedReceived: "SSH-2.0\r\n",
+		},
+		{
+			desc:             "Redis",
+			data:             []string{"*1\r\n$4\r\nPING\r\n"},
+			expectedHandler:  ProtocolRedis,
+			expectedReceived: "*1\r\n$4\r\nPING\r\n",
+		},
+		{
+			desc:            "MySQL, the client waits for the server",
+			expectedHandler: ProtocolMySQL,
+		},
+		{
+			desc:             "Unknown protocol",
+			data:             []string{"HELLO\r\n"},
+			expectedHandler:  "catchAll",
+			expectedReceived: "HELLO\r\n",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			type served struct {
+				handler  string
+				received string
+			}
+			servedCh := make(chan served, 1)
+
+			newHandler := func(name string) Handler {
+				return HandlerFunc(func(conn WriteCloser) {
+					defer conn.Close()
+
+					_, _ = conn.Write([]byte(name))
+
+					received, _ := io.ReadAll(conn)
+					servedCh <- served{handler: name, received: string(received)}
+				})
+			}
+
+			router := &Router{}
+			router.AddCatchAllNoTLS(newHandler("catchAll"))
+			for _, protocol := range []string{ProtocolSSH, ProtocolRedis, ProtocolMySQL} {
+				require.NoError(t, router.AddRouteProtocol(protocol, newHandler(protocol)))
+			}
+
+			conn := serveHandler(t, router)
+			require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+			for _, data := range test.data {
+				_, err := conn.Write([]byte(data))
+				require.NoError(t, err)
+
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			// The handler speaks first.
+			greeting := make([]byte, len(test.expectedHandler))
+			_, err := io.ReadFull(conn, greeting)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedHandler, string(greeting))
+
+			require.NoError(t, conn.(interface{ CloseWrite() error }).CloseWrite())
+
+			result := <-servedCh
+			assert.Equal(t, test.expectedHandler, result.handler)
+			assert.Equal(t, test.expectedReceived, result.received)
+		})
+	}
+}
+
+func TestRouter_protocolSniffTimeout(t *testing.T) {
+	served := make(chan string, 1)
+	newHandler := func(name string) Handler {
+		return HandlerFunc(func(conn WriteCloser) {
+			served <- name
+			conn.Close()
+		})
+	}
+
+	router := &Router{}
+	router.SetProtocolSniffTimeout(50 * time.Millisecond)
+	router.AddCatchAllNoTLS(newHandler("catchAll"))
+	require.NoError(t, router.AddRouteProtocol(ProtocolMySQL, newHandler(ProtocolMySQL)))
+
+	conn := serveHandler(t, router)
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	start := time.Now()
+
+	select {
+	case name := <-served:
+		assert.Equal(t, ProtocolMySQL, name)
+		assert.Less(t, int64(time.Since(start)), int64(defaultProtocolSniffTimeout))
+	case <-time.After(5 * time.Second):
+		t.Fatal("the connection was not served")
+	}
+}
+
+func TestRouter_readDeadlineRestoredAfterSniff(t *testing.T) {
+	served := make(chan string, 1)
+	newHandler := func(name string) Handler {
+		return HandlerFunc(func(conn WriteCloser) {
+			served <- name
+			conn.Close()
+		})
+	}
+
+	router := &Router{}
+	router.SetProtocolSniffTimeout(50 * time.Millisecond)
+	router.SetReadTimeout(5 * time.Second)
+	router.AddCatchAllNoTLS(newHandler("catchAll"))
+	router.AddRoute("*", newHandler("tls"))
+	require.NoError(t, router.AddRouteProtocol(ProtocolMySQL, newHandler(ProtocolMySQL)))
+
+	conn := serveHandler(t, router)
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	hello := clientHelloRecord(t)
+
+	// The client pauses after its first byte for longer than the protocol sniff timeout.
+	_, err := conn.Write(hello[:1])
+	require.NoError(t, err)
+
+	time.Sleep(200 * time.Millisecond)
+
+	_, err = conn.Write(hello[1:])
+	require.NoError(t, err)
+
+	select {
+	case name := <-served:
+		assert.Equal(t, "tls", name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the connection was not served")
+	}
+}
+
+// clientHelloRecord returns the TLS record of a ClientHello.
+func clientHelloRecord(t *testing.T) []byte {
+	t.Helper()
+
+	client, server := net.Pipe()
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = server.Close()
+	})
+
+	go func() {
+		_ = tls.Client(client, &tls.Config{ServerName: "foo.bar", InsecureSkipVerify: true}).Handshake()
+	}()
+
+	header := make([]byte, 5)
+	_, err := io.ReadFull(server, header)
+	require.NoError(t, err)
+
+	body := make([]byte, binary.BigEndian.Uint16(header[3:]))
+	_, err = io.ReadFull(server, body)
+	require.NoError(t, err)
+
+	return append(header, body...)
+}
@@ -0,0 +1,368 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// Protocols upgrading a plaintext connection to TLS in-band, supported by the StartTLSHandler.
+const (
+	StartTLSSMTP     = "smtp"
+	StartTLSIMAP     = "imap"
+	StartTLSPostgres = "postgres"
+)
+
+const (
+	// maxStartTLSCommands is the maximum number of commands a client can send before upgrading to TLS.
+	maxStartTLSCommands = 10
+	// maxStartTLSLineLength is the maximum length of a command sent by a client before upgrading to TLS.
+	maxStartTLSLineLength = 512
+	// defaultGreetingTimeout is the maximum duration to wait for the greeting of a server,
+	// when no dial timeout is configured.
+	defaultGreetingTimeout = 10 * time.Second
+	// defaultNegotiationTimeout is the maximum duration of the plaintext negotiation with a client, before upgrading to TLS.
+	defaultNegotiationTimeout = 10 * time.Second
+)
+
+// PostgreSQL request codes sent by a client instead of a StartupMessage.
+const (
+	postgresSSLRequestCode    = 80877103
+	postgresGSSENCRequestCode = 80877104
+)
+
+// StartTLSHandler performs the plaintext negotiation of a protocol upgrading the connection to TLS in-band,
+// and then hands the connection over to the next handler, to be routed as a TLS connection.
+type StartTLSHandler struct {
+	next               Handler
+	protocol           string
+	hostname           string
+	negotiationTimeout time.Duration
+}
+
+// NewStartTLSHandler creates a new StartTLSHandler for the given protocol.
+// The hostname is the one announced in the greeting of the SMTP and IMAP protocols,
+// and defaults to the host name of the system.
+func NewStartTLSHandler(protocol, hostname string, next Handler) (*StartTLSHandler, error) {
+	protocol = strings.ToLower(protocol)
+
+	switch protocol {
+	case StartTLSSMTP, StartTLSIMAP, StartTLSPostgres:
+	default:
+		return nil, fmt.Errorf("unsupported STARTTLS protocol: %q", protocol)
+	}
+
+	if hostname == "" {
+		var err error
+		hostname, err = os.Hostname()
+		if err != nil {
+			hostname = "localhost"
+		}
+	}
+
+	return &StartTLSHandler{
+		next:               next,
+		protocol:           protocol,
+		hostname:           hostname,
+		negotiationTimeout: defaultNegotiationTimeout,
+	}, nil
+}
+
+// ServeTCP upgrades the connection to TLS before handing it over to the next handler.
+func (s *StartTLSHandler) ServeTCP(conn WriteCloser) {
+	// The commands are read one at a time, and their length is bounded by the size of the buffer.
+	br := bufio.NewReaderSize(conn, maxStartTLSLineLength)
+
+	// A client cannot hold the connection in the plaintext negotiation indefinitely.
+	if err := conn.SetDeadline(time.Now().Add(s.negotiationTimeout)); err != nil {
+		log.WithoutContext().Errorf("Error while setting deadline: %v", err)
+	}
+
+	var err error
+	switch s.protocol {
+	case StartTLSSMTP:
+		err = s.startTLSSMTP(conn, br)
+	case StartTLSIMAP:
+		err = s.startTLSIMAP(conn, br)
+	case StartTLSPostgres:
+		err = startTLSPostgres(conn, br)
+	}
+
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			log.WithoutContext().Debugf("Error while upgrading connection from %s to TLS with %s: %v", conn.RemoteAddr(), s.protocol, err)
+		}
+		conn.Close()
+		return
+	}
+
+	// Nothing can be sent by the client before the server acknowledged the upgrade,
+	// so any buffered bytes were injected in the plaintext part of the connection.
+	if br.Buffered() > 0 {
+		log.WithoutContext().Debugf("Closing connection from %s: data sent before the TLS upgrade with %s", conn.RemoteAddr(), s.protocol)
+		conn.Close()
+		return
+	}
+
+	// The deadlines of the TLS connection are handled by the next handler.
+	removeDeadlines(conn)
+
+	s.next.ServeTCP(&startTLSConn{WriteCloser: conn, protocol: s.protocol})
+}
+
+// startTLSSMTP performs the SMTP negotiation up to the STARTTLS command (RFC 3207).
+func (s *StartTLSHandler) startTLSSMTP(conn WriteCloser, br *bufio.Reader) error {
+	if _, err := fmt.Fprintf(conn, "220 %s ESMTP Service ready\r\n", s.hostname); err != nil {
+		return err
+	}
+
+	for i := 0; i < maxStartTLSCommands; i++ {
+		line, err := readCommandLine(br)
+		if err != nil {
+			return err
+		}
+
+		var reply string
+		verb, _ := splitCommand(line)
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			reply = fmt.Sprintf("250-%s\r\n250 STARTTLS\r\n", s.hostname)
+		case "HELO":
+			reply = fmt.Sprintf("250 %s\r\n", s.hostname)
+		case "NOOP", "RSET":
+			reply = "250 2.0.0 OK\r\n"
+		case "STARTTLS":
+			_, err = io.WriteString(conn, "220 2.0.0 Ready to start TLS\r\n")
+			return err
+		case "QUIT":
+			_, _ = io.WriteString(conn, "221 2.0.0 Bye\r\n")
+			return io.EOF
+		default:
+			reply = "530 5.7.0 Must issue a STARTTLS command first\r\n"
+		}
+
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return err
+		}
+	}
+
+	_, _ = io.WriteString(conn, "421 4.7.0 Too many commands before STARTTLS\r\n")
+	return errors.New("too many commands before STARTTLS")
+}
+
+// startTLSIMAP performs the IMAP negotiation up to the STARTTLS command (RFC 3501).
+func (s *StartTLSHandler) startTLSIMAP(conn WriteCloser, br *bufio.Reader) error {
+	const capability = "IMAP4rev1 STARTTLS LOGINDISABLED"
+
+	if _, err := fmt.Fprintf(conn, "* OK [CAPABILITY %s] %s IMAP4 Service ready\r\n", capability, s.hostname); err != nil {
+		return err
+	}
+
+	for i := 0; i < maxStartTLSCommands; i++ {
+		line, err := readCommandLine(br)
+		if err != nil {
+			return err
+		}
+
+		tag, command := splitCommand(line)
+		verb, _ := splitCommand(command)
+		if tag == "" || verb == "" {
+			if _, err := io.WriteString(conn, "* BAD Invalid command\r\n"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var reply string
+		switch strings.ToUpper(verb) {
+		case "CAPABILITY":
+			reply = fmt.Sprintf("* CAPABILITY %s\r\n%s OK CAPABILITY completed\r\n", capability, tag)
+		case "NOOP":
+			reply = fmt.Sprintf("%s OK NOOP completed\r\n", tag)
+		case "STARTTLS":
+			_, err = fmt.Fprintf(conn, "%s OK Begin TLS negotiation now\r\n", tag)
+			return err
+		case "LOGOUT":
+			_, _ = fmt.Fprintf(conn, "* BYE Logging out\r\n%s OK LOGOUT completed\r\n", tag)
+			return io.EOF
+		default:
+			reply = fmt.Sprintf("%s NO Must issue a STARTTLS command first\r\n", tag)
+		}
+
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return err
+		}
+	}
+
+	_, _ = io.WriteString(conn, "* BYE Too many commands before STARTTLS\r\n")
+	return errors.New("too many commands before STARTTLS")
+}
+
+// startTLSPostgres accepts the SSLRequest sent by a PostgreSQL client before its StartupMessage.
+// A GSSENCRequest is declined, for the client to fall back to an SSLRequest.
+func startTLSPostgres(conn WriteCloser, br *bufio.Reader) error {
+	for i := 0; i < 2; i++ {
+		// The requests are made of their length, and of their code.
+		request := make([]byte, 8)
+		if _, err := io.ReadFull(br, request); err != nil {
+			return err
+		}
+
+		length := binary.BigEndian.Uint32(request[:4])
+		code := binary.BigEndian.Uint32(request[4:])
+
+		switch {
+		case length == 8 && code == postgresSSLRequestCode:
+			_, err := conn.Write([]byte{'S'})
+			return err
+		case length == 8 && code == postgresGSSENCRequestCode:
+			if _, err := conn.Write([]byte{'N'}); err != nil {
+				return err
+			}
+		default:
+			_, _ = conn.Write(postgresError("28000", "TLS is required"))
+			return errors.New("client did not request TLS")
+		}
+	}
+
+	return errors.New("client did not request TLS")
+}
+
+// postgresError returns a fatal PostgreSQL ErrorResponse message.
+func postgresError(code, message string) []byte {
+	var fields bytes.Buffer
+	for _, field := range []struct {
+		typ   byte
+		value string
+	}{{'S', "FATAL"}, {'V', "FATAL"}, {'C', code}, {'M', message}} {
+		fields.WriteByte(field.typ)
+		fields.WriteString(field.value)
+		fields.WriteByte(0)
+	}
+	fields.WriteByte(0)
+
+	msg := make([]byte, 5, 5+fields.Len())
+	msg[0] = 'E'
+	binary.BigEndian.PutUint32(msg[1:], uint32(4+fields.Len()))
+
+	return append(msg, fields.Bytes()...)
+}
+
+// readCommandLine reads a command line, without its line ending.
+func readCommandLine(br *bufio.Reader) (string, error) {
+	line, err := br.ReadSlice('\n')
+	if err != nil {
+		if errors.Is(err, bufio.ErrBufferFull) {
+			return "", errors.New("command line too long")
+		}
+		return "", err
+	}
+
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+// splitCommand splits a command line at its first space.
+func splitCommand(line string) (string, string) {
+	parts := strings.SplitN(strings.TrimSpace(line), " ", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// startTLSConn is a connection upgraded to TLS with a STARTTLS protocol.
+type startTLSConn struct {
+	WriteCloser
+
+	protocol string
+	// terminated is whether the connection wraps the TLS connection terminated by Traefik,
+	// rather than the connection upgraded by the client.
+	terminated bool
+}
+
+// Unwrap returns the connection wrapped by the startTLSConn.
+func (c *startTLSConn) Unwrap() WriteCloser {
+	return c.WriteCloser
+}
+
+// getStartTLSConn returns the connection upgraded to TLS with a STARTTLS protocol, which may be wrapped by the connection.
+func getStartTLSConn(conn WriteCloser) (*startTLSConn, bool) {
+	for {
+		switch c := conn.(type) {
+		case *startTLSConn:
+			return c, true
+		case *Conn:
+			conn = c.WriteCloser
+		case interface{ Unwrap() WriteCloser }:
+			conn = c.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
+
+// terminateTLS returns the TLS server connection terminating the given connection.
+// The protocol of a connection upgraded with STARTTLS is kept track of,
+// for the greeting of the server the connection is forwarded to to be skipped.
+func terminateTLS(conn WriteCloser, config *tls.Config) WriteCloser {
+	tlsConn := tls.Server(conn, config)
+
+	if c, ok := getStartTLSConn(conn); ok {
+		return &startTLSConn{WriteCloser: tlsConn, protocol: c.protocol, terminated: true}
+	}
+
+	return tlsConn
+}
+
+// skipServerGreeting reads the greeting sent by a plaintext server of the given STARTTLS protocol,
+// as the client already received the greeting of Traefik.
+// It reads one byte at a time, not to consume anything past the greeting.
+func skipServerGreeting(conn WriteCloser, protocol string) error {
+	// A PostgreSQL server does not send anything before the StartupMessage of the client.
+	if protocol != StartTLSSMTP && protocol != StartTLSIMAP {
+		return nil
+	}
+
+	var line []byte
+	b := make([]byte, 1)
+
+	for len(line) < maxStartTLSLineLength {
+		if _, err := io.ReadFull(conn, b); err != nil {
+			return err
+		}
+
+		line = append(line, b[0])
+		if b[0] != '\n' {
+			continue
+		}
+
+		switch protocol {
+		case StartTLSSMTP:
+			// A multiline reply is made of lines starting with "220-", the last one starting with "220 ".
+			if !bytes.HasPrefix(line, []byte("220")) {
+				return fmt.Errorf("unexpected SMTP greeting: %q", line)
+			}
+			if len(line) > 3 && line[3] == '-' {
+				line = line[:0]
+				continue
+			}
+		case StartTLSIMAP:
+			if !bytes.HasPrefix(line, []byte("* OK")) {
+				return fmt.Errorf("unexpected IMAP greeting: %q", line)
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%s greeting too long", protocol)
+}
@@ -0,0 +1,362 @@
+package tcp
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/tls/generate"
+)
+
+func postgresRequest(code uint32) []byte {
+	request := make([]byte, 8)
+	binary.BigEndian.PutUint32(request, 8)
+	binary.BigEndian.PutUint32(request[4:], code)
+	return request
+}
+
+// expectLine reads a line and checks that it starts with the expected prefix.
+func expectLine(t *testing.T, br *bufio.Reader, prefix string) {
+	t.Helper()
+
+	line, err := br.ReadString('\n')
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(line, prefix), "expected line starting with %q, got %q", prefix, line)
+}
+
+func TestStartTLSHandler(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		protocol        string
+		client          func(t *testing.T, conn net.Conn, br *bufio.Reader)
+		expectedUpgrade bool
+	}{
+		{
+			desc:     "SMTP STARTTLS",
+			protocol: StartTLSSMTP,
+			client: func(t *testing.T, conn net.Conn, br *bufio.Reader) {
+				t.Helper()
+
+				expectLine(t, br, "220 traefik.example.com ESMTP")
+
+				_, _ = io.WriteString(conn, "EHLO client.example.com\r\n")
+				expectLine(t, br, "250-traefik.example.com")
+				expectLine(t, br, "250 STARTTLS")
+
+				_, _ = io.WriteString(conn, "MAIL FROM:<foo@example.com>\r\n")
+				expectLine(t, br, "530 ")
+
+				_, _ = io.WriteString(conn, "STARTTLS\r\n")
+				expectLine(t, br, "220 ")
+			},
+			expectedUpgrade: true,
+		},
+		{
+			desc:     "SMTP QUIT",
+			protocol: StartTLSSMTP,
+			client: func(t *testing.T, conn net.Conn, br *bufio.Reader) {
+				t.Helper()
+
+				expectLine(t, br, "220 ")
+
+				_, _ = io.WriteString(conn, "QUIT\r\n")
+				expectLine(t, br, "221 ")
+			},
+		},
+		{
+			desc:     "SMTP command injected before the TLS upgrade",
+			protocol: StartTLSSMTP,
+			client: func(t *testing.T, conn net.Conn, br *bufio.Reader) {
+				t.Helper()
+
+				expectLine(t, br, "220 ")
+
+				_, _ = io.WriteString(conn, "STARTTLS\r\nMAIL FROM:<foo@example.com>\r\n")
+				expectLine(t, br, "220 ")
+			},
+		},
+		{
+			desc:     "SMTP too many commands",
+			protocol: StartTLSSMTP,
+			client: func(t *testing.T, conn net.Conn, br *bufio.Reader) {
+				t.Helper()
+
+				expectLine(t, br, "220 ")
+
+				for i := 0; i < maxStartTLSCommands; i++ {
+					_, _ = io.WriteString(conn, "NOOP\r\n")
+					expectLine(t, br, "250 ")
+				}
+				expectLine(t, br, "421 ")
+			},
+		},
+		{
+			desc:     "IMAP STARTTLS",
+			protocol: StartTLSIMAP,
+			client: func(t *testing.T, conn net.Conn, br *bufio.Reader) {
+				t.Helper()
+
+				expectLine(t, br, "* OK [CAPABILITY IMAP4rev1 STARTTLS LOGINDISABLED]")
+
+				_, _ = io.WriteString(conn, "a1 CAPABILITY\r\n")
+				expectLine(t, br, "* CAPABILITY IMAP4rev1 STARTTLS LOGINDISABLED")
+				expectLine(t, br, "a1 OK")
+
+				_, _ = io.WriteString(conn, "a2 LOGIN foo bar\r\n")
+				expectLine(t, br, "a2 NO")
+
+				_, _ = io.WriteString(conn, "a3 STARTTLS\r\n")
+				expectLine(t, br, "a3 OK")
+			},
+			expectedUpgrade: true,
+		},
+		{
+			desc:     "IMAP LOGOUT",
+			protocol: StartTLSIMAP,
+			client: func(t *testing.T, conn net.Conn, br *bufio.Reader) {
+				t.Helper()
+
+				expectLine(t, br, "* OK")
+
+				_, _ = io.WriteString(conn, "a1 LOGOUT\r\n")
+				expectLine(t, br, "* BYE")
+				expectLine(t, br, "a1 OK")
+			},
+		},
+		{
+			desc:     "PostgreSQL SSLRequest",
+			protocol: StartTLSPostgres,
+			client: func(t *testing.T, conn net.Conn, br *bufio.Reader) {
+				t.Helper()
+
+				_, _ = conn.Write(postgresRequest(postgresSSLRequestCode))
+
+				b, err := br.ReadByte()
+				require.NoError(t, err)
+				assert.Equal(t, byte('S'), b)
+			},
+			expectedUpgrade: true,
+		},
+		{
+			desc:     "PostgreSQL GSSENCRequest, then SSLRequest",
+			protocol: StartTLSPostgres,
+			client: func(t *testing.T, conn net.Conn, br *bufio.Reader) {
+				t.Helper()
+
+				_, _ = conn.Write(postgresRequest(postgresGSSENCRequestCode))
+
+				b, err := br.ReadByte()
+				require.NoError(t, err)
+				assert.Equal(t, byte('N'), b)
+
+				_, _ = conn.Write(postgresRequest(postgresSSLRequestCode))
+
+				b, err = br.ReadByte()
+				require.NoError(t, err)
+				assert.Equal(t, byte('S'), b)
+			},
+			expectedUpgrade: true,
+		},
+		{
+			desc:     "PostgreSQL StartupMessage without TLS",
+			protocol: StartTLSPostgres,
+			client: func(t *testing.T, conn net.Conn, br *bufio.Reader) {
+				t.Helper()
+
+				// Protocol version 3.0.
+				_, _ = conn.Write(postgresRequest(196608))
+
+				b, err := br.ReadByte()
+				require.NoError(t, err)
+				assert.Equal(t, byte('E'), b)
+
+				length := make([]byte, 4)
+				_, err = io.ReadFull(br, length)
+				require.NoError(t, err)
+
+				fields := make([]byte, binary.BigEndian.Uint32(length)-4)
+				_, err = io.ReadFull(br, fields)
+				require.NoError(t, err)
+				assert.Contains(t, string(fields), "TLS is required")
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			upgraded := make(chan WriteCloser, 1)
+			handler, err := NewStartTLSHandler(test.protocol, "traefik.example.com", HandlerFunc(func(conn WriteCloser) {
+				upgraded <- conn
+				conn.Close()
+			}))
+			require.NoError(t, err)
+
+			conn := serveHandler(t, handler)
+			require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+			br := bufio.NewReader(conn)
+			test.client(t, conn, br)
+
+			// The connection is closed, either by the next handler or by the StartTLSHandler.
+			_, err = br.ReadByte()
+			assert.ErrorIs(t, err, io.EOF)
+
+			select {
+			case c := <-upgraded:
+				assert.True(t, test.expectedUpgrade)
+
+				startTLS, ok := getStartTLSConn(c)
+				require.True(t, ok)
+				assert.Equal(t, test.protocol, startTLS.protocol)
+				assert.False(t, startTLS.terminated)
+			default:
+				assert.False(t, test.expectedUpgrade)
+			}
+		})
+	}
+}
+
+func TestNewStartTLSHandler_invalidProtocol(t *testing.T) {
+	_, err := NewStartTLSHandler("ftp", "", nil)
+	assert.Error(t, err)
+}
+
+func TestStartTLSHandler_negotiationTimeout(t *testing.T) {
+	handler, err := NewStartTLSHandler(StartTLSSMTP, "traefik.example.com", HandlerFunc(func(conn WriteCloser) {
+		conn.Close()
+	}))
+	require.NoError(t, err)
+	handler.negotiationTimeout = 50 * time.Millisecond
+
+	conn := serveHandler(t, handler)
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	br := bufio.NewReader(conn)
+	expectLine(t, br, "220 ")
+
+	// The client never sends any command, and the connection is closed once the negotiation timeout elapsed.
+	start := time.Now()
+
+	_, err = br.ReadByte()
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Less(t, int64(time.Since(start)), int64(defaultNegotiationTimeout))
+}
+
+func TestStartTLSHandler_routing(t *testing.T) {
+	certPEM, keyPEM, err := generate.KeyPair("smtp.example.com", time.Time{})
+	require.NoError(t, err)
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	// The backend sends a multiline greeting, skipped by the proxy, and then echoes what it receives.
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendListener.Close() })
+
+	go func() {
+		conn, err := backendListener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = io.WriteString(conn, "220-backend.example.com ESMTP\r\n220 backend ready\r\n")
+		_, _ = io.Copy(conn, conn)
+	}()
+
+	proxy, err := NewProxy(backendListener.Addr().String(), 10*time.Millisecond, nil, DialConfig{}, ConnTimeouts{}, nil)
+	require.NoError(t, err)
+
+	router := &Router{}
+	router.AddRouteTLS("smtp.example.com", proxy, &tls.Config{Certificates: []tls.Certificate{cert}})
+
+	handler, err := NewStartTLSHandler(StartTLSSMTP, "traefik.example.com", router)
+	require.NoError(t, err)
+
+	conn := serveHandler(t, handler)
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	br := bufio.NewReader(conn)
+	expectLine(t, br, "220 ")
+
+	_, err = io.WriteString(conn, "STARTTLS\r\n")
+	require.NoError(t, err)
+	expectLine(t, br, "220 ")
+
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: "smtp.example.com", InsecureSkipVerify: true})
+	require.NoError(t, tlsConn.Handshake())
+
+	_, err = io.WriteString(tlsConn, "EHLO client.example.com\r\n")
+	require.NoError(t, err)
+
+	line, err := bufio.NewReader(tlsConn).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "EHLO client.example.com\r\n", line)
+}
+
+func TestProxy_silentServerGreeting(t *testing.T) {
+	// The backend accepts the connection, but never sends its greeting.
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendListener.Close() })
+
+	go func() {
+		conn, err := backendListener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = io.Copy(io.Discard, conn)
+	}()
+
+	proxy, err := NewProxy(backendListener.Addr().String(), 10*time.Millisecond, nil, DialConfig{Timeout: 50 * time.Millisecond}, ConnTimeouts{}, nil)
+	require.NoError(t, err)
+
+	conn := serveHandler(t, HandlerFunc(func(conn WriteCloser) {
+		proxy.ServeTCP(&startTLSConn{WriteCloser: conn, protocol: StartTLSSMTP, terminated: true})
+	}))
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	// The connection is closed by the proxy once the greeting timeout elapsed.
+	_, err = conn.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+// serveHandler serves the connections of a new listener with the given handler,
+// and returns a client connection to the listener.
+func serveHandler(t *testing.T, handler Handler) net.Conn {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go handler.ServeTCP(conn.(*net.TCPConn))
+		}
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
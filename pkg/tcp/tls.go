@@ -12,5 +12,65 @@ type TLSHandler struct {
 
 // ServeTCP terminates the TLS connection.
 func (t *TLSHandler) ServeTCP(conn WriteCloser) {
-	t.Next.ServeTCP(tls.Server(conn, t.Config))
+	t.Next.ServeTCP(terminateTLS(conn, t.Config))
+}
+
+// GetTLSConn returns the TLS connection terminated by Traefik, which may be wrapped by the connection.
+// The wrapping connections expose the connection they wrap through an Unwrap method.
+func GetTLSConn(conn WriteCloser) (*tls.Conn, bool) {
+	for {
+		switch c := conn.(type) {
+		case *tls.Conn:
+			return c, true
+		case interface{ Unwrap() WriteCloser }:
+			conn = c.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
+
+// GetServerName returns the server name indicated by the client of the connection,
+// either in the TLS handshake terminated by Traefik, or in the ClientHello of a TLS passthrough connection.
+// The TLS handshake terminated by Traefik must be done.
+func GetServerName(conn WriteCloser) string {
+	if tlsConn, ok := GetTLSConn(conn); ok {
+		return tlsConn.ConnectionState().ServerName
+	}
+
+	if peekedConn, ok := getPeekedConn(conn); ok {
+		return peekedConn.ServerName
+	}
+
+	return ""
+}
+
+// GetALPN returns the application protocol of the connection,
+// either negotiated in the TLS handshake terminated by Traefik,
+// or the first one offered by the client in the ClientHello of a TLS passthrough connection.
+// The TLS handshake terminated by Traefik must be done.
+func GetALPN(conn WriteCloser) string {
+	if tlsConn, ok := GetTLSConn(conn); ok {
+		return tlsConn.ConnectionState().NegotiatedProtocol
+	}
+
+	if peekedConn, ok := getPeekedConn(conn); ok && len(peekedConn.ALPNProtocols) > 0 {
+		return peekedConn.ALPNProtocols[0]
+	}
+
+	return ""
+}
+
+// getPeekedConn returns the connection peeked by the router, which may be wrapped by the connection.
+func getPeekedConn(conn WriteCloser) (*Conn, bool) {
+	for {
+		switch c := conn.(type) {
+		case *Conn:
+			return c, true
+		case interface{ Unwrap() WriteCloser }:
+			conn = c.Unwrap()
+		default:
+			return nil, false
+		}
+	}
 }
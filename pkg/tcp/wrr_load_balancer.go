@@ -28,18 +28,36 @@ func NewWRRLoadBalancer() *WRRLoadBalancer {
 }
 
 // ServeTCP forwards the connection to the right service.
+// When a server cannot be reached, the connection is forwarded to the next server, until each server has been tried once.
 func (b *WRRLoadBalancer) ServeTCP(conn WriteCloser) {
-	b.lock.Lock()
-	next, err := b.next()
-	b.lock.Unlock()
+	tried := make(map[int]struct{})
 
-	if err != nil {
-		log.WithoutContext().Errorf("Error during load balancing: %v", err)
-		conn.Close()
-		return
-	}
+	for {
+		b.lock.Lock()
+		next, index, err := b.nextUntried(tried)
+		b.lock.Unlock()
+
+		if err != nil {
+			log.WithoutContext().Errorf("Error during load balancing: %v", err)
+
+			// The enclosing load balancer, if any, tries its next server.
+			if !retryNextServer(conn) {
+				conn.Close()
+			}
+			return
+		}
+
+		tried[index] = struct{}{}
+
+		rc := &retryConn{WriteCloser: conn}
+		next.ServeTCP(rc)
 
-	next.ServeTCP(conn)
+		if !rc.retry {
+			return
+		}
+
+		log.WithoutContext().Debugf("Forwarding connection from %s to the next server", conn.RemoteAddr())
+	}
 }
 
 // AddServer appends a server to the existing list.
@@ -89,6 +107,34 @@ func gcd(a, b int) int {
 	return a
 }
 
+// nextUntried returns the next server which has not been tried yet, and its index.
+func (b *WRRLoadBalancer) nextUntried(tried map[int]struct{}) (Handler, int, error) {
+	if len(tried) > 0 && len(tried) >= len(b.servers) {
+		return nil, 0, fmt.Errorf("no servers left to try")
+	}
+
+	// All the servers with a non-zero weight are returned within a full round.
+	round := 0
+	if gcd := b.weightGcd(); gcd > 0 {
+		for _, s := range b.servers {
+			round += s.weight / gcd
+		}
+	}
+
+	for i := 0; i <= round; i++ {
+		next, err := b.next()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if _, ok := tried[b.index]; !ok {
+			return next, b.index, nil
+		}
+	}
+
+	return nil, 0, fmt.Errorf("no servers left to try")
+}
+
 func (b *WRRLoadBalancer) next() (Handler, error) {
 	if len(b.servers) == 0 {
 		return nil, fmt.Errorf("no servers in the pool")
@@ -121,3 +167,32 @@ func (b *WRRLoadBalancer) next() (Handler, error) {
 		}
 	}
 }
+
+// retryConn is the connection forwarded to a server by the load balancer,
+// on which the server reports that it could not be reached, for the load balancer to try the next server.
+type retryConn struct {
+	WriteCloser
+
+	retry bool
+}
+
+// Unwrap returns the connection wrapped by the retryConn.
+func (c *retryConn) Unwrap() WriteCloser {
+	return c.WriteCloser
+}
+
+// retryNextServer reports, if the given connection is forwarded by a load balancer,
+// that the server could not be reached and that the load balancer should try its next server.
+func retryNextServer(conn WriteCloser) bool {
+	for {
+		switch c := conn.(type) {
+		case *retryConn:
+			c.retry = true
+			return true
+		case interface{ Unwrap() WriteCloser }:
+			conn = c.Unwrap()
+		default:
+			return false
+		}
+	}
+}
@@ -144,3 +144,93 @@ func TestLoadBalancing(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadBalancing_retry(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		servers       []string
+		unreachable   map[string]bool
+		expectedTries []string
+		expectedWrite map[string]int
+		expectedClose int
+	}{
+		{
+			desc:          "first server unreachable",
+			servers:       []string{"h1", "h2", "h3"},
+			unreachable:   map[string]bool{"h1": true},
+			expectedTries: []string{"h1", "h2"},
+			expectedWrite: map[string]int{"h2": 1},
+		},
+		{
+			desc:          "all servers unreachable",
+			servers:       []string{"h1", "h2", "h3"},
+			unreachable:   map[string]bool{"h1": true, "h2": true, "h3": true},
+			expectedTries: []string{"h1", "h2", "h3"},
+			expectedWrite: map[string]int{},
+			expectedClose: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var tries []string
+
+			balancer := NewWRRLoadBalancer()
+			for _, server := range test.servers {
+				server := server
+				balancer.AddServer(HandlerFunc(func(conn WriteCloser) {
+					tries = append(tries, server)
+
+					if test.unreachable[server] {
+						assert.True(t, retryNextServer(conn))
+						return
+					}
+
+					_, err := conn.Write([]byte(server))
+					require.NoError(t, err)
+				}))
+			}
+
+			conn := &retryTestConn{fakeConn: fakeConn{writeCall: make(map[string]int)}}
+			balancer.ServeTCP(conn)
+
+			assert.Equal(t, test.expectedTries, tries)
+			assert.Equal(t, test.expectedWrite, conn.writeCall)
+			assert.Equal(t, test.expectedClose, conn.closeCall)
+		})
+	}
+}
+
+func TestLoadBalancing_retryEnclosingLoadBalancer(t *testing.T) {
+	unreachable := NewWRRLoadBalancer()
+	unreachable.AddServer(HandlerFunc(func(conn WriteCloser) {
+		retryNextServer(conn)
+	}))
+
+	reachable := NewWRRLoadBalancer()
+	reachable.AddServer(HandlerFunc(func(conn WriteCloser) {
+		_, err := conn.Write([]byte("reachable"))
+		require.NoError(t, err)
+	}))
+
+	balancer := NewWRRLoadBalancer()
+	balancer.AddServer(unreachable)
+	balancer.AddServer(reachable)
+
+	conn := &retryTestConn{fakeConn: fakeConn{writeCall: make(map[string]int)}}
+	balancer.ServeTCP(conn)
+
+	assert.Equal(t, map[string]int{"reachable": 1}, conn.writeCall)
+	assert.Equal(t, 0, conn.closeCall)
+}
+
+type retryTestConn struct {
+	fakeConn
+}
+
+func (c *retryTestConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 40000}
+}
@@ -0,0 +1,328 @@
+package tls
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	// ocspTimeout is the timeout of the requests to the OCSP responders.
+	ocspTimeout = 10 * time.Second
+	// ocspHandshakeTimeout is the timeout of the requests to the OCSP responders made during the TLS handshakes,
+	// to check the revocation of client certificates with no cached status.
+	ocspHandshakeTimeout = 2 * time.Second
+	// ocspRetryInterval is the interval between two attempts to fetch an OCSP response, after a failure.
+	ocspRetryInterval = 10 * time.Minute
+	// ocspDefaultValidity is the validity of the OCSP responses without next update.
+	ocspDefaultValidity = time.Hour
+	// ocspMaxResponseSize is the maximum size of an OCSP response.
+	ocspMaxResponseSize = 1024 * 1024
+)
+
+// ocspClient fetches OCSP responses, and caches the ones used to check the revocation of client certificates.
+type ocspClient struct {
+	httpClient *http.Client
+
+	cacheMu sync.Mutex
+	cache   map[[sha256.Size]byte]*ocsp.Response
+	// refreshing are the cached responses being refreshed in the background.
+	refreshing map[[sha256.Size]byte]struct{}
+}
+
+func newOCSPClient() *ocspClient {
+	return &ocspClient{
+		httpClient: &http.Client{Timeout: ocspTimeout},
+		cache:      map[[sha256.Size]byte]*ocsp.Response{},
+		refreshing: map[[sha256.Size]byte]struct{}{},
+	}
+}
+
+// fetch requests the status of the certificate to its OCSP responders, and returns the first valid response.
+func (c *ocspClient) fetch(ctx context.Context, leaf, issuer *x509.Certificate) (*ocsp.Response, []byte, error) {
+	if len(leaf.OCSPServer) == 0 {
+		return nil, nil, errors.New("no OCSP server in certificate")
+	}
+
+	request, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var errs []error
+	for _, server := range leaf.OCSPServer {
+		raw, err := c.post(ctx, server, request)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		response, err := ocsp.ParseResponseForCert(raw, leaf, issuer)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid OCSP response from %s: %w", server, err))
+			continue
+		}
+
+		if !response.NextUpdate.IsZero() && response.NextUpdate.Before(time.Now()) {
+			errs = append(errs, fmt.Errorf("expired OCSP response from %s", server))
+			continue
+		}
+
+		return response, raw, nil
+	}
+
+	return nil, nil, fmt.Errorf("unable to get an OCSP response: %v", errs)
+}
+
+// status returns the cached OCSP response of the client certificate, or fetches it within the handshake timeout.
+// The cached responses are refreshed in the background halfway through their validity,
+// so that the handshakes of the known clients do not wait for the OCSP responders.
+func (c *ocspClient) status(leaf, issuer *x509.Certificate) (*ocsp.Response, error) {
+	key := sha256.Sum256(leaf.Raw)
+
+	c.cacheMu.Lock()
+	response, ok := c.cache[key]
+	c.cacheMu.Unlock()
+
+	if ok && time.Now().Before(ocspExpiry(response)) {
+		if time.Now().After(ocspRefreshTime(response)) {
+			c.refresh(key, leaf, issuer)
+		}
+		return response, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ocspHandshakeTimeout)
+	defer cancel()
+
+	response, _, err := c.fetch(ctx, leaf, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	c.store(key, response)
+
+	return response, nil
+}
+
+// refresh fetches the OCSP response of the client certificate in the background, unless it is already being fetched.
+func (c *ocspClient) refresh(key [sha256.Size]byte, leaf, issuer *x509.Certificate) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	if _, ok := c.refreshing[key]; ok {
+		return
+	}
+	c.refreshing[key] = struct{}{}
+
+	go func() {
+		response, _, err := c.fetch(context.Background(), leaf, issuer)
+
+		c.cacheMu.Lock()
+		delete(c.refreshing, key)
+		c.cacheMu.Unlock()
+
+		if err != nil {
+			log.WithoutContext().Debugf("Unable to refresh the OCSP status of the client certificate %q: %v", leaf.Subject, err)
+			return
+		}
+
+		c.store(key, response)
+	}()
+}
+
+func (c *ocspClient) store(key [sha256.Size]byte, response *ocsp.Response) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	c.cache[key] = response
+	c.removeExpired()
+}
+
+// removeExpired removes the expired responses from the cache, with the lock held.
+func (c *ocspClient) removeExpired() {
+	now := time.Now()
+	for key, response := range c.cache {
+		if now.After(ocspExpiry(response)) {
+			delete(c.cache, key)
+		}
+	}
+}
+
+func (c *ocspClient) post(ctx context.Context, server string, request []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server, bytes.NewReader(request))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/ocsp-request")
+	req.Header.Set("Accept", "application/ocsp-response")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from %s: %d", server, resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, ocspMaxResponseSize))
+}
+
+// ocspExpiry returns the time after which the response must not be used anymore.
+func ocspExpiry(response *ocsp.Response) time.Time {
+	if response.NextUpdate.IsZero() {
+		return response.ThisUpdate.Add(ocspDefaultValidity)
+	}
+	return response.NextUpdate
+}
+
+// ocspRefreshTime returns the time halfway through the validity of the response, after which it is refreshed.
+func ocspRefreshTime(response *ocsp.Response) time.Time {
+	return response.ThisUpdate.Add(ocspExpiry(response).Sub(response.ThisUpdate) / 2)
+}
+
+// ocspStapler fetches and refreshes the OCSP responses of the served certificates, to staple them.
+type ocspStapler struct {
+	client *ocspClient
+
+	mu      sync.RWMutex
+	staples map[[sha256.Size]byte]*ocspStaple
+}
+
+// ocspStaple is the OCSP response of a served certificate, refreshed in the background.
+type ocspStaple struct {
+	leaf   *x509.Certificate
+	issuer *x509.Certificate
+	raw    []byte
+	expiry time.Time
+	timer  *time.Timer
+}
+
+func newOCSPStapler(client *ocspClient) *ocspStapler {
+	return &ocspStapler{
+		client:  client,
+		staples: map[[sha256.Size]byte]*ocspStaple{},
+	}
+}
+
+// update starts fetching the OCSP responses of the new certificates, and forgets the ones of the removed certificates.
+// The certificates with no OCSP server, or without their issuer in their chain, are not stapled.
+func (s *ocspStapler) update(certificates []*tls.Certificate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := map[[sha256.Size]byte]struct{}{}
+
+	for _, certificate := range certificates {
+		if certificate == nil || len(certificate.Certificate) < 2 {
+			continue
+		}
+
+		key := sha256.Sum256(certificate.Certificate[0])
+		current[key] = struct{}{}
+
+		if _, ok := s.staples[key]; ok {
+			continue
+		}
+
+		leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+		if err != nil || len(leaf.OCSPServer) == 0 {
+			continue
+		}
+
+		issuer, err := x509.ParseCertificate(certificate.Certificate[1])
+		if err != nil {
+			continue
+		}
+
+		staple := &ocspStaple{leaf: leaf, issuer: issuer}
+		s.staples[key] = staple
+		staple.timer = time.AfterFunc(0, func() { s.refresh(key) })
+	}
+
+	for key, staple := range s.staples {
+		if _, ok := current[key]; !ok {
+			staple.timer.Stop()
+			delete(s.staples, key)
+		}
+	}
+}
+
+// refresh fetches the OCSP response of the certificate, and schedules the next refresh.
+func (s *ocspStapler) refresh(key [sha256.Size]byte) {
+	s.mu.RLock()
+	staple, ok := s.staples[key]
+	s.mu.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	logger := log.WithoutContext().WithField("certificate", staple.leaf.Subject.String())
+
+	next := ocspRetryInterval
+
+	response, raw, err := s.client.fetch(context.Background(), staple.leaf, staple.issuer)
+	if err != nil {
+		logger.Warnf("Unable to staple an OCSP response: %v", err)
+	} else {
+		if response.Status == ocsp.Revoked {
+			logger.Errorf("The certificate has been revoked at %s", response.RevokedAt)
+		}
+
+		// Refreshes the response halfway through its validity.
+		next = time.Until(ocspRefreshTime(response))
+		if next < time.Minute {
+			next = time.Minute
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The certificate may have been removed while fetching.
+	if s.staples[key] != staple {
+		return
+	}
+
+	if err == nil {
+		staple.raw = raw
+		staple.expiry = ocspExpiry(response)
+	}
+	staple.timer = time.AfterFunc(next, func() { s.refresh(key) })
+}
+
+// staple returns a copy of the certificate with its OCSP response, if any.
+func (s *ocspStapler) staple(certificate *tls.Certificate) *tls.Certificate {
+	if certificate == nil || len(certificate.Certificate) == 0 {
+		return certificate
+	}
+
+	s.mu.RLock()
+	staple, ok := s.staples[sha256.Sum256(certificate.Certificate[0])]
+	var raw []byte
+	if ok && time.Now().Before(staple.expiry) {
+		raw = staple.raw
+	}
+	s.mu.RUnlock()
+
+	if raw == nil {
+		return certificate
+	}
+
+	stapled := *certificate
+	stapled.OCSPStaple = raw
+	return &stapled
+}
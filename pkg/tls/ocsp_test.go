@@ -0,0 +1,424 @@
+package tls
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+// testPKI is a CA issuing certificates, with an OCSP responder.
+type testPKI struct {
+	ca     *x509.Certificate
+	caKey  crypto.Signer
+	serial int64
+
+	mu       sync.Mutex
+	statuses map[string]int
+
+	responder *httptest.Server
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		SubjectKeyId:          []byte{1, 2, 3, 4},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, caKey.Public(), caKey)
+	require.NoError(t, err)
+
+	ca, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pki := &testPKI{ca: ca, caKey: caKey, serial: 1, statuses: map[string]int{}}
+
+	pki.responder = httptest.NewServer(http.HandlerFunc(pki.serveOCSP))
+	t.Cleanup(pki.responder.Close)
+
+	return pki
+}
+
+func (p *testPKI) serveOCSP(rw http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	request, err := ocsp.ParseRequest(body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	status, ok := p.statuses[request.SerialNumber.String()]
+	p.mu.Unlock()
+	if !ok {
+		status = ocsp.Unknown
+	}
+
+	template := ocsp.Response{
+		Status:       status,
+		SerialNumber: request.SerialNumber,
+		ThisUpdate:   time.Now().Add(-time.Minute),
+		NextUpdate:   time.Now().Add(time.Hour),
+	}
+	if status == ocsp.Revoked {
+		template.RevokedAt = time.Now().Add(-time.Minute)
+	}
+
+	response, err := ocsp.CreateResponse(p.ca, p.ca, template, p.caKey)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/ocsp-response")
+	_, _ = rw.Write(response)
+}
+
+func (p *testPKI) setStatus(cert *x509.Certificate, status int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.statuses[cert.SerialNumber.String()] = status
+}
+
+// issue issues a certificate for the domain, referencing the given OCSP servers.
+func (p *testPKI) issue(t *testing.T, domain string, ocspServers ...string) (*x509.Certificate, crypto.Signer) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p.mu.Lock()
+	p.serial++
+	serial := p.serial
+	p.mu.Unlock()
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		OCSPServer:   ocspServers,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, p.ca, key.Public(), p.caKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert, key
+}
+
+func (p *testPKI) crl(t *testing.T, revoked ...*x509.Certificate) []byte {
+	t.Helper()
+
+	var revokedCerts []pkix.RevokedCertificate
+	for _, cert := range revoked {
+		revokedCerts = append(revokedCerts, pkix.RevokedCertificate{SerialNumber: cert.SerialNumber, RevocationTime: time.Now()})
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		RevokedCertificates: revokedCerts,
+		Number:              big.NewInt(1),
+		ThisUpdate:          time.Now().Add(-time.Minute),
+		NextUpdate:          time.Now().Add(time.Hour),
+	}, p.ca, p.caKey)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+}
+
+func pemCertificate(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+func TestManager_Get_ocspStapling(t *testing.T) {
+	pki := newTestPKI(t)
+
+	leaf, key := pki.issue(t, "stapled.localhost", pki.responder.URL)
+	pki.setStatus(leaf, ocsp.Good)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	certs := []*CertAndStores{{
+		Certificate: Certificate{
+			CertFile: FileOrContent(append(pemCertificate(leaf), pemCertificate(pki.ca)...)),
+			KeyFile:  FileOrContent(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})),
+		},
+	}}
+
+	tlsManager := NewManager()
+	tlsManager.UpdateConfigs(context.Background(), nil, map[string]Options{"default": {}}, certs)
+
+	config, err := tlsManager.Get(DefaultTLSStoreName, DefaultTLSConfigName)
+	require.NoError(t, err)
+
+	var staple []byte
+	assert.Eventually(t, func() bool {
+		certificate, err := config.GetCertificate(&tls.ClientHelloInfo{ServerName: "stapled.localhost"})
+		require.NoError(t, err)
+
+		staple = certificate.OCSPStaple
+		return len(staple) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	response, err := ocsp.ParseResponseForCert(staple, leaf, pki.ca)
+	require.NoError(t, err)
+	assert.Equal(t, ocsp.Good, response.Status)
+
+	// The default certificate, with no OCSP server, is not stapled.
+	certificate, err := config.GetCertificate(&tls.ClientHelloInfo{ServerName: "unknown.localhost"})
+	require.NoError(t, err)
+	assert.Empty(t, certificate.OCSPStaple)
+
+	// The staples of the removed certificates are forgotten.
+	tlsManager.UpdateConfigs(context.Background(), nil, nil, nil)
+	assert.Empty(t, tlsManager.stapler.staples)
+}
+
+func TestRevocationChecker_verifyPeerCertificate(t *testing.T) {
+	pki := newTestPKI(t)
+
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	good, _ := pki.issue(t, "good.localhost", pki.responder.URL)
+	pki.setStatus(good, ocsp.Good)
+
+	revoked, _ := pki.issue(t, "revoked.localhost", pki.responder.URL)
+	pki.setStatus(revoked, ocsp.Revoked)
+
+	unknown, _ := pki.issue(t, "unknown.localhost", unreachable.URL)
+
+	crl := FileOrContent(pki.crl(t, revoked))
+
+	testCases := []struct {
+		desc        string
+		config      RevocationCheck
+		cert        *x509.Certificate
+		expectedErr bool
+	}{
+		{
+			desc:   "OCSP good",
+			config: RevocationCheck{OCSP: true},
+			cert:   good,
+		},
+		{
+			desc:        "OCSP revoked",
+			config:      RevocationCheck{OCSP: true},
+			cert:        revoked,
+			expectedErr: true,
+		},
+		{
+			desc:        "OCSP revoked with soft fail",
+			config:      RevocationCheck{OCSP: true, SoftFail: true},
+			cert:        revoked,
+			expectedErr: true,
+		},
+		{
+			desc:        "OCSP responder unreachable",
+			config:      RevocationCheck{OCSP: true},
+			cert:        unknown,
+			expectedErr: true,
+		},
+		{
+			desc:   "OCSP responder unreachable with soft fail",
+			config: RevocationCheck{OCSP: true, SoftFail: true},
+			cert:   unknown,
+		},
+		{
+			desc:   "CRL not listing the certificate",
+			config: RevocationCheck{CRLFiles: []FileOrContent{crl}},
+			cert:   unknown,
+		},
+		{
+			desc:        "CRL listing the certificate",
+			config:      RevocationCheck{CRLFiles: []FileOrContent{crl}},
+			cert:        revoked,
+			expectedErr: true,
+		},
+		{
+			desc:        "CRL listing the certificate with soft fail",
+			config:      RevocationCheck{CRLFiles: []FileOrContent{crl}, SoftFail: true},
+			cert:        revoked,
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			checker, err := newRevocationChecker(&test.config, newOCSPClient())
+			require.NoError(t, err)
+
+			err = checker.verifyPeerCertificate(nil, [][]*x509.Certificate{{test.cert, pki.ca}})
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBuildTLSConfig_revocationCheck(t *testing.T) {
+	pki := newTestPKI(t)
+
+	caFile := FileOrContent(pemCertificate(pki.ca))
+
+	testCases := []struct {
+		desc        string
+		clientAuth  ClientAuth
+		expectedErr bool
+	}{
+		{
+			desc: "OCSP",
+			clientAuth: ClientAuth{
+				CAFiles:         []FileOrContent{caFile},
+				RevocationCheck: &RevocationCheck{OCSP: true},
+			},
+		},
+		{
+			desc: "CRL",
+			clientAuth: ClientAuth{
+				CAFiles:         []FileOrContent{caFile},
+				RevocationCheck: &RevocationCheck{CRLFiles: []FileOrContent{FileOrContent(pki.crl(t))}},
+			},
+		},
+		{
+			desc: "no CA files",
+			clientAuth: ClientAuth{
+				RevocationCheck: &RevocationCheck{OCSP: true},
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "neither OCSP nor CRL",
+			clientAuth: ClientAuth{
+				CAFiles:         []FileOrContent{caFile},
+				RevocationCheck: &RevocationCheck{SoftFail: true},
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "invalid CRL",
+			clientAuth: ClientAuth{
+				CAFiles:         []FileOrContent{caFile},
+				RevocationCheck: &RevocationCheck{CRLFiles: []FileOrContent{"-----BEGIN X509 CRL-----\nfoo\n-----END X509 CRL-----"}},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config, err := buildTLSConfig(Options{ClientAuth: test.clientAuth}, newRevocationCheckers(newOCSPClient()))
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, config.VerifyPeerCertificate)
+		})
+	}
+}
+
+func TestOCSPClient_status_backgroundRefresh(t *testing.T) {
+	pki := newTestPKI(t)
+
+	leaf, _ := pki.issue(t, "client.localhost", pki.responder.URL)
+	pki.setStatus(leaf, ocsp.Revoked)
+
+	client := newOCSPClient()
+
+	key := sha256.Sum256(leaf.Raw)
+	client.cache[key] = &ocsp.Response{
+		Status:     ocsp.Good,
+		ThisUpdate: time.Now().Add(-50 * time.Minute),
+		NextUpdate: time.Now().Add(10 * time.Minute),
+	}
+
+	// The cached response is returned, while it is refreshed in the background.
+	response, err := client.status(leaf, pki.ca)
+	require.NoError(t, err)
+	assert.Equal(t, ocsp.Good, response.Status)
+
+	assert.Eventually(t, func() bool {
+		response, err := client.status(leaf, pki.ca)
+		require.NoError(t, err)
+
+		return response.Status == ocsp.Revoked
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRevocationCheckers(t *testing.T) {
+	pki := newTestPKI(t)
+
+	revoked, _ := pki.issue(t, "revoked.localhost")
+
+	checkers := newRevocationCheckers(newOCSPClient())
+
+	config := &RevocationCheck{OCSP: true, CRLFiles: []FileOrContent{FileOrContent(pki.crl(t))}}
+
+	checker, err := checkers.get(config)
+	require.NoError(t, err)
+
+	// The checker is kept across the rebuilds of the TLS configurations.
+	sameChecker, err := checkers.get(&RevocationCheck{OCSP: true, CRLFiles: config.CRLFiles})
+	require.NoError(t, err)
+	assert.Same(t, checker, sameChecker)
+
+	// The checker is built again when the content of the CRL files changes.
+	newConfig := &RevocationCheck{OCSP: true, CRLFiles: []FileOrContent{FileOrContent(pki.crl(t, revoked))}}
+
+	newChecker, err := checkers.get(newConfig)
+	require.NoError(t, err)
+	assert.NotSame(t, checker, newChecker)
+
+	// The checkers not used anymore are forgotten.
+	checkers.retain(map[string]Options{"default": {ClientAuth: ClientAuth{RevocationCheck: newConfig}}})
+	assert.Len(t, checkers.checkers, 1)
+}
@@ -0,0 +1,272 @@
+package tls
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"golang.org/x/crypto/ocsp"
+)
+
+// revocationStatus is the revocation status of a client certificate.
+type revocationStatus int
+
+const (
+	revocationUnknown revocationStatus = iota
+	revocationGood
+	revocationRevoked
+)
+
+// revocationChecker checks the revocation of the client certificates, with CRLs and OCSP.
+type revocationChecker struct {
+	config *RevocationCheck
+	crls   []*pkix.CertificateList
+	ocsp   *ocspClient
+}
+
+func newRevocationChecker(config *RevocationCheck, client *ocspClient) (*revocationChecker, error) {
+	checker := &revocationChecker{config: config, ocsp: client}
+
+	for _, crlFile := range config.CRLFiles {
+		data, err := crlFile.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		crls, err := parseCRLs(data)
+		if err != nil {
+			if crlFile.IsPath() {
+				return nil, fmt.Errorf("invalid CRL(s) in %s: %w", crlFile, err)
+			}
+			return nil, fmt.Errorf("invalid CRL(s) content: %w", err)
+		}
+
+		checker.crls = append(checker.crls, crls...)
+	}
+
+	if !config.OCSP && len(checker.crls) == 0 {
+		return nil, errors.New("the revocation check requires OCSP or CRL files")
+	}
+
+	return checker, nil
+}
+
+// revocationCheckers keeps the revocation checkers across the rebuilds of the TLS configurations,
+// so that the CRLs are not parsed again, and the checkers share the cached OCSP statuses.
+type revocationCheckers struct {
+	ocsp *ocspClient
+
+	mu       sync.Mutex
+	checkers map[[sha256.Size]byte]*revocationChecker
+}
+
+func newRevocationCheckers(client *ocspClient) *revocationCheckers {
+	return &revocationCheckers{
+		ocsp:     client,
+		checkers: map[[sha256.Size]byte]*revocationChecker{},
+	}
+}
+
+// get returns the checker of the configuration,
+// which is built again only when the configuration or the content of its CRL files changes.
+func (c *revocationCheckers) get(config *RevocationCheck) (*revocationChecker, error) {
+	key, err := revocationCheckKey(config)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if checker, ok := c.checkers[key]; ok {
+		return checker, nil
+	}
+
+	checker, err := newRevocationChecker(config, c.ocsp)
+	if err != nil {
+		return nil, err
+	}
+	c.checkers[key] = checker
+
+	return checker, nil
+}
+
+// retain forgets the checkers which are not used by the given TLS options.
+func (c *revocationCheckers) retain(configs map[string]Options) {
+	used := map[[sha256.Size]byte]struct{}{}
+	for _, config := range configs {
+		if config.ClientAuth.RevocationCheck == nil {
+			continue
+		}
+
+		key, err := revocationCheckKey(config.ClientAuth.RevocationCheck)
+		if err != nil {
+			continue
+		}
+		used[key] = struct{}{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.checkers {
+		if _, ok := used[key]; !ok {
+			delete(c.checkers, key)
+		}
+	}
+}
+
+// revocationCheckKey returns a key identifying the configuration, and the content of its CRL files.
+func revocationCheckKey(config *RevocationCheck) ([sha256.Size]byte, error) {
+	hash := sha256.New()
+	_ = binary.Write(hash, binary.BigEndian, [2]bool{config.OCSP, config.SoftFail})
+
+	for _, crlFile := range config.CRLFiles {
+		data, err := crlFile.Read()
+		if err != nil {
+			return [sha256.Size]byte{}, err
+		}
+
+		_ = binary.Write(hash, binary.BigEndian, uint64(len(data)))
+		_, _ = hash.Write(data)
+	}
+
+	var key [sha256.Size]byte
+	copy(key[:], hash.Sum(nil))
+
+	return key, nil
+}
+
+// verifyPeerCertificate implements the tls.Config VerifyPeerCertificate callback.
+// The leaf certificate of at least one of the verified chains must not be revoked.
+func (r *revocationChecker) verifyPeerCertificate(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+	if len(verifiedChains) == 0 {
+		// No verified client certificate to check.
+		return nil
+	}
+
+	var err error
+	for _, chain := range verifiedChains {
+		if len(chain) < 2 {
+			// Self-signed certificate directly trusted.
+			return nil
+		}
+
+		leaf, issuer := chain[0], chain[1]
+
+		switch r.status(leaf, issuer) {
+		case revocationGood:
+			return nil
+		case revocationRevoked:
+			err = fmt.Errorf("client certificate %q has been revoked", leaf.Subject)
+		default:
+			if r.config.SoftFail {
+				log.WithoutContext().Debugf("Unable to check the revocation of the client certificate %q, accepting it", leaf.Subject)
+				return nil
+			}
+			if err == nil {
+				err = fmt.Errorf("unable to check the revocation of the client certificate %q", leaf.Subject)
+			}
+		}
+	}
+
+	return err
+}
+
+// status returns the revocation status of the certificate, checked with the CRLs of its issuer then with OCSP.
+func (r *revocationChecker) status(leaf, issuer *x509.Certificate) revocationStatus {
+	status := r.crlStatus(leaf, issuer)
+	if status != revocationUnknown || !r.config.OCSP {
+		return status
+	}
+
+	response, err := r.ocsp.status(leaf, issuer)
+	if err != nil {
+		log.WithoutContext().Debugf("Unable to get the OCSP status of the client certificate %q: %v", leaf.Subject, err)
+		return revocationUnknown
+	}
+
+	switch response.Status {
+	case ocsp.Good:
+		return revocationGood
+	case ocsp.Revoked:
+		return revocationRevoked
+	default:
+		return revocationUnknown
+	}
+}
+
+func (r *revocationChecker) crlStatus(leaf, issuer *x509.Certificate) revocationStatus {
+	status := revocationUnknown
+
+	for _, crl := range r.crls {
+		if crl.TBSCertList.Issuer.String() != issuer.Subject.ToRDNSequence().String() {
+			continue
+		}
+
+		//nolint:staticcheck // x509.ParseRevocationList requires Go 1.19.
+		if err := issuer.CheckCRLSignature(crl); err != nil {
+			continue
+		}
+
+		for _, revoked := range crl.TBSCertList.RevokedCertificates {
+			if revoked.SerialNumber.Cmp(leaf.SerialNumber) == 0 {
+				return revocationRevoked
+			}
+		}
+
+		if crl.HasExpired(time.Now()) {
+			continue
+		}
+
+		status = revocationGood
+	}
+
+	return status
+}
+
+// parseCRLs parses the PEM or DER encoded CRLs.
+func parseCRLs(data []byte) ([]*pkix.CertificateList, error) {
+	if !bytes.Contains(data, []byte("-----BEGIN")) {
+		//nolint:staticcheck // x509.ParseRevocationList requires Go 1.19.
+		crl, err := x509.ParseDERCRL(data)
+		if err != nil {
+			return nil, err
+		}
+		return []*pkix.CertificateList{crl}, nil
+	}
+
+	var crls []*pkix.CertificateList
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "X509 CRL" {
+			continue
+		}
+
+		//nolint:staticcheck // x509.ParseRevocationList requires Go 1.19.
+		crl, err := x509.ParseDERCRL(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		crls = append(crls, crl)
+	}
+
+	if len(crls) == 0 {
+		return nil, errors.New("no CRL found")
+	}
+
+	return crls, nil
+}
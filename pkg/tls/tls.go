@@ -10,6 +10,20 @@ type ClientAuth struct {
 	// ClientAuthType defines the client authentication type to apply.
 	// The available values are: "NoClientCert", "RequestClientCert", "VerifyClientCertIfGiven" and "RequireAndVerifyClientCert".
 	ClientAuthType string `json:"clientAuthType,omitempty" toml:"clientAuthType,omitempty" yaml:"clientAuthType,omitempty" export:"true"`
+	// RevocationCheck defines how the revocation of the verified client certificates is checked.
+	RevocationCheck *RevocationCheck `json:"revocationCheck,omitempty" toml:"revocationCheck,omitempty" yaml:"revocationCheck,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RevocationCheck defines how the revocation of the client certificates is checked.
+type RevocationCheck struct {
+	// OCSP enables checking the status of the client certificates with the OCSP responders they reference.
+	OCSP bool `json:"ocsp,omitempty" toml:"ocsp,omitempty" yaml:"ocsp,omitempty" export:"true"`
+	// CRLFiles are the certificate revocation lists of the client certificate issuers.
+	CRLFiles []FileOrContent `json:"crlFiles,omitempty" toml:"crlFiles,omitempty" yaml:"crlFiles,omitempty"`
+	// SoftFail accepts the client certificates whose revocation status cannot be determined.
+	SoftFail bool `json:"softFail,omitempty" toml:"softFail,omitempty" yaml:"softFail,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -37,6 +51,17 @@ func (o *Options) SetDefaults() {
 // Store holds the options for a given Store.
 type Store struct {
 	DefaultCertificate *Certificate `json:"defaultCertificate,omitempty" toml:"defaultCertificate,omitempty" yaml:"defaultCertificate,omitempty" export:"true"`
+	SNIOptions         []SNIOptions `json:"sniOptions,omitempty" toml:"sniOptions,omitempty" yaml:"sniOptions,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// SNIOptions selects the TLS options of the connections whose server name (SNI) matches one of the patterns,
+// whatever the options of the routers.
+type SNIOptions struct {
+	// SNI holds the server name patterns, either a domain or a wildcard domain (e.g. *.example.com) matching all its subdomains.
+	SNI     []string `json:"sni,omitempty" toml:"sni,omitempty" yaml:"sni,omitempty" export:"true"`
+	Options string   `json:"options,omitempty" toml:"options,omitempty" yaml:"options,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
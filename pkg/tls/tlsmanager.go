@@ -6,6 +6,7 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
@@ -29,25 +30,49 @@ var DefaultTLSOptions = Options{
 	ALPNProtocols: []string{"h2", "http/1.1", tlsalpn01.ACMETLS1Protocol},
 }
 
+// OnDemandResolver obtains certificates during the TLS handshakes,
+// for the server names with no certificate in the default store.
+type OnDemandResolver interface {
+	// GetCertificate returns a certificate for the server name, or nil if none can be obtained.
+	GetCertificate(serverName string) (*tls.Certificate, error)
+}
+
 // Manager is the TLS option/store/configuration factory.
 type Manager struct {
-	lock         sync.RWMutex
-	storesConfig map[string]Store
-	stores       map[string]*CertificateStore
-	configs      map[string]Options
-	certs        []*CertAndStores
+	lock              sync.RWMutex
+	storesConfig      map[string]Store
+	stores            map[string]*CertificateStore
+	configs           map[string]Options
+	certs             []*CertAndStores
+	onDemandResolvers []OnDemandResolver
+	revocationChecks  *revocationCheckers
+	stapler           *ocspStapler
 }
 
 // NewManager creates a new Manager.
 func NewManager() *Manager {
+	client := newOCSPClient()
+
 	return &Manager{
 		stores: map[string]*CertificateStore{},
 		configs: map[string]Options{
 			"default": DefaultTLSOptions,
 		},
+		revocationChecks: newRevocationCheckers(client),
+		stapler:          newOCSPStapler(client),
 	}
 }
 
+// AddOnDemandResolver adds a resolver asked for a certificate during the TLS handshakes,
+// when no certificate of the default store matches the server name.
+// The resolvers are asked in the order they have been added, until one of them returns a certificate.
+func (m *Manager) AddOnDemandResolver(resolver OnDemandResolver) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.onDemandResolvers = append(m.onDemandResolvers, resolver)
+}
+
 // UpdateConfigs updates the TLS* configuration options.
 // It initializes the default TLS store, and the TLS store for the ACME challenges.
 func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, configs map[string]Options, certs []*CertAndStores) {
@@ -73,6 +98,10 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 	m.stores = make(map[string]*CertificateStore)
 	for storeName, storeConfig := range m.storesConfig {
 		ctxStore := log.With(ctx, log.Str(log.TLSStoreName, storeName))
+		if err := m.validateSNIOptions(storeConfig.SNIOptions); err != nil {
+			log.FromContext(ctxStore).Errorf("Invalid SNI options, the matching connections will be refused: %v", err)
+		}
+
 		store, err := buildCertificateStore(ctxStore, storeConfig, storeName)
 		if err != nil {
 			log.FromContext(ctxStore).Errorf("Error while creating certificate store: %v", err)
@@ -106,13 +135,126 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 		}
 		st.DynamicCerts.Set(certs)
 	}
+
+	m.revocationChecks.retain(m.configs)
+	m.stapler.update(m.servedCertificates())
+}
+
+// validateSNIOptions checks that the SNI options reference existing TLS options, with valid patterns.
+func (m *Manager) validateSNIOptions(sniOptions []SNIOptions) error {
+	for _, options := range sniOptions {
+		if _, ok := m.configs[options.Options]; !ok {
+			return fmt.Errorf("unknown TLS options: %s", options.Options)
+		}
+
+		if len(options.SNI) == 0 {
+			return fmt.Errorf("no SNI for the TLS options %s", options.Options)
+		}
+
+		for _, pattern := range options.SNI {
+			if pattern == "" || strings.Contains(strings.TrimPrefix(pattern, "*."), "*") {
+				return fmt.Errorf("invalid SNI pattern %q for the TLS options %s", pattern, options.Options)
+			}
+		}
+	}
+
+	return nil
+}
+
+// servedCertificates returns the default and dynamic certificates of the stores, except the ACME TLS store.
+func (m *Manager) servedCertificates() []*tls.Certificate {
+	var certificates []*tls.Certificate
+	for storeName, store := range m.stores {
+		if storeName == tlsalpn01.ACMETLS1Protocol {
+			continue
+		}
+
+		if store.DefaultCertificate != nil {
+			certificates = append(certificates, store.DefaultCertificate)
+		}
+
+		if store.DynamicCerts != nil && store.DynamicCerts.Get() != nil {
+			for _, cert := range store.DynamicCerts.Get().(map[string]*tls.Certificate) {
+				certificates = append(certificates, cert)
+			}
+		}
+	}
+
+	return certificates
 }
 
 // Get gets the TLS configuration to use for a given store / configuration.
+// When the server name of a connection matches the SNI options of the store,
+// the configuration of the matching options is used instead.
 func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 	m.lock.RLock()
 	defer m.lock.RUnlock()
 
+	tlsConfig, err := m.get(storeName, configName)
+
+	sniConfigs := m.buildSNIConfigs(storeName, configName)
+	if len(sniConfigs) > 0 {
+		tlsConfig.GetConfigForClient = func(clientHello *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverName := types.CanonicalDomain(clientHello.ServerName)
+
+			for _, sniConfig := range sniConfigs {
+				if matchSNI(sniConfig.patterns, serverName) {
+					// A nil configuration keeps using the current one.
+					return sniConfig.config, sniConfig.err
+				}
+			}
+
+			return nil, nil
+		}
+	}
+
+	return tlsConfig, err
+}
+
+// GetSNIOptionsName returns the name of the TLS options selected by the SNI options of the store for the server name.
+func (m *Manager) GetSNIOptionsName(storeName, serverName string) (string, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	serverName = types.CanonicalDomain(serverName)
+
+	for _, sniOptions := range m.storesConfig[storeName].SNIOptions {
+		if matchSNI(sniOptions.SNI, serverName) {
+			return sniOptions.Options, true
+		}
+	}
+
+	return "", false
+}
+
+type sniConfig struct {
+	patterns []string
+	config   *tls.Config
+	err      error
+}
+
+// buildSNIConfigs builds the TLS configurations of the SNI options of the store.
+// The connections matching SNI options which cannot be built are refused, rather than served with other options.
+func (m *Manager) buildSNIConfigs(storeName, configName string) []sniConfig {
+	var sniConfigs []sniConfig
+	for _, sniOptions := range m.storesConfig[storeName].SNIOptions {
+		config := sniConfig{patterns: sniOptions.SNI}
+
+		if sniOptions.Options != configName {
+			config.config, config.err = m.get(storeName, sniOptions.Options)
+			if config.err != nil {
+				config.config = nil
+				config.err = fmt.Errorf("invalid TLS options %s for the SNI options of the TLS store %s: %w", sniOptions.Options, storeName, config.err)
+			}
+		}
+
+		sniConfigs = append(sniConfigs, config)
+	}
+
+	return sniConfigs
+}
+
+func (m *Manager) get(storeName, configName string) (*tls.Config, error) {
 	var tlsConfig *tls.Config
 	var err error
 
@@ -120,7 +262,7 @@ func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 	config, ok := m.configs[configName]
 	if ok {
 		sniStrict = config.SniStrict
-		tlsConfig, err = buildTLSConfig(config)
+		tlsConfig, err = buildTLSConfig(config, m.revocationChecks)
 	} else {
 		err = fmt.Errorf("unknown TLS options: %s", configName)
 	}
@@ -151,7 +293,15 @@ func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 
 		bestCertificate := store.GetBestCertificate(clientHello)
 		if bestCertificate != nil {
-			return bestCertificate, nil
+			return m.stapler.staple(bestCertificate), nil
+		}
+
+		if storeName == DefaultTLSStoreName && len(domainToCheck) > 0 {
+			if certificate := m.getOnDemandCertificate(domainToCheck); certificate != nil {
+				// Serves the certificate until it is added to the store by the resolver.
+				store.CertCache.SetDefault(domainToCheck, certificate)
+				return certificate, nil
+			}
 		}
 
 		if sniStrict {
@@ -159,12 +309,33 @@ func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 		}
 
 		log.WithoutContext().Debugf("Serving default certificate for request: %q", domainToCheck)
-		return store.DefaultCertificate, nil
+		return m.stapler.staple(store.DefaultCertificate), nil
 	}
 
 	return tlsConfig, err
 }
 
+// getOnDemandCertificate asks the on-demand resolvers for a certificate for the domain.
+func (m *Manager) getOnDemandCertificate(domain string) *tls.Certificate {
+	m.lock.RLock()
+	resolvers := m.onDemandResolvers
+	m.lock.RUnlock()
+
+	for _, resolver := range resolvers {
+		certificate, err := resolver.GetCertificate(domain)
+		if err != nil {
+			log.WithoutContext().Debugf("Unable to obtain a certificate on demand for %q: %v", domain, err)
+			continue
+		}
+
+		if certificate != nil {
+			return certificate
+		}
+	}
+
+	return nil
+}
+
 // GetCertificates returns all stored certificates.
 func (m *Manager) GetCertificates() []*x509.Certificate {
 	var certificates []*x509.Certificate
@@ -232,7 +403,7 @@ func buildCertificateStore(ctx context.Context, tlsStore Store, storename string
 }
 
 // creates a TLS config that allows terminating HTTPS for multiple domains using SNI.
-func buildTLSConfig(tlsOption Options) (*tls.Config, error) {
+func buildTLSConfig(tlsOption Options, revocationChecks *revocationCheckers) (*tls.Config, error) {
 	conf := &tls.Config{
 		NextProtos: tlsOption.ALPNProtocols,
 	}
@@ -279,6 +450,18 @@ func buildTLSConfig(tlsOption Options) (*tls.Config, error) {
 		}
 	}
 
+	if tlsOption.ClientAuth.RevocationCheck != nil {
+		if conf.ClientCAs == nil {
+			return nil, errors.New("the revocation check of the client certificates requires CAFiles")
+		}
+
+		checker, err := revocationChecks.get(tlsOption.ClientAuth.RevocationCheck)
+		if err != nil {
+			return nil, err
+		}
+		conf.VerifyPeerCertificate = checker.verifyPeerCertificate
+	}
+
 	// Set PreferServerCipherSuites.
 	conf.PreferServerCipherSuites = tlsOption.PreferServerCipherSuites
 
@@ -352,3 +535,28 @@ func isACMETLS(clientHello *tls.ClientHelloInfo) bool {
 
 	return false
 }
+
+// matchSNI checks whether the server name matches one of the patterns.
+// A wildcard pattern (e.g. *.example.com) matches all the subdomains, at any depth.
+func matchSNI(patterns []string, serverName string) bool {
+	if serverName == "" {
+		return false
+	}
+
+	for _, pattern := range patterns {
+		pattern = types.CanonicalDomain(pattern)
+
+		if strings.HasPrefix(pattern, "*.") {
+			if strings.HasSuffix(serverName, pattern[1:]) {
+				return true
+			}
+			continue
+		}
+
+		if serverName == pattern {
+			return true
+		}
+	}
+
+	return false
+}
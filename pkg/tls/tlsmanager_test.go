@@ -152,6 +152,148 @@ func TestManager_Get(t *testing.T) {
 	}
 }
 
+func TestManager_Get_onDemand(t *testing.T) {
+	certificate, err := tls.X509KeyPair([]byte(localhostCert), []byte(localhostKey))
+	require.NoError(t, err)
+
+	resolver := &onDemandResolverMock{
+		certificates: map[string]*tls.Certificate{"foo.com": &certificate},
+		calls:        map[string]int{},
+	}
+
+	tlsManager := NewManager()
+	tlsManager.UpdateConfigs(context.Background(), nil, map[string]Options{"default": {}}, nil)
+	tlsManager.AddOnDemandResolver(resolver)
+
+	config, err := tlsManager.Get(DefaultTLSStoreName, DefaultTLSConfigName)
+	require.NoError(t, err)
+
+	cert, err := config.GetCertificate(&tls.ClientHelloInfo{ServerName: "foo.com"})
+	require.NoError(t, err)
+	assert.Equal(t, &certificate, cert)
+
+	// The certificate is then served from the store cache.
+	cert, err = config.GetCertificate(&tls.ClientHelloInfo{ServerName: "foo.com"})
+	require.NoError(t, err)
+	assert.Equal(t, &certificate, cert)
+	assert.Equal(t, 1, resolver.calls["foo.com"])
+
+	// The default certificate is served when no certificate is obtained.
+	cert, err = config.GetCertificate(&tls.ClientHelloInfo{ServerName: "bar.com"})
+	require.NoError(t, err)
+	assert.Equal(t, tlsManager.GetStore(DefaultTLSStoreName).DefaultCertificate, cert)
+	assert.Equal(t, 1, resolver.calls["bar.com"])
+}
+
+type onDemandResolverMock struct {
+	certificates map[string]*tls.Certificate
+	calls        map[string]int
+}
+
+func (r *onDemandResolverMock) GetCertificate(serverName string) (*tls.Certificate, error) {
+	r.calls[serverName]++
+	return r.certificates[serverName], nil
+}
+
+func TestManager_Get_sniOptions(t *testing.T) {
+	stores := map[string]Store{
+		DefaultTLSStoreName: {
+			SNIOptions: []SNIOptions{
+				{SNI: []string{"*.bank.example.com"}, Options: "strict"},
+				{SNI: []string{"legacy.bank.example.com"}, Options: "legacy"},
+				{SNI: []string{"app.example.com"}, Options: "unknown"},
+			},
+		},
+	}
+	configs := map[string]Options{
+		"default": {},
+		"strict":  {MinVersion: "VersionTLS13"},
+		"legacy":  {MinVersion: "VersionTLS10"},
+	}
+
+	tlsManager := NewManager()
+	tlsManager.UpdateConfigs(context.Background(), stores, configs, nil)
+
+	config, err := tlsManager.Get(DefaultTLSStoreName, DefaultTLSConfigName)
+	require.NoError(t, err)
+	require.NotNil(t, config.GetConfigForClient)
+
+	testCases := []struct {
+		desc               string
+		serverName         string
+		expectedMinVersion uint16
+		expectedOptions    string
+		expectedErr        bool
+	}{
+		{
+			desc:               "subdomain",
+			serverName:         "www.bank.example.com",
+			expectedMinVersion: tls.VersionTLS13,
+			expectedOptions:    "strict",
+		},
+		{
+			desc:               "deep subdomain, case insensitive",
+			serverName:         "Api.EU.bank.example.com",
+			expectedMinVersion: tls.VersionTLS13,
+			expectedOptions:    "strict",
+		},
+		{
+			desc:               "first matching SNI options",
+			serverName:         "legacy.bank.example.com",
+			expectedMinVersion: tls.VersionTLS13,
+			expectedOptions:    "strict",
+		},
+		{
+			desc:       "domain of the wildcard",
+			serverName: "bank.example.com",
+		},
+		{
+			desc: "no server name",
+		},
+		{
+			desc:            "unknown TLS options",
+			serverName:      "app.example.com",
+			expectedOptions: "unknown",
+			expectedErr:     true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			optionsName, ok := tlsManager.GetSNIOptionsName(DefaultTLSStoreName, test.serverName)
+			assert.Equal(t, test.expectedOptions != "", ok)
+			assert.Equal(t, test.expectedOptions, optionsName)
+
+			sniConfig, err := config.GetConfigForClient(&tls.ClientHelloInfo{ServerName: test.serverName})
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if test.expectedMinVersion == 0 {
+				assert.Nil(t, sniConfig)
+				return
+			}
+
+			require.NotNil(t, sniConfig)
+			assert.Equal(t, test.expectedMinVersion, sniConfig.MinVersion)
+			assert.NotNil(t, sniConfig.GetCertificate)
+		})
+	}
+
+	// The connections already using the matching options keep their configuration.
+	strictConfig, err := tlsManager.Get(DefaultTLSStoreName, "strict")
+	require.NoError(t, err)
+
+	sniConfig, err := strictConfig.GetConfigForClient(&tls.ClientHelloInfo{ServerName: "www.bank.example.com"})
+	require.NoError(t, err)
+	assert.Nil(t, sniConfig)
+}
+
 func TestClientAuth(t *testing.T) {
 	tlsConfigs := map[string]Options{
 		"eca": {
@@ -59,6 +59,11 @@ func (in *ClientAuth) DeepCopyInto(out *ClientAuth) {
 		*out = make([]FileOrContent, len(*in))
 		copy(*out, *in)
 	}
+	if in.RevocationCheck != nil {
+		in, out := &in.RevocationCheck, &out.RevocationCheck
+		*out = new(RevocationCheck)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -104,6 +109,48 @@ func (in *Options) DeepCopy() *Options {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevocationCheck) DeepCopyInto(out *RevocationCheck) {
+	*out = *in
+	if in.CRLFiles != nil {
+		in, out := &in.CRLFiles, &out.CRLFiles
+		*out = make([]FileOrContent, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevocationCheck.
+func (in *RevocationCheck) DeepCopy() *RevocationCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(RevocationCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SNIOptions) DeepCopyInto(out *SNIOptions) {
+	*out = *in
+	if in.SNI != nil {
+		in, out := &in.SNI, &out.SNI
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SNIOptions.
+func (in *SNIOptions) DeepCopy() *SNIOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(SNIOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Store) DeepCopyInto(out *Store) {
 	*out = *in
@@ -112,6 +159,13 @@ func (in *Store) DeepCopyInto(out *Store) {
 		*out = new(Certificate)
 		**out = **in
 	}
+	if in.SNIOptions != nil {
+		in, out := &in.SNIOptions, &out.SNIOptions
+		*out = make([]SNIOptions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
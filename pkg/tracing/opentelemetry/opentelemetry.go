@@ -0,0 +1,194 @@
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/types"
+	"github.com/traefik/traefik/v2/pkg/version"
+	"go.opentelemetry.io/otel/attribute"
+	otbridge "go.opentelemetry.io/otel/bridge/opentracing"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/credentials"
+)
+
+// Name sets the name of this tracer.
+const Name = "opentelemetry"
+
+// Config provides configuration settings for an OpenTelemetry tracer.
+type Config struct {
+	GRPC               *GRPC             `description:"Settings for the gRPC OTLP exporter (takes precedence over HTTP)." json:"grpc,omitempty" toml:"grpc,omitempty" yaml:"grpc,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	HTTP               *HTTP             `description:"Settings for the HTTP OTLP exporter." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	SampleRate         *float64          `description:"Sets the rate between 0.0 and 1.0 of requests to trace." json:"sampleRate,omitempty" toml:"sampleRate,omitempty" yaml:"sampleRate,omitempty" export:"true"`
+	ResourceAttributes map[string]string `description:"Defines additional resource attributes (key:value)." json:"resourceAttributes,omitempty" toml:"resourceAttributes,omitempty" yaml:"resourceAttributes,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *Config) SetDefaults() {
+	c.HTTP = &HTTP{}
+	c.HTTP.SetDefaults()
+	sampleRate := 1.0
+	c.SampleRate = &sampleRate
+}
+
+// GRPC provides configuration settings for the gRPC OTLP exporter.
+type GRPC struct {
+	Endpoint string            `description:"Sets the address (host:port) of the collector gRPC endpoint." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Insecure bool              `description:"Disables client transport security for the exporter." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
+	Headers  map[string]string `description:"Defines additional headers to be sent with the payloads." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
+	TLS      *types.ClientTLS  `description:"Defines client transport security parameters." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *GRPC) SetDefaults() {
+	c.Endpoint = "localhost:4317"
+}
+
+// HTTP provides configuration settings for the HTTP OTLP exporter.
+type HTTP struct {
+	Endpoint string            `description:"Sets the URL of the collector HTTP endpoint." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Headers  map[string]string `description:"Defines additional headers to be sent with the payloads." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
+	TLS      *types.ClientTLS  `description:"Defines client transport security parameters." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *HTTP) SetDefaults() {
+	c.Endpoint = "http://localhost:4318/v1/traces"
+}
+
+// Setup sets up the tracer.
+func (c *Config) Setup(componentName string) (opentracing.Tracer, io.Closer, error) {
+	var exporter *otlptrace.Exporter
+	var err error
+	if c.GRPC != nil {
+		exporter, err = c.setupGRPCExporter()
+	} else {
+		exporter, err = c.setupHTTPExporter()
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("setting up exporter: %w", err)
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.ServiceNameKey.String(componentName),
+		semconv.ServiceVersionKey.String(version.Version),
+	}
+	for k, v := range c.ResourceAttributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	res, err := resource.New(context.Background(),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(attrs...),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("building resource: %w", err)
+	}
+
+	sampleRate := 1.0
+	if c.SampleRate != nil {
+		sampleRate = *c.SampleRate
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRate))),
+		sdktrace.WithBatcher(exporter),
+	)
+
+	bt := otbridge.NewBridgeTracer()
+	bt.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	bt.SetOpenTelemetryTracer(tracerProvider.Tracer(componentName, trace.WithInstrumentationVersion(version.Version)))
+	bt.SetWarningHandler(func(msg string) {
+		log.WithoutContext().Debugf("OpenTelemetry bridge: %s", msg)
+	})
+
+	// Without this, child spans are getting the NOOP tracer
+	opentracing.SetGlobalTracer(bt)
+
+	log.WithoutContext().Debug("OpenTelemetry tracer configured")
+
+	return bt, tpCloser{provider: tracerProvider}, nil
+}
+
+func (c *Config) setupHTTPExporter() (*otlptrace.Exporter, error) {
+	endpoint, err := url.Parse(c.HTTP.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid collector endpoint %q: %w", c.HTTP.Endpoint, err)
+	}
+
+	if (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid collector endpoint %q: the URL must have an http or https scheme and a host", c.HTTP.Endpoint)
+	}
+
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(endpoint.Host),
+		otlptracehttp.WithHeaders(c.HTTP.Headers),
+	}
+
+	if endpoint.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	if endpoint.Path != "" {
+		opts = append(opts, otlptracehttp.WithURLPath(endpoint.Path))
+	}
+
+	if c.HTTP.TLS != nil {
+		tlsConfig, err := c.HTTP.TLS.CreateTLSConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("creating TLS client config: %w", err)
+		}
+
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsConfig))
+	}
+
+	return otlptrace.New(context.Background(), otlptracehttp.NewClient(opts...))
+}
+
+func (c *Config) setupGRPCExporter() (*otlptrace.Exporter, error) {
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(c.GRPC.Endpoint),
+		otlptracegrpc.WithHeaders(c.GRPC.Headers),
+	}
+
+	if c.GRPC.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	if c.GRPC.TLS != nil {
+		tlsConfig, err := c.GRPC.TLS.CreateTLSConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("creating TLS client config: %w", err)
+		}
+
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+	}
+
+	return otlptrace.New(context.Background(), otlptracegrpc.NewClient(opts...))
+}
+
+// tpCloser converts a TracerProvider into an io.Closer.
+type tpCloser struct {
+	provider *sdktrace.TracerProvider
+}
+
+func (t tpCloser) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return t.provider.Shutdown(ctx)
+}
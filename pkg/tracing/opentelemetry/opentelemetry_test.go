@@ -0,0 +1,216 @@
+package opentelemetry
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+)
+
+type traceCollector struct {
+	coltracepb.UnimplementedTraceServiceServer
+
+	requests chan *coltracepb.ExportTraceServiceRequest
+}
+
+func (c *traceCollector) Export(_ context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	c.requests <- req
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func TestTracing(t *testing.T) {
+	tests := []struct {
+		desc  string
+		setup func(t *testing.T, collector *traceCollector) *Config
+	}{
+		{
+			desc: "HTTP exporter",
+			setup: func(t *testing.T, collector *traceCollector) *Config {
+				t.Helper()
+
+				srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					assert.Equal(t, "/v1/traces", req.URL.Path)
+					assert.Equal(t, "bar", req.Header.Get("X-Foo"))
+
+					body, err := io.ReadAll(req.Body)
+					require.NoError(t, err)
+
+					exportReq := &coltracepb.ExportTraceServiceRequest{}
+					require.NoError(t, proto.Unmarshal(body, exportReq))
+
+					collector.requests <- exportReq
+				}))
+				t.Cleanup(srv.Close)
+
+				return &Config{
+					HTTP: &HTTP{
+						Endpoint: srv.URL + "/v1/traces",
+						Headers:  map[string]string{"X-Foo": "bar"},
+					},
+					ResourceAttributes: map[string]string{"deployment.environment": "test"},
+				}
+			},
+		},
+		{
+			desc: "gRPC exporter",
+			setup: func(t *testing.T, collector *traceCollector) *Config {
+				t.Helper()
+
+				listener, err := net.Listen("tcp", "127.0.0.1:0")
+				require.NoError(t, err)
+
+				srv := grpc.NewServer()
+				coltracepb.RegisterTraceServiceServer(srv, collector)
+				go func() { _ = srv.Serve(listener) }()
+				t.Cleanup(srv.Stop)
+
+				return &Config{
+					GRPC: &GRPC{
+						Endpoint: listener.Addr().String(),
+						Insecure: true,
+					},
+					ResourceAttributes: map[string]string{"deployment.environment": "test"},
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			collector := &traceCollector{requests: make(chan *coltracepb.ExportTraceServiceRequest, 10)}
+			config := test.setup(t, collector)
+
+			tracer, closer, err := config.Setup("traefik")
+			require.NoError(t, err)
+
+			span := tracer.StartSpan("EntryPoint web")
+
+			headers := http.Header{}
+			err = tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(headers))
+			require.NoError(t, err)
+			assert.Regexp(t, `^00-[0-9a-f]{32}-[0-9a-f]{16}-01$`, headers.Get("traceparent"))
+
+			span.Finish()
+
+			require.NoError(t, closer.Close())
+
+			var req *coltracepb.ExportTraceServiceRequest
+			select {
+			case req = <-collector.requests:
+			default:
+				t.Fatal("no spans exported to the collector")
+			}
+
+			require.Len(t, req.ResourceSpans, 1)
+
+			attributes := map[string]string{}
+			for _, attr := range req.ResourceSpans[0].Resource.Attributes {
+				attributes[attr.Key] = attr.Value.GetStringValue()
+			}
+			assert.Equal(t, "traefik", attributes["service.name"])
+			assert.Equal(t, "test", attributes["deployment.environment"])
+
+			require.Len(t, req.ResourceSpans[0].InstrumentationLibrarySpans, 1)
+			spans := req.ResourceSpans[0].InstrumentationLibrarySpans[0].Spans
+			require.Len(t, spans, 1)
+			assert.Equal(t, "EntryPoint web", spans[0].Name)
+		})
+	}
+}
+
+func TestTracing_extractTraceParent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	t.Cleanup(srv.Close)
+
+	config := &Config{}
+	config.SetDefaults()
+	config.HTTP.Endpoint = srv.URL
+
+	tracer, closer, err := config.Setup("traefik")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = closer.Close() })
+
+	headers := http.Header{}
+	headers.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	spanCtx, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(headers))
+	require.NoError(t, err)
+
+	span := tracer.StartSpan("child", opentracing.ChildOf(spanCtx))
+	defer span.Finish()
+
+	injected := http.Header{}
+	err = tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(injected))
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^00-4bf92f3577b34da6a3ce929d0e0e4736-[0-9a-f]{16}-01$`, injected.Get("traceparent"))
+}
+
+func TestTracing_sampleRate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	t.Cleanup(srv.Close)
+
+	config := &Config{}
+	config.SetDefaults()
+	config.HTTP.Endpoint = srv.URL
+
+	sampleRate := 0.0
+	config.SampleRate = &sampleRate
+
+	tracer, closer, err := config.Setup("traefik")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = closer.Close() })
+
+	span := tracer.StartSpan("EntryPoint web")
+	defer span.Finish()
+
+	headers := http.Header{}
+	err = tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(headers))
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^00-[0-9a-f]{32}-[0-9a-f]{16}-00$`, headers.Get("traceparent"))
+}
+
+func TestTracing_invalidHTTPEndpoint(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		endpoint string
+	}{
+		{
+			desc:     "without scheme",
+			endpoint: "localhost:4318/v1/traces",
+		},
+		{
+			desc:     "unsupported scheme",
+			endpoint: "ftp://localhost:4318/v1/traces",
+		},
+		{
+			desc:     "without host",
+			endpoint: "http:///v1/traces",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := &Config{}
+			config.SetDefaults()
+			config.HTTP.Endpoint = test.endpoint
+
+			_, _, err := config.Setup("traefik")
+			assert.Error(t, err)
+		})
+	}
+}
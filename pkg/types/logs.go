@@ -1,6 +1,16 @@
 package types
 
-import "github.com/traefik/paerser/types"
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
 
 const (
 	// AccessLogKeep is the keep string value.
@@ -21,9 +31,10 @@ const (
 
 // TraefikLog holds the configuration settings for the traefik logger.
 type TraefikLog struct {
-	Level    string `description:"Log level set to traefik logs." json:"level,omitempty" toml:"level,omitempty" yaml:"level,omitempty" export:"true"`
-	FilePath string `description:"Traefik log file path. Stdout is used when omitted or empty." json:"filePath,omitempty" toml:"filePath,omitempty" yaml:"filePath,omitempty"`
-	Format   string `description:"Traefik log format: json | common" json:"format,omitempty" toml:"format,omitempty" yaml:"format,omitempty" export:"true"`
+	Level    string   `description:"Log level set to traefik logs." json:"level,omitempty" toml:"level,omitempty" yaml:"level,omitempty" export:"true"`
+	FilePath string   `description:"Traefik log file path. Stdout is used when omitted or empty." json:"filePath,omitempty" toml:"filePath,omitempty" yaml:"filePath,omitempty"`
+	Format   string   `description:"Traefik log format: json | common" json:"format,omitempty" toml:"format,omitempty" yaml:"format,omitempty" export:"true"`
+	Sink     *LogSink `description:"Ships the Traefik logs to a remote output, instead of the file path or stdout." json:"sink,omitempty" toml:"sink,omitempty" yaml:"sink,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -39,6 +50,7 @@ type AccessLog struct {
 	Filters       *AccessLogFilters `description:"Access log filters, used to keep only specific access logs." json:"filters,omitempty" toml:"filters,omitempty" yaml:"filters,omitempty" export:"true"`
 	Fields        *AccessLogFields  `description:"AccessLogFields." json:"fields,omitempty" toml:"fields,omitempty" yaml:"fields,omitempty" export:"true"`
 	BufferingSize int64             `description:"Number of access log lines to process in a buffered way." json:"bufferingSize,omitempty" toml:"bufferingSize,omitempty" yaml:"bufferingSize,omitempty" export:"true"`
+	Sink          *LogSink          `description:"Ships the access logs to a remote output, instead of the file path or stdout." json:"sink,omitempty" toml:"sink,omitempty" yaml:"sink,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -50,6 +62,154 @@ func (l *AccessLog) SetDefaults() {
 	l.Fields.SetDefaults()
 }
 
+// Overflow policies of a log sink, applied when its buffer is full.
+const (
+	LogSinkOverflowDrop  = "drop"
+	LogSinkOverflowBlock = "block"
+)
+
+// LogSink holds the configuration of a remote output for logs.
+type LogSink struct {
+	BufferSize     int         `description:"Maximum number of log lines waiting to be shipped." json:"bufferSize,omitempty" toml:"bufferSize,omitempty" yaml:"bufferSize,omitempty" export:"true"`
+	OverflowPolicy string      `description:"Policy applied to the log lines written while the buffer is full: drop | block" json:"overflowPolicy,omitempty" toml:"overflowPolicy,omitempty" yaml:"overflowPolicy,omitempty" export:"true"`
+	Syslog         *SyslogSink `description:"Ships the logs to a syslog server, in the RFC 5424 format." json:"syslog,omitempty" toml:"syslog,omitempty" yaml:"syslog,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	TCP            *TCPSink    `description:"Ships the logs as newline-delimited lines over TCP." json:"tcp,omitempty" toml:"tcp,omitempty" yaml:"tcp,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	HTTP           *HTTPSink   `description:"Ships the logs in batches with HTTP POST requests." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (s *LogSink) SetDefaults() {
+	s.BufferSize = 1024
+	s.OverflowPolicy = LogSinkOverflowDrop
+}
+
+// CreateSink creates the log.Sink shipping the logs named name (e.g. access) to the configured output.
+func (s *LogSink) CreateSink(ctx context.Context, name string) (*log.Sink, error) {
+	options := log.SinkOptions{
+		Name:       name,
+		BufferSize: s.BufferSize,
+	}
+
+	switch s.OverflowPolicy {
+	case LogSinkOverflowDrop, "":
+	case LogSinkOverflowBlock:
+		options.Block = true
+	default:
+		return nil, fmt.Errorf("unknown overflow policy: %q", s.OverflowPolicy)
+	}
+
+	var output log.Output
+	switch {
+	case s.Syslog != nil && s.TCP == nil && s.HTTP == nil:
+		tlsConfig, err := createSinkTLSConfig(ctx, s.Syslog.TLS)
+		if err != nil {
+			return nil, err
+		}
+
+		output, err = log.NewSyslogOutput(s.Syslog.Protocol, s.Syslog.Address, tlsConfig, s.Syslog.Facility, s.Syslog.Hostname, s.Syslog.AppName, time.Duration(s.Syslog.WriteTimeout))
+		if err != nil {
+			return nil, err
+		}
+
+	case s.TCP != nil && s.Syslog == nil && s.HTTP == nil:
+		tlsConfig, err := createSinkTLSConfig(ctx, s.TCP.TLS)
+		if err != nil {
+			return nil, err
+		}
+
+		output = log.NewTCPOutput(s.TCP.Address, tlsConfig, time.Duration(s.TCP.WriteTimeout))
+
+	case s.HTTP != nil && s.Syslog == nil && s.TCP == nil:
+		tlsConfig, err := createSinkTLSConfig(ctx, s.HTTP.TLS)
+		if err != nil {
+			return nil, err
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+
+		client := &http.Client{Transport: transport, Timeout: time.Duration(s.HTTP.Timeout)}
+
+		labels := s.HTTP.Labels
+		if len(labels) == 0 {
+			labels = map[string]string{"job": "traefik"}
+		}
+
+		output, err = log.NewHTTPOutput(s.HTTP.URL, s.HTTP.Format, s.HTTP.Headers, labels, s.HTTP.Index, client)
+		if err != nil {
+			return nil, err
+		}
+
+		options.BatchSize = s.HTTP.BatchSize
+		options.FlushInterval = time.Duration(s.HTTP.FlushInterval)
+
+	default:
+		return nil, errors.New("exactly one of syslog, tcp or http must be configured")
+	}
+
+	return log.NewSink(output, options), nil
+}
+
+func createSinkTLSConfig(ctx context.Context, clientTLS *ClientTLS) (*tls.Config, error) {
+	if clientTLS == nil {
+		return nil, nil
+	}
+	return clientTLS.CreateTLSConfig(ctx)
+}
+
+// SyslogSink holds the configuration of a syslog log output.
+type SyslogSink struct {
+	Address      string         `description:"Syslog server address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	Protocol     string         `description:"Protocol used to reach the syslog server: udp | tcp | tls" json:"protocol,omitempty" toml:"protocol,omitempty" yaml:"protocol,omitempty" export:"true"`
+	TLS          *ClientTLS     `description:"TLS configuration, used with the tls protocol." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	Facility     string         `description:"Syslog facility of the messages." json:"facility,omitempty" toml:"facility,omitempty" yaml:"facility,omitempty" export:"true"`
+	AppName      string         `description:"Application name of the messages." json:"appName,omitempty" toml:"appName,omitempty" yaml:"appName,omitempty" export:"true"`
+	Hostname     string         `description:"Hostname of the messages. Defaults to the host name reported by the system." json:"hostname,omitempty" toml:"hostname,omitempty" yaml:"hostname,omitempty"`
+	WriteTimeout types.Duration `description:"Timeout of the writes to the syslog server." json:"writeTimeout,omitempty" toml:"writeTimeout,omitempty" yaml:"writeTimeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (s *SyslogSink) SetDefaults() {
+	s.Address = "localhost:514"
+	s.Protocol = "udp"
+	s.Facility = "local0"
+	s.AppName = "traefik"
+	s.WriteTimeout = types.Duration(log.DefaultSinkWriteTimeout)
+}
+
+// TCPSink holds the configuration of a newline-delimited TCP log output.
+type TCPSink struct {
+	Address      string         `description:"Address of the server receiving the logs." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	TLS          *ClientTLS     `description:"Enables TLS, with the given configuration." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	WriteTimeout types.Duration `description:"Timeout of the writes to the server." json:"writeTimeout,omitempty" toml:"writeTimeout,omitempty" yaml:"writeTimeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (t *TCPSink) SetDefaults() {
+	t.WriteTimeout = types.Duration(log.DefaultSinkWriteTimeout)
+}
+
+// HTTPSink holds the configuration of a batched HTTP log output.
+type HTTPSink struct {
+	URL           string            `description:"URL the batches of logs are posted to." json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty"`
+	Format        string            `description:"Format of the batches: ndjson | loki | elasticsearch" json:"format,omitempty" toml:"format,omitempty" yaml:"format,omitempty" export:"true"`
+	Headers       map[string]string `description:"Headers sent with the requests." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
+	Labels        map[string]string `description:"Labels of the Loki stream. Defaults to job=traefik." json:"labels,omitempty" toml:"labels,omitempty" yaml:"labels,omitempty" export:"true"`
+	Index         string            `description:"Elasticsearch index, when not part of the URL." json:"index,omitempty" toml:"index,omitempty" yaml:"index,omitempty" export:"true"`
+	BatchSize     int               `description:"Maximum number of log lines sent in a request." json:"batchSize,omitempty" toml:"batchSize,omitempty" yaml:"batchSize,omitempty" export:"true"`
+	FlushInterval types.Duration    `description:"Maximum duration a log line waits before being sent." json:"flushInterval,omitempty" toml:"flushInterval,omitempty" yaml:"flushInterval,omitempty" export:"true"`
+	Timeout       types.Duration    `description:"Timeout of the requests." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+	TLS           *ClientTLS        `description:"TLS configuration." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (h *HTTPSink) SetDefaults() {
+	h.Format = log.HTTPFormatNDJSON
+	h.BatchSize = 100
+	h.FlushInterval = types.Duration(time.Second)
+	h.Timeout = types.Duration(10 * time.Second)
+}
+
 // AccessLogFilters holds filters configuration.
 type AccessLogFilters struct {
 	StatusCodes   []string       `description:"Keep access logs with status codes in the specified range." json:"statusCodes,omitempty" toml:"statusCodes,omitempty" yaml:"statusCodes,omitempty" export:"true"`
@@ -1,9 +1,11 @@
 package udp
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net"
+	"os"
 	"sync"
 	"time"
 )
@@ -16,7 +18,7 @@ var errClosedListener = errors.New("udp: listener closed")
 
 // Listener augments a session-oriented Listener over a UDP PacketConn.
 type Listener struct {
-	pConn *net.UDPConn
+	pConn net.PacketConn
 
 	mu    sync.RWMutex
 	conns map[string]*Conn
@@ -42,6 +44,16 @@ func Listen(network string, laddr *net.UDPAddr, timeout time.Duration) (*Listene
 		return nil, err
 	}
 
+	return ListenPacketConn(conn, timeout)
+}
+
+// ListenPacketConn creates a new listener over the given packet connection,
+// e.g. a socket inherited from another process.
+func ListenPacketConn(conn net.PacketConn, timeout time.Duration) (*Listener, error) {
+	if timeout <= 0 {
+		return nil, errors.New("timeout should be greater than zero")
+	}
+
 	l := &Listener{
 		pConn:     conn,
 		acceptCh:  make(chan *Conn),
@@ -55,6 +67,16 @@ func Listen(network string, laddr *net.UDPAddr, timeout time.Duration) (*Listene
 	return l, nil
 }
 
+// File returns a copy of the socket file of the listener.
+func (l *Listener) File() (*os.File, error) {
+	fileConn, ok := l.pConn.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, errors.New("udp: unsupported packet connection type")
+	}
+
+	return fileConn.File()
+}
+
 // Accept waits for and returns the next connection to the listener.
 func (l *Listener) Accept() (*Conn, error) {
 	c := <-l.acceptCh
@@ -140,7 +162,7 @@ func (l *Listener) readLoop() {
 		if err != nil {
 			return
 		}
-		conn, err := l.getConn(raddr)
+		conn, err := l.getConn(raddr, buf[:n])
 		if err != nil {
 			continue
 		}
@@ -153,8 +175,8 @@ func (l *Listener) readLoop() {
 }
 
 // getConn returns the ongoing session with raddr if it exists, or creates a new
-// one otherwise.
-func (l *Listener) getConn(raddr net.Addr) (*Conn, error) {
+// one otherwise, starting with the given packet payload.
+func (l *Listener) getConn(raddr net.Addr, payload []byte) (*Conn, error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -166,7 +188,7 @@ func (l *Listener) getConn(raddr net.Addr) (*Conn, error) {
 	if !l.accepting {
 		return nil, errClosedListener
 	}
-	conn = l.newConn(raddr)
+	conn = l.newConn(raddr, payload)
 	l.conns[raddr.String()] = conn
 	l.acceptCh <- conn
 	go conn.readLoop()
@@ -174,15 +196,16 @@ func (l *Listener) getConn(raddr net.Addr) (*Conn, error) {
 	return conn, nil
 }
 
-func (l *Listener) newConn(rAddr net.Addr) *Conn {
+func (l *Listener) newConn(rAddr net.Addr, payload []byte) *Conn {
 	return &Conn{
-		listener:  l,
-		rAddr:     rAddr,
-		receiveCh: make(chan []byte),
-		readCh:    make(chan []byte),
-		sizeCh:    make(chan int),
-		doneCh:    make(chan struct{}),
-		timeout:   l.timeout,
+		listener:       l,
+		rAddr:          rAddr,
+		initialPayload: payload,
+		receiveCh:      make(chan []byte),
+		readCh:         make(chan []byte),
+		sizeCh:         make(chan int),
+		doneCh:         make(chan struct{}),
+		timeout:        l.timeout,
 	}
 }
 
@@ -190,6 +213,8 @@ func (l *Listener) newConn(rAddr net.Addr) *Conn {
 type Conn struct {
 	listener *Listener
 	rAddr    net.Addr
+	// initialPayload is the payload of the packet which started the session.
+	initialPayload []byte
 
 	receiveCh chan []byte // to receive the data from the listener's readLoop
 	readCh    chan []byte // to receive the buffer into which we should Read
@@ -198,6 +223,12 @@ type Conn struct {
 
 	muActivity   sync.RWMutex
 	lastActivity time.Time // the last time the session saw either read or write activity
+	bytesRead    int64     // the number of bytes read from the client
+	bytesWritten int64     // the number of bytes written to the client
+	expired      bool      // whether the session was closed because it was idle for too long
+
+	// ctx carries the values attached to the session by the handlers.
+	ctx context.Context
 
 	timeout  time.Duration // for timeouts
 	doneOnce sync.Once
@@ -222,7 +253,7 @@ func (c *Conn) readLoop() {
 				deadline := c.lastActivity.Add(c.timeout)
 				c.muActivity.RUnlock()
 				if time.Now().After(deadline) {
-					c.Close()
+					c.expire()
 					return
 				}
 				continue
@@ -242,7 +273,7 @@ func (c *Conn) readLoop() {
 			deadline := c.lastActivity.Add(c.timeout)
 			c.muActivity.RUnlock()
 			if time.Now().After(deadline) {
-				c.Close()
+				c.expire()
 				return
 			}
 		}
@@ -256,6 +287,7 @@ func (c *Conn) Read(p []byte) (int, error) {
 		n := <-c.sizeCh
 		c.muActivity.Lock()
 		c.lastActivity = time.Now()
+		c.bytesRead += int64(n)
 		c.muActivity.Unlock()
 		return n, nil
 	case <-c.doneCh:
@@ -273,7 +305,69 @@ func (c *Conn) Write(p []byte) (n int, err error) {
 	c.muActivity.Lock()
 	c.lastActivity = time.Now()
 	c.muActivity.Unlock()
-	return l.pConn.WriteTo(p, c.rAddr)
+
+	n, err = l.pConn.WriteTo(p, c.rAddr)
+
+	c.muActivity.Lock()
+	c.bytesWritten += int64(n)
+	c.muActivity.Unlock()
+
+	return n, err
+}
+
+// RemoteAddr returns the remote network address of the session.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.rAddr
+}
+
+// InitialPayload returns the payload of the first packet of the session,
+// which is still to be read from the Conn.
+// It must not be modified.
+func (c *Conn) InitialPayload() []byte {
+	return c.initialPayload
+}
+
+// Context returns the context of the session, carrying the values attached to it by the handlers.
+func (c *Conn) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// SetContext replaces the context of the session.
+// It must only be called by a handler before handing the session over to the next one.
+func (c *Conn) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// BytesRead returns the number of bytes read from the client during the session.
+func (c *Conn) BytesRead() int64 {
+	c.muActivity.RLock()
+	defer c.muActivity.RUnlock()
+	return c.bytesRead
+}
+
+// BytesWritten returns the number of bytes written to the client during the session.
+func (c *Conn) BytesWritten() int64 {
+	c.muActivity.RLock()
+	defer c.muActivity.RUnlock()
+	return c.bytesWritten
+}
+
+// Expired reports whether the session was closed because it was idle for longer than the timeout.
+func (c *Conn) Expired() bool {
+	c.muActivity.RLock()
+	defer c.muActivity.RUnlock()
+	return c.expired
+}
+
+func (c *Conn) expire() {
+	c.muActivity.Lock()
+	c.expired = true
+	c.muActivity.Unlock()
+
+	c.Close()
 }
 
 func (c *Conn) close() {
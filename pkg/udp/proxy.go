@@ -3,19 +3,23 @@ package udp
 import (
 	"io"
 	"net"
+	"time"
 
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 )
 
 // Proxy is a reverse-proxy implementation of the Handler interface.
 type Proxy struct {
 	// TODO: maybe optimize by pre-resolving it at proxy creation time
-	target string
+	target  string
+	metrics *metrics.ConnMetrics
 }
 
 // NewProxy creates a new Proxy.
-func NewProxy(address string) (*Proxy, error) {
-	return &Proxy{target: address}, nil
+// The given connMetrics, if not nil, records the sessions forwarded by the proxy.
+func NewProxy(address string, connMetrics *metrics.ConnMetrics) (*Proxy, error) {
+	return &Proxy{target: address, metrics: connMetrics}, nil
 }
 
 // ServeUDP implements the Handler interface.
@@ -27,6 +31,7 @@ func (p *Proxy) ServeUDP(conn *Conn) {
 
 	connBackend, err := net.Dial("udp", p.target)
 	if err != nil {
+		p.metrics.DialFailed(p.target)
 		log.Errorf("Error while connecting to backend: %v", err)
 		return
 	}
@@ -34,9 +39,12 @@ func (p *Proxy) ServeUDP(conn *Conn) {
 	// maybe not needed, but just in case
 	defer connBackend.Close()
 
+	p.metrics.Opened()
+	defer p.metrics.Closed(time.Now())
+
 	errChan := make(chan error)
-	go p.connCopy(conn, connBackend, errChan)
-	go p.connCopy(connBackend, conn, errChan)
+	go p.connCopy(conn, connBackend, errChan, p.metrics.SentWriter)
+	go p.connCopy(connBackend, conn, errChan, p.metrics.ReceivedWriter)
 
 	err = <-errChan
 	if err != nil {
@@ -46,8 +54,9 @@ func (p *Proxy) ServeUDP(conn *Conn) {
 	<-errChan
 }
 
-func (p Proxy) connCopy(dst io.WriteCloser, src io.Reader, errCh chan error) {
-	_, err := io.Copy(dst, src)
+// connCopy copies src to dst, through the writer returned by count to record the copied bytes, and reports to errCh.
+func (p Proxy) connCopy(dst io.WriteCloser, src io.Reader, errCh chan error, count func(io.Writer) io.Writer) {
+	_, err := io.Copy(count(dst), src)
 	errCh <- err
 
 	if err := dst.Close(); err != nil {
@@ -21,7 +21,7 @@ func TestUDPProxy(t *testing.T) {
 		}
 	}))
 
-	proxy, err := NewProxy(backendAddr)
+	proxy, err := NewProxy(backendAddr, nil)
 	require.NoError(t, err)
 
 	proxyAddr := ":8080"
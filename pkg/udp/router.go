@@ -0,0 +1,57 @@
+package udp
+
+import (
+	"net"
+	"sort"
+)
+
+// MatcherFunc reports whether a session matches a route,
+// given the address of the client and the payload of the first packet of the session.
+type MatcherFunc func(remoteAddr net.Addr, payload []byte) bool
+
+type route struct {
+	matcher  MatcherFunc
+	priority int
+	handler  Handler
+}
+
+// Router routes the UDP sessions to the handler of the first route they match,
+// when they are created.
+type Router struct {
+	routes   []route
+	catchAll Handler
+}
+
+// AddRoute adds a route to the router.
+// The routes are evaluated in decreasing priority order,
+// and in the order they were added for the same priority.
+func (r *Router) AddRoute(matcher MatcherFunc, priority int, handler Handler) {
+	r.routes = append(r.routes, route{matcher: matcher, priority: priority, handler: handler})
+
+	sort.SliceStable(r.routes, func(i, j int) bool {
+		return r.routes[i].priority > r.routes[j].priority
+	})
+}
+
+// AddCatchAll sets the handler of the sessions matching none of the routes.
+func (r *Router) AddCatchAll(handler Handler) {
+	r.catchAll = handler
+}
+
+// ServeUDP routes the session to the handler of the first route it matches.
+// The session is closed if it matches none of the routes, and there is no catch-all handler.
+func (r *Router) ServeUDP(conn *Conn) {
+	for _, rt := range r.routes {
+		if rt.matcher(conn.RemoteAddr(), conn.InitialPayload()) {
+			rt.handler.ServeUDP(conn)
+			return
+		}
+	}
+
+	if r.catchAll != nil {
+		r.catchAll.ServeUDP(conn)
+		return
+	}
+
+	conn.Close()
+}
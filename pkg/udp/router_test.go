@@ -0,0 +1,147 @@
+package udp
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter(t *testing.T) {
+	newHandler := func(name string) Handler {
+		return HandlerFunc(func(conn *Conn) {
+			defer conn.Close()
+
+			b := make([]byte, 2048)
+			n, err := conn.Read(b)
+			if err != nil {
+				return
+			}
+
+			_, _ = conn.Write(append([]byte(name+":"), b[:n]...))
+		})
+	}
+
+	prefix := func(p string) MatcherFunc {
+		return func(_ net.Addr, payload []byte) bool {
+			return bytes.HasPrefix(payload, []byte(p))
+		}
+	}
+
+	testCases := []struct {
+		desc     string
+		catchAll bool
+		payload  string
+		expected string
+	}{
+		{
+			desc:     "Matching route",
+			payload:  "foo",
+			expected: "foo:foo",
+		},
+		{
+			desc:     "Matching routes, the one with the highest priority is used",
+			payload:  "foobar",
+			expected: "foobar:foobar",
+		},
+		{
+			desc:     "No matching route, catch-all",
+			catchAll: true,
+			payload:  "bar",
+			expected: "catchAll:bar",
+		},
+		{
+			desc:    "No matching route, without catch-all",
+			payload: "bar",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			router := &Router{}
+			router.AddRoute(prefix("foo"), 3, newHandler("foo"))
+			router.AddRoute(prefix("foobar"), 6, newHandler("foobar"))
+			if test.catchAll {
+				router.AddCatchAll(newHandler("catchAll"))
+			}
+
+			addr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+			require.NoError(t, err)
+
+			ln, err := Listen("udp", addr, 3*time.Second)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = ln.Close() })
+
+			go func() {
+				for {
+					conn, err := ln.Accept()
+					if errors.Is(err, errClosedListener) {
+						return
+					}
+
+					go router.ServeUDP(conn)
+				}
+			}()
+
+			udpConn, err := net.Dial("udp", ln.Addr().String())
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = udpConn.Close() })
+
+			_, err = udpConn.Write([]byte(test.payload))
+			require.NoError(t, err)
+
+			require.NoError(t, udpConn.SetReadDeadline(time.Now().Add(time.Second)))
+
+			b := make([]byte, 2048)
+			n, err := udpConn.Read(b)
+			if test.expected == "" {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(b[:n]))
+		})
+	}
+}
+
+func TestConn_InitialPayload(t *testing.T) {
+	addr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ln, err := Listen("udp", addr, 3*time.Second)
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	udpConn, err := net.Dial("udp", ln.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = udpConn.Close() }()
+
+	_, err = udpConn.Write([]byte("first"))
+	require.NoError(t, err)
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(conn.InitialPayload()))
+
+	_, err = udpConn.Write([]byte("second"))
+	require.NoError(t, err)
+
+	// The initial payload is still read from the session.
+	b := make([]byte, 2048)
+	n, err := conn.Read(b)
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(b[:n]))
+
+	n, err = conn.Read(b)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(b[:n]))
+
+	assert.Equal(t, "first", string(conn.InitialPayload()))
+}